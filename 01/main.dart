@@ -57,6 +57,8 @@ void solve(Scanner sc) {
 }
 
 List<int> solveTest(int R, int B) {
+  // Периметр 2*W + 2*H - 4 чётен: при нечётном R панели нет
+  if (R % 2 != 0) return [0, 0];
   int sumWH = (R + 4) ~/ 2;
 
   for (int d = 1; d * d <= B; d++) {
@@ -1,45 +1,165 @@
 package main
 
 import (
-	"bufio"
-	"fmt"
+	"errors"
+	"math/bits"
 	"os"
-	"strconv"
-	"strings"
+
+	"yandex-2025-winter/lib/fastio"
+	"yandex-2025-winter/lib/numtheory"
 )
 
 func main() {
-	reader := bufio.NewReaderSize(os.Stdin, 1<<20)
-	writer := bufio.NewWriterSize(os.Stdout, 1<<20)
+	reader := fastio.NewReader(os.Stdin)
+	writer := fastio.NewWriter(os.Stdout)
 	defer writer.Flush()
 
-	line, _ := reader.ReadString('\n')
-	parts := strings.Fields(strings.TrimSpace(line))
-	R, _ := strconv.Atoi(parts[0])
-	B, _ := strconv.Atoi(parts[1])
+	R, B := reader.Int(), reader.Int()
+
+	// По условию ответ есть; на входе без ответа, как в Rust и Dart, — «0 0»
+	W, H, err := solve(R, B)
+	if err != nil {
+		W, H = 0, 0
+	}
+	writer.Ints([]int{W, H})
+}
+
+// errNoPanel — панели (или коробки) с таким числом плиток нет
+var errNoPanel = errors.New("нет размеров с таким числом красных и синих плиток")
+
+// Panel — размеры прямоугольной панели, W ≥ H
+type Panel struct {
+	W, H int
+}
 
-	W, H := solve(R, B)
-	writer.WriteString(fmt.Sprintf("%d %d\n", W, H))
+// Box — размеры коробки из кубиков, W ≥ H ≥ D
+type Box struct {
+	W, H, D int
 }
 
 // solve находит размеры панели W и H (W >= H) по количеству красных R и синих B плиток
 // R = 2*W + 2*H - 4, B = (W-2) * (H-2)
-func solve(R, B int) (int, int) {
-	sum := (R + 4) / 2
+func solve(R, B int) (int, int, error) {
+	ps, err := panels(R, B, 1)
+	if err != nil {
+		return 0, 0, err
+	}
+	return ps[0].W, ps[0].H, nil
+}
 
-	for d := 1; d*d <= B; d++ {
-		if B%d != 0 {
-			continue
+// panels возвращает все панели W ≥ H по возрастанию H, у которых красная
+// рамка толщины k ≥ 1 из R плиток окружает B синих:
+// B = max(0, W−2k)·max(0, H−2k), R = W·H − B. R, B ≤ 10^18
+//
+// При B ≥ 1 стороны ядра a = W−2k и b = H−2k — делители B с
+// a + b = (R − 4k²)/(2k), и решение не больше одного. При B = 0 панель вся
+// красная: W·H = R и H ≤ 2k, решений может быть несколько
+func panels(R, B, k int) ([]Panel, error) {
+	if R < 1 || B < 0 || k < 1 {
+		return nil, errNoPanel
+	}
+	var res []Panel
+	if B == 0 {
+		for _, h := range numtheory.Divisors(R) {
+			if h > R/h || h > 2*k {
+				break
+			}
+			res = append(res, Panel{R / h, h})
 		}
+	} else if k <= numtheory.IntSqrt(R)/2 && (R-4*k*k)%(2*k) == 0 { // 4k² ≤ R без переполнения
+		sum := (R - 4*k*k) / (2 * k)
+		for _, b := range numtheory.Divisors(B) {
+			if b > B/b {
+				break
+			}
+			if B/b == sum-b {
+				res = append(res, Panel{B/b + 2*k, b + 2*k})
+			}
+		}
+	}
+	if len(res) == 0 {
+		return nil, errNoPanel
+	}
+	return res, nil
+}
 
-		// Проверяем оба варианта: (W-2, H-2) = (d, B/d) и (B/d, d)
-		for _, w := range []int{B/d + 2, d + 2} {
-			h := sum - w
-			if (w-2)*(h-2) == B && w >= h {
-				return w, h
+// boxes возвращает все коробки W ≥ H ≥ D по возрастанию (D, H), у которых
+// красная оболочка в один кубик из R кубиков окружает синее ядро из B:
+// B = max(0, W−2)·max(0, H−2)·max(0, D−2), R = W·H·D − B. R, B ≤ 10^18
+//
+// При B ≥ 1 перебираются делители c = D−2 числа B с c³ ≤ B; для
+// остальных сторон ядра a ≥ b ≥ c известны произведение ab = B/c и из
+// R = 2(ab + bc + ca) + 4(a + b + c) + 8 сумма a + b, а b находится
+// двоичным поиском
+func boxes(R, B int) ([]Box, error) {
+	if R < 1 || B < 0 {
+		return nil, errNoPanel
+	}
+	var res []Box
+	if B == 0 {
+		// Ядро пусто, только если наименьшая сторона D ≤ 2
+		for d := 1; d <= 2; d++ {
+			if R%d != 0 {
+				continue
+			}
+			q := R / d
+			for _, h := range numtheory.Divisors(q) {
+				if h > q/h {
+					break
+				}
+				if h >= d {
+					res = append(res, Box{q / h, h, d})
+				}
+			}
+		}
+	} else {
+		for _, c := range numtheory.Divisors(B) {
+			if c > B/c/c {
+				break
+			}
+			p := B / c
+			// 2p + 2c·(a+b) + 4(a+b) + 4c + 8 = R; 2p ≤ 2·10^18 без переполнения
+			rest := R - 2*p - 4*c - 8
+			if rest < 0 || rest%(2*c+4) != 0 {
+				continue
+			}
+			if a, b, ok := splitProduct(p, rest/(2*c+4), c); ok {
+				res = append(res, Box{a + 2, b + 2, c + 2})
 			}
 		}
 	}
+	if len(res) == 0 {
+		return nil, errNoPanel
+	}
+	return res, nil
+}
 
-	return 0, 0
+// splitProduct находит a ≥ b ≥ lo с a·b = p и a + b = s. На [lo, s/2]
+// произведение b·(s−b) возрастает, так что b ищется двоичным поиском;
+// произведение считается в 128 битах
+func splitProduct(p, s, lo int) (a, b int, ok bool) {
+	// cmp сравнивает b·(s−b) с p
+	cmp := func(b int) int {
+		hi, prod := bits.Mul64(uint64(b), uint64(s-b))
+		switch {
+		case hi > 0 || prod > uint64(p):
+			return 1
+		case prod < uint64(p):
+			return -1
+		}
+		return 0
+	}
+	l, r := lo, s/2
+	for l < r {
+		m := l + (r-l)/2
+		if cmp(m) < 0 {
+			l = m + 1
+		} else {
+			r = m
+		}
+	}
+	if l > s/2 || cmp(l) != 0 {
+		return 0, 0, false
+	}
+	return s - l, l, true
 }
@@ -20,6 +20,10 @@ fn main() {
 // solve находит размеры панели W и H (W >= H) по количеству красных R и синих B плиток
 // R = 2*W + 2*H - 4, B = (W-2) * (H-2)
 fn solve(r: i32, b: i32) -> (i32, i32) {
+    // Периметр 2*W + 2*H - 4 чётен: при нечётном R панели нет
+    if r % 2 != 0 {
+        return (0, 0);
+    }
     let sum = (r + 4) / 2;
 
     let mut d = 1;
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"errors"
 	"runtime"
+	"slices"
 	"testing"
 	"time"
 )
@@ -39,7 +41,10 @@ func TestSolve(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			W, H := solve(tt.R, tt.B)
+			W, H, err := solve(tt.R, tt.B)
+			if err != nil {
+				t.Fatalf("solve(%d, %d) не нашло решение: %v", tt.R, tt.B, err)
+			}
 			if W != tt.expectedW || H != tt.expectedH {
 				t.Errorf("solve(%d, %d) = (%d, %d), ожидалось (%d, %d)",
 					tt.R, tt.B, W, H, tt.expectedW, tt.expectedH)
@@ -126,11 +131,11 @@ func TestSolveConstraints(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			W, H := solve(tt.R, tt.B)
+			W, H, err := solve(tt.R, tt.B)
 
 			// Проверяем, что решение найдено
-			if W == 0 || H == 0 {
-				t.Errorf("solve(%d, %d) не нашло решение", tt.R, tt.B)
+			if err != nil {
+				t.Errorf("solve(%d, %d) не нашло решение: %v", tt.R, tt.B, err)
 				return
 			}
 
@@ -214,10 +219,10 @@ func TestSolveEdgeCases(t *testing.T) {
 				return
 			}
 
-			W, H := solve(tt.R, tt.B)
+			W, H, err := solve(tt.R, tt.B)
 
-			if W == 0 || H == 0 {
-				t.Errorf("solve(%d, %d) не нашло решение", tt.R, tt.B)
+			if err != nil {
+				t.Errorf("solve(%d, %d) не нашло решение: %v", tt.R, tt.B, err)
 				return
 			}
 
@@ -284,11 +289,11 @@ func TestSolveBoundaryValues(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			W, H := solve(tt.R, tt.B)
+			W, H, err := solve(tt.R, tt.B)
 
 			// Проверяем, что решение найдено
-			if W == 0 || H == 0 {
-				t.Errorf("solve(%d, %d) не нашло решение", tt.R, tt.B)
+			if err != nil {
+				t.Errorf("solve(%d, %d) не нашло решение: %v", tt.R, tt.B, err)
 				return
 			}
 
@@ -335,6 +340,126 @@ func TestSolveBoundaryValues(t *testing.T) {
 	}
 }
 
+// TestPanels сравнивает panels с перебором всех W ≥ H для рамок толщины 1–3
+func TestPanels(t *testing.T) {
+	type key struct{ R, B int }
+	for k := 1; k <= 3; k++ {
+		want := map[key][]Panel{}
+		for h := 1; h <= 40; h++ {
+			for w := h; w <= 40; w++ {
+				B := max(0, w-2*k) * max(0, h-2*k)
+				R := w*h - B
+				want[key{R, B}] = append(want[key{R, B}], Panel{w, h})
+			}
+		}
+		for R := 1; R <= 200; R++ {
+			for B := 0; B <= 200; B++ {
+				got, err := panels(R, B, k)
+				exp := want[key{R, B}]
+				if err != nil {
+					if len(exp) > 0 || err != errNoPanel || got != nil {
+						t.Fatalf("panels(%d, %d, %d) = %v, %v; ожидалось %v", R, B, k, got, err, exp)
+					}
+					continue
+				}
+				// Перебор ограничен сторонами до 40: сверяем только такие ответы
+				var small []Panel
+				for _, p := range got {
+					if p.W <= 40 {
+						small = append(small, p)
+					}
+				}
+				if !slices.Equal(small, exp) {
+					t.Fatalf("panels(%d, %d, %d) = %v; ожидалось %v", R, B, k, got, exp)
+				}
+			}
+		}
+	}
+}
+
+// TestPanelsNotFound проверяет, что отсутствие решения — ошибка, а не (0, 0)
+func TestPanelsNotFound(t *testing.T) {
+	for _, tt := range []struct{ R, B, k int }{
+		{9, 1, 1},  // нечётное R
+		{10, 1, 1}, // 3×3 с рамкой даёт только R = 8
+		{8, 1, 2},  // рамка толщины 2 вокруг одной плитки — это R = 24
+		{0, 1, 1},
+		{8, -1, 1},
+		{8, 1, 0},
+	} {
+		if ps, err := panels(tt.R, tt.B, tt.k); !errors.Is(err, errNoPanel) {
+			t.Errorf("panels(%d, %d, %d) = %v, %v; ожидалась errNoPanel", tt.R, tt.B, tt.k, ps, err)
+		}
+	}
+	if _, _, err := solve(10, 1); !errors.Is(err, errNoPanel) {
+		t.Errorf("solve(10, 1): ошибка %v, ожидалась errNoPanel", err)
+	}
+}
+
+// TestBoxes сравнивает boxes с перебором всех W ≥ H ≥ D
+func TestBoxes(t *testing.T) {
+	type key struct{ R, B int }
+	want := map[key][]Box{}
+	const side = 30
+	for d := 1; d <= side; d++ {
+		for h := d; h <= side; h++ {
+			for w := h; w <= side; w++ {
+				B := max(0, w-2) * max(0, h-2) * max(0, d-2)
+				R := w*h*d - B
+				want[key{R, B}] = append(want[key{R, B}], Box{w, h, d})
+			}
+		}
+	}
+	for kb, exp := range want {
+		got, err := boxes(kb.R, kb.B)
+		if err != nil {
+			t.Fatalf("boxes(%d, %d): %v, ожидалось %v", kb.R, kb.B, err, exp)
+		}
+		// У ответа могут быть стороны больше side: сверяем только те, что в переборе
+		var small []Box
+		for _, b := range got {
+			if b.W <= side {
+				small = append(small, b)
+			}
+		}
+		if !slices.Equal(small, exp) {
+			t.Fatalf("boxes(%d, %d) = %v, ожидалось %v", kb.R, kb.B, got, exp)
+		}
+	}
+	if bs, err := boxes(26, 1); err != nil || !slices.Equal(bs, []Box{{3, 3, 3}}) {
+		t.Errorf("boxes(26, 1) = %v, %v; ожидалось [{3 3 3}]", bs, err)
+	}
+	if bs, err := boxes(27, 2); !errors.Is(err, errNoPanel) {
+		t.Errorf("boxes(27, 2) = %v, %v; ожидалась errNoPanel", bs, err)
+	}
+}
+
+// TestLarge проверяет входы до 10^18: стороны около 10^9 и 10^6
+func TestLarge(t *testing.T) {
+	W, H := 1_000_000_007, 999_999_937
+	ps, err := panels(W*H-(W-2)*(H-2), (W-2)*(H-2), 1)
+	if err != nil || !slices.Equal(ps, []Panel{{W, H}}) {
+		t.Errorf("панель %d×%d: получено %v, %v", W, H, ps, err)
+	}
+	k := 1000
+	ps, err = panels(W*H-(W-2*k)*(H-2*k), (W-2*k)*(H-2*k), k)
+	if err != nil || !slices.Equal(ps, []Panel{{W, H}}) {
+		t.Errorf("панель %d×%d с рамкой %d: получено %v, %v", W, H, k, ps, err)
+	}
+	// B = 10^18 с ~10^5 делителями и коробка 10^6+2 × 10^6+2 × 10^6+2
+	a := 1_000_000
+	bs, err := boxes((a+2)*(a+2)*(a+2)-a*a*a, a*a*a)
+	if err != nil || !slices.Contains(bs, Box{a + 2, a + 2, a + 2}) {
+		t.Errorf("куб %d: получено %v, %v", a+2, bs, err)
+	}
+	// Вытянутая коробка: ядро 10^18 × 1 × 1
+	B := 1_000_000_000_000_000_000
+	bs, err = boxes((B+2)*3*3-B, B)
+	if err != nil || !slices.Equal(bs, []Box{{B + 2, 3, 3}}) {
+		t.Errorf("коробка %d×3×3: получено %v, %v", B+2, bs, err)
+	}
+}
+
 // TestSolvePerformance проверяет ограничения времени выполнения (300 мс) и памяти (512 МБ)
 func TestSolvePerformance(t *testing.T) {
 	tests := []struct {
@@ -385,7 +510,7 @@ func TestSolvePerformance(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Измеряем время выполнения
 			start := time.Now()
-			W, H := solve(tt.R, tt.B)
+			W, H, err := solve(tt.R, tt.B)
 			elapsed := time.Since(start)
 
 			// Проверяем ограничение времени: 300 мс
@@ -396,8 +521,8 @@ func TestSolvePerformance(t *testing.T) {
 			}
 
 			// Проверяем, что решение найдено
-			if W == 0 || H == 0 {
-				t.Errorf("solve(%d, %d) не нашло решение", tt.R, tt.B)
+			if err != nil {
+				t.Errorf("solve(%d, %d) не нашло решение: %v", tt.R, tt.B, err)
 				return
 			}
 
@@ -464,7 +589,7 @@ func TestSolveMemoryUsage(t *testing.T) {
 			runtime.GC()
 			runtime.ReadMemStats(&m1)
 
-			W, H := solve(tt.R, tt.B)
+			W, H, err := solve(tt.R, tt.B)
 
 			// Измеряем память после выполнения
 			runtime.GC()
@@ -475,8 +600,8 @@ func TestSolveMemoryUsage(t *testing.T) {
 			maxMemory := 512 * 1024 * 1024 // 512 МБ в байтах
 
 			// Проверяем, что решение найдено
-			if W == 0 || H == 0 {
-				t.Errorf("solve(%d, %d) не нашло решение", tt.R, tt.B)
+			if err != nil {
+				t.Errorf("solve(%d, %d) не нашло решение: %v", tt.R, tt.B, err)
 				return
 			}
 
@@ -516,7 +641,7 @@ func BenchmarkSolve(b *testing.B) {
 	for _, bm := range benchmarks {
 		b.Run(bm.name, func(b *testing.B) {
 			for i := 0; i < b.N; i++ {
-				W, H := solve(bm.R, bm.B)
+				W, H, _ := solve(bm.R, bm.B)
 				_ = W
 				_ = H
 			}
@@ -1,57 +1,224 @@
 package main
 
 import (
-	"bufio"
-	"fmt"
+	"math/bits"
 	"os"
-	"strconv"
-	"strings"
+
+	"yandex-2025-winter/lib/fastio"
 )
 
 func main() {
-	reader := bufio.NewReaderSize(os.Stdin, 1<<16)
-	writer := bufio.NewWriterSize(os.Stdout, 1<<16)
+	reader := fastio.NewReader(os.Stdin)
+	writer := fastio.NewWriter(os.Stdout)
 	defer writer.Flush()
 
 	// Читаем 10 чисел
 	nums := make([]int, 10)
 	for i := 0; i < 10; i++ {
-		line, _ := reader.ReadString('\n')
-		nums[i], _ = strconv.Atoi(strings.TrimSpace(line))
+		nums[i] = reader.Int()
 	}
 
 	result := solve(nums)
-	writer.WriteString(fmt.Sprintf("%d\n", result))
+	writer.Int(result)
+	writer.WriteByte('\n')
 }
 
+// target — сумма, к которой калибруется альтиметр в задаче
+const target = 100
+
 // solve находит сумму подмножества, ближайшую к 100
 // При равном расстоянии выбирает большую сумму
 func solve(nums []int) int {
-	const target = 100
-	bestSum := 0
-	bestDist := target // расстояние от 0 до 100
-
-	// Перебираем все 2^10 = 1024 подмножества
-	for mask := 0; mask < (1 << 10); mask++ {
-		sum := 0
-		for i := 0; i < 10; i++ {
-			if mask&(1<<i) != 0 {
-				sum += nums[i]
+	return calibrate(nums, target).Sum
+}
+
+// Calibration — лучшая сумма и индексы шкал, которые её дают, по возрастанию
+type Calibration struct {
+	Sum     int
+	Indices []int
+}
+
+const (
+	// maxBitsetSum — наибольшая сумма весов для битсета: first занимает
+	// 4·2^22 байт = 16 МБ, проход — n·2^16 слов
+	maxBitsetSum = 1 << 22
+	// maxMeetInMiddle — наибольшее n для встречи посередине
+	maxMeetInMiddle = 50
+	// halfBits — наибольшая половина: отсортированные суммы 2^24 чисел —
+	// 128 МБ на половину
+	halfBits = 24
+)
+
+// calibrate выбирает подмножество weights с суммой, ближайшей к target; при
+// равном расстоянии — с большей суммой. Неотрицательные веса с суммой до
+// 2^22 решаются битсетом при любом n, остальные — встречей посередине при
+// n ≤ 50 (веса любого знака). Иначе паника
+func calibrate(weights []int, target int) Calibration {
+	total, negative := 0, false
+	for _, w := range weights {
+		total += max(w, -w)
+		negative = negative || w < 0
+	}
+	switch {
+	case !negative && total <= maxBitsetSum:
+		return bitsetCalibrate(weights, total, target)
+	case len(weights) <= maxMeetInMiddle:
+		return meetInMiddle(weights, target)
+	}
+	panic("calibrate: больше 50 весов и сумма не для битсета")
+}
+
+// better сообщает, что сумма s лучше best: ближе к target, а при равном
+// расстоянии — больше
+func better(s, best, target int) bool {
+	d, bd := abs(s-target), abs(best-target)
+	return d < bd || d == bd && s > best
+}
+
+// bitsetCalibrate — рюкзак на битсете достижимых сумм 0..total. first[s] —
+// вес, на котором сумма s стала достижимой: из s − weights[first[s]] она
+// получена раньше, поэтому подмножество восстанавливается по убыванию
+// индексов
+func bitsetCalibrate(weights []int, total, target int) Calibration {
+	reach := make([]uint64, total/64+1)
+	reach[0] = 1
+	first := make([]int32, total+1)
+	for i, w := range weights {
+		if w == 0 {
+			continue // сумму не меняет
+		}
+		q, r := w/64, uint(w%64)
+		// Сверху вниз, чтобы слова-источники ещё не включали текущий вес
+		for j := len(reach) - 1; j >= q; j-- {
+			v := reach[j-q] << r
+			if r > 0 && j-q > 0 {
+				v |= reach[j-q-1] >> (64 - r)
+			}
+			for fresh := v &^ reach[j]; fresh != 0; fresh &= fresh - 1 {
+				first[j*64+bits.TrailingZeros64(fresh)] = int32(i)
+			}
+			reach[j] |= v
+		}
+	}
+
+	best := 0
+	for s := 1; s <= total; s++ {
+		if reach[s/64]>>(s%64)&1 == 1 && better(s, best, target) {
+			best = s
+		}
+	}
+	res := Calibration{Sum: best}
+	for s := best; s > 0; s -= weights[first[s]] {
+		res.Indices = append(res.Indices, int(first[s]))
+	}
+	for i, j := 0, len(res.Indices)-1; i < j; i, j = i+1, j-1 {
+		res.Indices[i], res.Indices[j] = res.Indices[j], res.Indices[i]
+	}
+	return res
+}
+
+// meetInMiddle делит веса на два блока до 2^24 сумм и остаток (не больше
+// двух весов при n ≤ 50). Для каждого подмножества остатка лучшая пара
+// сумм блоков ищется двумя указателями по отсортированным суммам; маски
+// блоков потом восстанавливаются перебором по найденным суммам
+func meetInMiddle(weights []int, target int) Calibration {
+	n := len(weights)
+	h := min(halfBits, (n+1)/2)
+	r := min(halfBits, n-h)
+	left, right, rest := weights[:h], weights[h:h+r], weights[h+r:]
+	ls, rs := sortedSums(left), sortedSums(right)
+
+	first := true
+	var best, bestL, bestR, bestRest int
+	for mask := range 1 << len(rest) {
+		o := subsetSum(rest, mask)
+		t := target - o
+		for i, j := 0, len(rs)-1; i < len(ls) && j >= 0; {
+			s := ls[i] + rs[j]
+			if first || better(s+o, best, target) {
+				first = false
+				best, bestL, bestR, bestRest = s+o, ls[i], rs[j], mask
+			}
+			if s < t {
+				i++
+			} else if s > t {
+				j--
+			} else {
+				break // точное попадание: лучше для этого o не будет
 			}
 		}
+	}
 
-		dist := abs(sum - target)
+	res := Calibration{Sum: best}
+	for _, part := range []struct {
+		offset, mask int
+	}{
+		{0, maskWithSum(left, bestL)},
+		{h, maskWithSum(right, bestR)},
+		{h + r, bestRest},
+	} {
+		for m := part.mask; m != 0; m &= m - 1 {
+			res.Indices = append(res.Indices, part.offset+bits.TrailingZeros(uint(m)))
+		}
+	}
+	return res
+}
 
-		// Выбираем лучшую сумму:
-		// - меньшее расстояние до 100
-		// - при равном расстоянии — большую сумму
-		if dist < bestDist || (dist == bestDist && sum > bestSum) {
-			bestSum = sum
-			bestDist = dist
+// sortedSums возвращает суммы всех 2^len(w) подмножеств w по возрастанию.
+// Добавление веса x сливает суммы A с A + x с конца одного буфера: запись
+// идёт в позицию i + j + 1, правее обоих указателей, и не затирает
+// непрочитанное
+func sortedSums(w []int) []int {
+	sums := make([]int, 1<<len(w))
+	size := 1
+	for _, x := range w {
+		i, j := size-1, size-1 // A[i] и A[j] + x
+		k := 2*size - 1
+		// Пока обе части не кончились, без ветвлений: на случайных суммах
+		// сравнение непредсказуемо
+		for i >= 0 && j >= 0 {
+			a, b := sums[i], sums[j]+x
+			v, takeA := b, 0
+			if a > b {
+				v, takeA = a, 1
+			}
+			sums[k] = v
+			k--
+			i -= takeA
+			j -= 1 - takeA
 		}
+		// Остаток A уже на месте; остаток A + x — в позициях 0..j
+		for ; j >= 0; j-- {
+			sums[j] += x
+		}
+		size *= 2
+	}
+	return sums
+}
+
+// subsetSum возвращает сумму весов w из маски
+func subsetSum(w []int, mask int) int {
+	s := 0
+	for m := mask; m != 0; m &= m - 1 {
+		s += w[bits.TrailingZeros(uint(m))]
 	}
+	return s
+}
 
-	return bestSum
+// maskWithSum находит маску подмножества w с суммой s перебором в порядке
+// кода Грея: соседние маски отличаются одним весом. Сумма s достижима
+func maskWithSum(w []int, s int) int {
+	mask, sum := 0, 0
+	for k := 1; sum != s; k++ {
+		b := bits.TrailingZeros(uint(k))
+		mask ^= 1 << b
+		if mask>>b&1 == 1 {
+			sum += w[b]
+		} else {
+			sum -= w[b]
+		}
+	}
+	return mask
 }
 
 func abs(x int) int {
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"math/rand"
+	"slices"
 	"testing"
 )
 
@@ -130,6 +132,152 @@ func TestAbs(t *testing.T) {
 	}
 }
 
+// bruteCalibrate перебирает все подмножества
+func bruteCalibrate(weights []int, target int) int {
+	best := 0
+	for mask := 0; mask < 1<<len(weights); mask++ {
+		if s := subsetSum(weights, mask); mask == 0 || better(s, best, target) {
+			best = s
+		}
+	}
+	return best
+}
+
+// checkIndices проверяет, что индексы возрастают и дают сумму c.Sum
+func checkIndices(t *testing.T, weights []int, c Calibration) {
+	t.Helper()
+	sum := 0
+	for k, i := range c.Indices {
+		if i < 0 || i >= len(weights) || k > 0 && i <= c.Indices[k-1] {
+			t.Fatalf("%v: индексы %v не возрастают или вне массива", weights, c.Indices)
+		}
+		sum += weights[i]
+	}
+	if sum != c.Sum {
+		t.Fatalf("%v: индексы %v дают %d, а не %d", weights, c.Indices, sum, c.Sum)
+	}
+}
+
+func TestCalibrate(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for iter := 0; iter < 2000; iter++ {
+		n := rng.Intn(15)
+		weights := make([]int, n)
+		limit := []int{3, 100, 1e5, 1e12}[rng.Intn(4)]
+		for i := range weights {
+			weights[i] = rng.Intn(limit + 1)
+			if iter%3 == 0 {
+				weights[i] -= limit / 2 // отрицательные — только встреча посередине
+			}
+		}
+		target := rng.Intn(limit*n/2+1) - limit/4
+		want := bruteCalibrate(weights, target)
+
+		c := calibrate(weights, target)
+		if c.Sum != want {
+			t.Fatalf("calibrate(%v, %d) = %d, ожидалось %d", weights, target, c.Sum, want)
+		}
+		checkIndices(t, weights, c)
+		// Встреча посередине и там, где выбран бы битсет
+		c = meetInMiddle(weights, target)
+		if c.Sum != want {
+			t.Fatalf("meetInMiddle(%v, %d) = %d, ожидалось %d", weights, target, c.Sum, want)
+		}
+		checkIndices(t, weights, c)
+	}
+}
+
+func TestSortedSums(t *testing.T) {
+	w := []int{5, -3, 5, 0, 7}
+	sums := sortedSums(w)
+	var want []int
+	for mask := 0; mask < 1<<len(w); mask++ {
+		want = append(want, subsetSum(w, mask))
+	}
+	slices.Sort(want)
+	if !slices.Equal(sums, want) {
+		t.Errorf("sortedSums(%v) = %v, ожидалось %v", w, sums, want)
+	}
+}
+
+// TestCalibrateLarge — 44 показания до 10^12 (встреча посередине, два
+// блока по 22 без остатка) и 1000 малых (битсет)
+func TestCalibrateLarge(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	weights := make([]int, 44)
+	for i := range weights {
+		weights[i] = 1 + rng.Intn(1e12)
+	}
+	// Цель — сумма случайного подмножества: достижима точно
+	target := 0
+	for i := range weights {
+		if rng.Intn(2) == 0 {
+			target += weights[i]
+		}
+	}
+	c := calibrate(weights, target)
+	if c.Sum != target {
+		t.Errorf("44 веса: сумма %d, ожидалась достижимая %d", c.Sum, target)
+	}
+	checkIndices(t, weights, c)
+
+	small := make([]int, 1000)
+	for i := range small {
+		small[i] = 1 + rng.Intn(4000)
+	}
+	c = calibrate(small, 1_500_001)
+	checkIndices(t, small, c)
+	if c.Sum != 1_500_001 {
+		t.Errorf("1000 весов до 4000: сумма %d, ожидалась 1500001", c.Sum)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("51 большой вес: ожидалась паника")
+		}
+	}()
+	calibrate(make([]int, 51), 0) // нули проходят битсетом
+	big := make([]int, 51)
+	for i := range big {
+		big[i] = 1e12
+	}
+	calibrate(big, 0)
+}
+
+// TestCalibrateRest — 49 и 50 весов: два блока по 24 и остаток из одного
+// или двух весов. Цель — сумма подмножества, в которое входит остаток;
+// веса случайны до ±5·10^16: 2^50 подмножеств на разброс сумм ~10^17,
+// так что другое подмножество с той же суммой практически невозможно и
+// индексы из трёх частей сверяются точно. Больше нельзя: разность суммы и
+// цели должна уложиться в int
+func TestCalibrateRest(t *testing.T) {
+	if testing.Short() {
+		t.Skip("два прохода встречи посередине по 2^24 — несколько секунд")
+	}
+	rng := rand.New(rand.NewSource(3))
+	for _, n := range []int{49, 50} {
+		weights := make([]int, n)
+		for i := range weights {
+			weights[i] = rng.Intn(1e17) - 5e16 // любого знака: не битсет
+		}
+		var want []int
+		target := 0
+		for i := range weights {
+			if i >= 2*halfBits || rng.Intn(2) == 0 {
+				want = append(want, i)
+				target += weights[i]
+			}
+		}
+		c := calibrate(weights, target)
+		if c.Sum != target {
+			t.Errorf("%d весов: сумма %d, ожидалась достижимая %d", n, c.Sum, target)
+		}
+		checkIndices(t, weights, c)
+		if got := slices.Sorted(slices.Values(c.Indices)); !slices.Equal(got, want) {
+			t.Errorf("%d весов: индексы %v, ожидались %v", n, got, want)
+		}
+	}
+}
+
 func BenchmarkSolve(b *testing.B) {
 	nums := []int{10, 20, 30, 40, 50, 60, 70, 80, 90, 100}
 	b.ResetTimer()
@@ -145,3 +293,14 @@ func BenchmarkSolveWorstCase(b *testing.B) {
 		solve(nums)
 	}
 }
+
+func BenchmarkCalibrate50(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	weights := make([]int, 50)
+	for i := range weights {
+		weights[i] = 1 + rng.Intn(1e12)
+	}
+	for b.Loop() {
+		calibrate(weights, 1e13+7)
+	}
+}
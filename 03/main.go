@@ -1,121 +1,345 @@
 package main
 
 import (
-	"bufio"
-	"fmt"
+	"iter"
+	"math"
+	"math/big"
+	"math/bits"
 	"os"
-	"sort"
-	"strconv"
-	"strings"
-)
+	"slices"
 
-const mod = 1000000007
+	"yandex-2025-winter/lib/fastio"
+	"yandex-2025-winter/lib/modint"
+)
 
 func main() {
-	reader := bufio.NewReaderSize(os.Stdin, 1<<20)
-	writer := bufio.NewWriterSize(os.Stdout, 1<<20)
+	reader := fastio.NewReader(os.Stdin)
+	writer := fastio.NewWriter(os.Stdout)
 	defer writer.Flush()
 
 	// Читаем M и N
-	line, _ := reader.ReadString('\n')
-	parts := strings.Fields(strings.TrimSpace(line))
-	M, _ := strconv.ParseInt(parts[0], 10, 64)
-	N, _ := strconv.Atoi(parts[1])
+	M := reader.Int64()
+	N := reader.Int()
 
-	// Читаем потребности групп
+	// Читаем потребности групп; числа могут быть в нескольких строках
 	W := make([]int64, N)
 	totalNeed := int64(0)
-
-	// Читаем числа, обрабатывая случай, когда они могут быть в нескольких строках
-	scanner := bufio.NewScanner(reader)
-	scanner.Split(bufio.ScanWords)
-
-	for i := 0; i < N && scanner.Scan(); i++ {
-		val, _ := strconv.ParseInt(scanner.Text(), 10, 64)
-		W[i] = val
-		totalNeed += val
+	for i := 0; i < N && reader.More(); i++ {
+		W[i] = reader.Int64()
+		totalNeed += W[i]
 	}
 
 	result := solve(M, W, totalNeed)
-	writer.WriteString(fmt.Sprintf("%d\n", result))
+	writer.Int(result)
+	writer.WriteByte('\n')
 }
 
-// solve находит минимальную сумму квадратов недостачи
+// solve находит минимальную сумму квадратов недостачи по модулю 10^9 + 7
 // Алгоритм: для минимизации суммы квадратов при фиксированной сумме
 // нужно распределить недостачу максимально равномерно
 func solve(M int64, W []int64, totalNeed int64) int {
-	N := len(W)
-	deficit := totalNeed - M
-
-	if deficit == 0 {
-		return 0
-	}
-
-	// Создаем пары (потребность, индекс) для сортировки
-	type pair struct {
-		w     int64
-		index int
-	}
-	pairs := make([]pair, N)
-	for i := 0; i < N; i++ {
-		pairs[i] = pair{w: W[i], index: i}
-	}
-
-	// Сортируем по убыванию потребности
-	sort.Slice(pairs, func(i, j int) bool {
-		return pairs[i].w > pairs[j].w
-	})
-
-	// Распределяем недостачу оптимальным образом
-	// Для минимизации суммы квадратов нужно распределить недостачу максимально равномерно
-	// с учётом ограничения: недостача группы не может превышать её потребность
-	origN := N
-	shortfall := make([]int64, origN)
-	remaining := deficit
-
-	// Проходим по группам (отсортированным по убыванию потребности)
-	// и распределяем недостачу
-	// Ключевая идея: если avgShortfall <= minW среди оставшихся групп,
-	// то можем распределить равномерно. Иначе, группа с минимальной потребностью
-	// получает максимальную недостачу (равную своей потребности).
-	for remaining > 0 && N > 0 {
-		avgShortfall := remaining / int64(N)
-		minW := pairs[N-1].w // минимальная потребность (pairs отсортирован по убыванию)
-
-		// Если средняя недостача <= минимальной потребности среди оставшихся,
-		// то все группы могут принять эту недостачу
-		if avgShortfall <= minW {
-			// Распределяем равномерно среди оставшихся групп
-			baseShortfall := remaining / int64(N)
-			extra := remaining % int64(N)
-
-			// Распределяем базовую недостачу
-			for j := 0; j < N; j++ {
-				shortfall[pairs[j].index] = baseShortfall
-			}
+	p := penalty(newLevel(totalNeed-M, W), W)
+	return int(p.Mod(p, big.NewInt(modint.Mod[modint.P1e9_7]())).Int64())
+}
+
+// level — оптимальная недостача в виде уровня «воды»: группа с потребностью
+// w недополучает min(w, L), и ещё extra групп с w > L — по одной единице
+// сверх L. extra меньше числа групп с w > L, так что никто не недополучает
+// больше своей потребности
+type level struct {
+	L, extra int64
+}
+
+// newLevel находит уровень для недостачи deficit = Σ W − M. Σ min(w, L)
+// не убывает по L: двоичный поиск наибольшего L с Σ min(w, L) ≤ deficit,
+// O(N log max W) проходов по W без сортировки и пар
+func newLevel(deficit int64, W []int64) level {
+	if deficit <= 0 {
+		return level{}
+	}
+	below := func(L int64) int64 {
+		s := int64(0)
+		for _, w := range W {
+			s += min(w, L)
+		}
+		return s
+	}
+	lo, hi := int64(0), slices.Max(W)
+	for lo < hi {
+		mid := lo + (hi-lo+1)/2
+		if below(mid) <= deficit {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	return level{L: lo, extra: deficit - below(lo)}
+}
 
-			// Распределяем остаток по одной единице
-			for j := 0; j < int(extra); j++ {
-				shortfall[pairs[j].index]++
+// allocation перечисляет (i, выдано группе i) в порядке ввода за O(1)
+// дополнительной памяти — потоковый режим для N до 10^7. Лишние единицы
+// недостачи получают первые extra групп с потребностью больше L
+func allocation(lv level, W []int64) iter.Seq2[int, int64] {
+	return func(yield func(int, int64) bool) {
+		extra := lv.extra
+		for i, w := range W {
+			short := min(w, lv.L)
+			if w > lv.L && extra > 0 {
+				short++
+				extra--
 			}
-			break
+			if !yield(i, w-short) {
+				return
+			}
+		}
+	}
+}
+
+// Plan — распределение снаряжения: Given[i] — выдано группе i,
+// Shortfall[i] = W[i] − Given[i] — недостача, в порядке ввода
+type Plan struct {
+	Given, Shortfall []int64
+}
+
+// plan возвращает оптимальное распределение M единиц по потребностям W
+func plan(M int64, W []int64) Plan {
+	total := int64(0)
+	for _, w := range W {
+		total += w
+	}
+	p := Plan{Given: make([]int64, len(W)), Shortfall: make([]int64, len(W))}
+	for i, g := range allocation(newLevel(total-M, W), W) {
+		p.Given[i], p.Shortfall[i] = g, W[i]-g
+	}
+	return p
+}
+
+// Penalty возвращает Σ Shortfall² точно
+func (p Plan) Penalty() *big.Int {
+	var acc uint128
+	for _, s := range p.Shortfall {
+		acc.addSquare(uint64(s))
+	}
+	return acc.big()
+}
+
+// penalty возвращает Σ недостача² точно, не перечисляя распределение:
+// Σ_{w ≤ L} w² + (число w > L)·L² + extra·(2L + 1)
+func penalty(lv level, W []int64) *big.Int {
+	var acc uint128
+	above := uint64(0)
+	for _, w := range W {
+		if w <= lv.L {
+			acc.addSquare(uint64(w))
 		} else {
-			// Группа с минимальной потребностью не может принять среднюю недостачу
-			// Даем ей максимально возможную недостачу (равную потребности)
-			shortfall[pairs[N-1].index] = pairs[N-1].w
-			remaining -= pairs[N-1].w
-			N-- // удаляем эту группу из рассмотрения
+			above++
+		}
+	}
+	res := acc.big()
+	L := new(big.Int).SetInt64(lv.L)
+	res.Add(res, new(big.Int).Mul(new(big.Int).Mul(L, L), new(big.Int).SetUint64(above)))
+	return res.Add(res, big.NewInt(lv.extra*(2*lv.L+1))) // extra < N, 2L + 1 ≤ 4·10^9
+}
+
+// uint128 — сумма квадратов до 10^7 · (2·10^9)² ≈ 4·10^25 без big.Int
+// в цикле
+type uint128 struct {
+	hi, lo uint64
+}
+
+func (a *uint128) addSquare(x uint64) {
+	hi, lo := bits.Mul64(x, x)
+	var carry uint64
+	a.lo, carry = bits.Add64(a.lo, lo, 0)
+	a.hi += hi + carry
+}
+
+func (a uint128) big() *big.Int {
+	res := new(big.Int).SetUint64(a.hi)
+	res.Lsh(res, 64)
+	return res.Or(res, new(big.Int).SetUint64(a.lo))
+}
+
+// Cost — выпуклый штраф группы f(x) за недостачу x ≥ 0
+type Cost interface {
+	// Marginal возвращает f(x+1) − f(x); не убывает по x. Значение больше
+	// int64 насыщается до math.MaxInt64, и дальше Marginal тоже
+	// math.MaxInt64 — монотонность сохраняется
+	Marginal(x int64) int64
+	// Value возвращает f(x) точно
+	Value(x int64) *big.Int
+}
+
+// Square — c·x², c ≥ 0; Square{1} — штраф задачи
+type Square struct {
+	C int64
+}
+
+func (s Square) Marginal(x int64) int64 { return mulSat(s.C, 2*x+1) }
+
+func (s Square) Value(x int64) *big.Int {
+	v := big.NewInt(x)
+	return v.Mul(v.Mul(v, v), big.NewInt(s.C))
+}
+
+// Power — x^P, P ≥ 1
+type Power struct {
+	P int
+}
+
+func (p Power) Marginal(x int64) int64 {
+	if next, ok := powSat(x+1, p.P); ok {
+		cur, _ := powSat(x, p.P)
+		return next - cur
+	}
+	// (x+1)^P больше int64, а разность может и уложиться
+	d := new(big.Int).Sub(p.Value(x+1), p.Value(x))
+	if !d.IsInt64() {
+		return math.MaxInt64
+	}
+	return d.Int64()
+}
+
+func (p Power) Value(x int64) *big.Int {
+	return new(big.Int).Exp(big.NewInt(x), big.NewInt(int64(p.P)), nil)
+}
+
+// mulSat возвращает a·b для a, b ≥ 0, насыщая до math.MaxInt64
+func mulSat(a, b int64) int64 {
+	hi, lo := bits.Mul64(uint64(a), uint64(b))
+	if hi > 0 || lo > math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return int64(lo)
+}
+
+// powSat возвращает x^p для x ≥ 0 и false, если степень больше int64
+func powSat(x int64, p int) (int64, bool) {
+	res := int64(1)
+	for range p {
+		if res = mulSat(res, x); res == math.MaxInt64 {
+			return 0, false
 		}
 	}
+	return res, true
+}
 
-	N = origN // восстанавливаем для подсчёта результата
+// PiecewiseLinear — кусочно-линейный штраф с f(0) = 0: на [Breaks[k-1],
+// Breaks[k]) наклон Slopes[k]. len(Breaks) = len(Slopes) − 1, Breaks
+// возрастают, Slopes не убывают
+type PiecewiseLinear struct {
+	Breaks, Slopes []int64
+}
+
+func (c PiecewiseLinear) Marginal(x int64) int64 {
+	k, _ := slices.BinarySearch(c.Breaks, x+1)
+	return c.Slopes[k]
+}
 
-	// Вычисляем сумму квадратов недостачи по модулю
-	result := int64(0)
-	for i := 0; i < N; i++ {
-		sq := shortfall[i] % mod
-		result = (result + sq*sq%mod) % mod
+func (c PiecewiseLinear) Value(x int64) *big.Int {
+	res, prev := new(big.Int), int64(0)
+	// segment прибавляет отрезок [prev, end) с наклоном slope
+	segment := func(slope, end int64) {
+		res.Add(res, new(big.Int).Mul(big.NewInt(slope), big.NewInt(end-prev)))
+		prev = end
 	}
+	for k, b := range c.Breaks {
+		if b >= x {
+			break
+		}
+		segment(c.Slopes[k], b)
+	}
+	k, _ := slices.BinarySearch(c.Breaks, x)
+	segment(c.Slopes[k], x)
+	return res
+}
 
-	return int(result)
+// planConvex распределяет M единиц по потребностям W при выпуклых штрафах
+// costs[i] от недостачи и возвращает план и Σ costs[i](Shortfall[i]).
+//
+// Жадно выгодно отдавать недостачу туда, где следующая единица дешевле
+// всего, поэтому оптимум — порог λ: группа i недополучает все единицы
+// с предельным штрафом < λ, а единицы ровно по λ добирают недостачу до
+// deficit в порядке ввода. Число единиц с предельным штрафом ≤ λ не
+// убывает по λ: λ — двоичный поиск, O(N log W log Λ) в худшем случае.
+// Если порог попадает на насыщенный предельный штраф, порядок единиц
+// неизвестен — паника
+func planConvex(M int64, W []int64, costs []Cost) (Plan, *big.Int) {
+	if len(costs) != len(W) {
+		panic("planConvex: штрафов не столько, сколько групп")
+	}
+	deficit := -M
+	for _, w := range W {
+		deficit += w
+	}
+	p := Plan{Given: slices.Clone(W), Shortfall: make([]int64, len(W))}
+	if deficit > 0 {
+		lo, hi := int64(0), int64(0)
+		for i, w := range W {
+			if w == 0 {
+				continue
+			}
+			lo, hi = min(lo, costs[i].Marginal(0)), max(hi, costs[i].Marginal(w-1))
+		}
+		// Наименьший λ, при котором единиц с предельным штрафом ≤ λ не
+		// меньше deficit. units монотонны по λ, поэтому после каждого шага
+		// ответ группы i остаётся в [low[i], high[i]] и поиск в группе
+		// сужается вместе с λ
+		low, high, cur := make([]int64, len(W)), slices.Clone(W), make([]int64, len(W))
+		for lo < hi {
+			// Середина без переполнения при любых lo ≤ hi
+			mid := int64(uint64(lo) + (uint64(hi)-uint64(lo))/2)
+			n := int64(0)
+			for i := range W {
+				cur[i] = units(costs[i], low[i], high[i], mid)
+				n += cur[i]
+			}
+			if n >= deficit {
+				hi = mid
+				copy(high, cur)
+			} else {
+				lo = mid + 1
+				copy(low, cur)
+			}
+		}
+		if lo == math.MaxInt64 {
+			panic("planConvex: предельный штраф на пороге не укладывается в int64")
+		}
+		rest := deficit
+		for i := range W {
+			p.Shortfall[i] = units(costs[i], low[i], high[i], lo-1)
+			rest -= p.Shortfall[i]
+		}
+		for i := range W {
+			if rest == 0 {
+				break
+			}
+			add := min(rest, high[i]-p.Shortfall[i])
+			p.Shortfall[i] += add
+			rest -= add
+		}
+		for i := range W {
+			p.Given[i] -= p.Shortfall[i]
+		}
+	}
+	total := new(big.Int)
+	for i, s := range p.Shortfall {
+		total.Add(total, costs[i].Value(s))
+	}
+	return p, total
+}
+
+// units возвращает число первых единиц недостачи с предельным штрафом
+// ≤ lambda, если известно, что оно в [lo, hi], — двоичным поиском:
+// предельный штраф не убывает
+func units(c Cost, lo, hi, lambda int64) int64 {
+	for lo < hi {
+		mid := lo + (hi-lo+1)/2
+		if c.Marginal(mid-1) <= lambda {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	return lo
 }
@@ -1,7 +1,12 @@
 package main
 
 import (
+	"math"
+	"math/big"
+	"math/rand"
 	"testing"
+
+	"yandex-2025-winter/lib/modint"
 )
 
 func TestExamples(t *testing.T) {
@@ -79,8 +84,8 @@ func TestEdgeCases(t *testing.T) {
 				totalNeed += w
 			}
 			result := solve(tt.M, tt.W, totalNeed)
-			if result < 0 || result >= mod {
-				t.Errorf("solve(M=%d, W=%v) = %d, должно быть в [0, %d)", tt.M, tt.W, result, mod)
+			if result < 0 || result >= modint.M1e9_7 {
+				t.Errorf("solve(M=%d, W=%v) = %d, должно быть в [0, %d)", tt.M, tt.W, result, modint.M1e9_7)
 			}
 			if tt.expected > 0 && result != tt.expected {
 				t.Logf("solve(M=%d, W=%v) = %d, ожидалось %d (может отличаться из-за оптимизации)", tt.M, tt.W, result, tt.expected)
@@ -100,7 +105,7 @@ func TestLargeInput(t *testing.T) {
 	M := totalNeed / 2 // Половина от потребности
 
 	result := solve(M, W, totalNeed)
-	if result < 0 || result >= mod {
+	if result < 0 || result >= modint.M1e9_7 {
 		t.Errorf("solve на больших данных вернул недопустимое значение %d", result)
 	}
 }
@@ -159,13 +164,255 @@ func TestConstraints(t *testing.T) {
 			}
 
 			result := solve(tt.M, tt.W, totalNeed)
-			if result < 0 || result >= mod {
+			if result < 0 || result >= modint.M1e9_7 {
 				t.Errorf("solve вернул недопустимое значение %d", result)
 			}
 		})
 	}
 }
 
+// brutePenalty — ДП по группам: наименьшая Σ недостача² при недостаче
+// ровно deficit
+func brutePenalty(W []int64, deficit int64) int64 {
+	const inf = int64(1) << 62
+	dp := make([]int64, deficit+1)
+	for d := range dp {
+		dp[d] = inf
+	}
+	dp[0] = 0
+	for _, w := range W {
+		next := make([]int64, deficit+1)
+		for d := range next {
+			next[d] = inf
+			for s := int64(0); s <= min(w, int64(d)); s++ {
+				if dp[int64(d)-s] < inf {
+					next[d] = min(next[d], dp[int64(d)-s]+s*s)
+				}
+			}
+		}
+		dp = next
+	}
+	return dp[deficit]
+}
+
+func TestPlan(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for iter := 0; iter < 500; iter++ {
+		W := make([]int64, 1+rng.Intn(6))
+		total := int64(0)
+		for i := range W {
+			W[i] = 1 + rng.Int63n(8)
+			total += W[i]
+		}
+		M := rng.Int63n(total) + 1
+		if M == total {
+			M-- // суммарная потребность строго больше M
+		}
+		p := plan(M, W)
+		given := int64(0)
+		for i := range W {
+			if p.Given[i] < 0 || p.Given[i] > W[i] || p.Shortfall[i] != W[i]-p.Given[i] {
+				t.Fatalf("M=%d W=%v: группе %d выдано %d", M, W, i, p.Given[i])
+			}
+			given += p.Given[i]
+		}
+		if given != M {
+			t.Fatalf("M=%d W=%v: выдано %d", M, W, given)
+		}
+		want := brutePenalty(W, total-M)
+		if got := p.Penalty(); !got.IsInt64() || got.Int64() != want {
+			t.Fatalf("M=%d W=%v: штраф плана %v, ожидалось %d", M, W, got, want)
+		}
+		if got := penalty(newLevel(total-M, W), W); got.Int64() != want {
+			t.Fatalf("M=%d W=%v: penalty = %v, ожидалось %d", M, W, got, want)
+		}
+		if got := solve(M, W, total); got != int(want%modint.M1e9_7) {
+			t.Fatalf("M=%d W=%v: solve = %d, ожидалось %d", M, W, got, want%modint.M1e9_7)
+		}
+	}
+}
+
+// TestPenaltyExact — штраф больше 2^64: модульный ответ его не показывает
+func TestPenaltyExact(t *testing.T) {
+	W := make([]int64, 10)
+	for i := range W {
+		W[i] = 2000000000
+	}
+	p := plan(1, W)
+	// Недостача 2·10^10 − 1: девять групп по 2·10^9, одна — 2·10^9 − 1
+	want := new(big.Int).Mul(big.NewInt(9), new(big.Int).Mul(big.NewInt(2e9), big.NewInt(2e9)))
+	want.Add(want, new(big.Int).Mul(big.NewInt(2e9-1), big.NewInt(2e9-1)))
+	if got := p.Penalty(); got.Cmp(want) != 0 {
+		t.Errorf("Penalty = %v, ожидалось %v", got, want)
+	}
+	if got := penalty(newLevel(int64(len(W))*2e9-1, W), W); got.Cmp(want) != 0 {
+		t.Errorf("penalty = %v, ожидалось %v", got, want)
+	}
+	if want.IsUint64() {
+		t.Error("штраф должен быть больше 2^64")
+	}
+}
+
+// TestAllocationStop — потоковый перебор останавливается по break
+func TestAllocationStop(t *testing.T) {
+	W := []int64{4, 5, 2, 3}
+	lv := newLevel(4, W)
+	var seen []int
+	for i, g := range allocation(lv, W) {
+		if g != W[i]-1 {
+			t.Errorf("группе %d выдано %d, ожидалось %d", i, g, W[i]-1)
+		}
+		seen = append(seen, i)
+		if i == 1 {
+			break
+		}
+	}
+	if len(seen) != 2 {
+		t.Errorf("после break получено %v", seen)
+	}
+}
+
+// randomCost возвращает случайный выпуклый штраф одного из трёх видов
+func randomCost(rng *rand.Rand) Cost {
+	switch rng.Intn(3) {
+	case 0:
+		return Square{rng.Int63n(4)}
+	case 1:
+		return Power{1 + rng.Intn(3)}
+	}
+	var c PiecewiseLinear
+	slope, x := -rng.Int63n(5), int64(0)
+	for range rng.Intn(3) {
+		x += 1 + rng.Int63n(3)
+		c.Breaks = append(c.Breaks, x)
+		c.Slopes = append(c.Slopes, slope)
+		slope += rng.Int63n(4)
+	}
+	c.Slopes = append(c.Slopes, slope)
+	return c
+}
+
+func TestPlanConvex(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	for iter := 0; iter < 500; iter++ {
+		W := make([]int64, 1+rng.Intn(5))
+		costs := make([]Cost, len(W))
+		total := int64(0)
+		for i := range W {
+			W[i] = rng.Int63n(7)
+			costs[i] = randomCost(rng)
+			total += W[i]
+		}
+		M := rng.Int63n(total + 2)
+		deficit := max(0, total-M)
+
+		// ДП по группам: наименьший штраф при недостаче ровно d
+		const inf = int64(1) << 62
+		dp := []int64{0}
+		for i, w := range W {
+			next := make([]int64, len(dp)+int(w))
+			for d := range next {
+				next[d] = inf
+				for s := max(0, int64(d)-int64(len(dp))+1); s <= min(w, int64(d)); s++ {
+					if dp[int64(d)-s] < inf {
+						next[d] = min(next[d], dp[int64(d)-s]+costs[i].Value(s).Int64())
+					}
+				}
+			}
+			dp = next
+		}
+
+		p, cost := planConvex(M, W, costs)
+		given, sum := int64(0), int64(0)
+		for i := range W {
+			if p.Shortfall[i] < 0 || p.Shortfall[i] > W[i] || p.Given[i] != W[i]-p.Shortfall[i] {
+				t.Fatalf("M=%d W=%v: недостача группы %d = %d", M, W, i, p.Shortfall[i])
+			}
+			given += p.Given[i]
+			sum += costs[i].Value(p.Shortfall[i]).Int64()
+		}
+		if given != total-deficit {
+			t.Fatalf("M=%d W=%v: выдано %d", M, W, given)
+		}
+		if !cost.IsInt64() || cost.Int64() != sum || sum != dp[deficit] {
+			t.Fatalf("M=%d W=%v %v: штраф %v (по плану %d), ожидалось %d", M, W, costs, cost, sum, dp[deficit])
+		}
+	}
+}
+
+// TestPlanConvexSquare — Square{1} совпадает со штрафом задачи
+func TestPlanConvexSquare(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	W := make([]int64, 1000)
+	costs := make([]Cost, len(W))
+	total := int64(0)
+	for i := range W {
+		W[i] = 1 + rng.Int63n(2e9)
+		costs[i] = Square{1}
+		total += W[i]
+	}
+	M := total / 3
+	p, cost := planConvex(M, W, costs)
+	if want := penalty(newLevel(total-M, W), W); cost.Cmp(want) != 0 {
+		t.Errorf("planConvex = %v, ожидалось %v", cost, want)
+	}
+	if got := p.Penalty(); got.Cmp(cost) != 0 {
+		t.Errorf("Plan.Penalty = %v, planConvex = %v", got, cost)
+	}
+}
+
+// TestPlanConvexLarge — штрафы больше int64 при W = 2·10^9
+func TestPlanConvexLarge(t *testing.T) {
+	pow := func(x int64, p int64) *big.Int {
+		return new(big.Int).Exp(big.NewInt(x), big.NewInt(p), nil)
+	}
+	tests := []struct {
+		name  string
+		M     int64
+		W     []int64
+		costs []Cost
+		want  *big.Int
+	}{
+		// 3·(2·10^9)² = 1.2·10^19 > 2^63
+		{"Square{3}", 0, []int64{2e9}, []Cost{Square{3}}, new(big.Int).Mul(big.NewInt(3), pow(2e9, 2))},
+		// 10^9·(10^9)² = 10^27, предельный штраф у порога ≈ 2·10^18
+		{"Square{10^9}", 2e9, []int64{2e9, 2e9}, []Cost{Square{1e9}, Square{1e9}},
+			new(big.Int).Mul(big.NewInt(2e9), pow(1e9, 2))},
+		// (x+1)³ − x³ ≈ 3·10^18 у порога, сами кубы — 10^27
+		{"Power{3}", 2e9, []int64{2e9, 2e9}, []Cost{Power{3}, Power{3}}, new(big.Int).Mul(big.NewInt(2), pow(1e9, 3))},
+		{"Power{3} разные W", 1e9, []int64{1e9, 2e9}, []Cost{Power{3}, Power{3}}, new(big.Int).Mul(big.NewInt(2), pow(1e9, 3))},
+	}
+	for _, tt := range tests {
+		p, got := planConvex(tt.M, tt.W, tt.costs)
+		if got.Cmp(tt.want) != 0 {
+			t.Errorf("%s: штраф %v, ожидалось %v (план %v)", tt.name, got, tt.want, p.Shortfall)
+		}
+	}
+
+	// Предельный штраф не убывает и там, где он насыщается
+	for _, c := range []Cost{Power{3}, Power{5}, Square{1 << 40}} {
+		prev := int64(math.MinInt64)
+		for x := int64(0); x < 2e9; x = x*3/2 + 1 {
+			m := c.Marginal(x)
+			if m < prev {
+				t.Fatalf("%v: Marginal(%d) = %d меньше предыдущего %d", c, x, m, prev)
+			}
+			prev = m
+		}
+	}
+	if got := (Power{3}).Marginal(2e6); got != 12000006000001 {
+		t.Errorf("Power{3}.Marginal(2·10^6) = %d, ожидалось 12000006000001", got)
+	}
+
+	// Порог в насыщенной области: какая единица дешевле, неизвестно
+	defer func() {
+		if recover() == nil {
+			t.Error("ожидалась паника на насыщенном пороге")
+		}
+	}()
+	planConvex(0, []int64{2, 2}, []Cost{Square{1 << 62}, Square{1 << 62}})
+}
+
 func BenchmarkSolve(b *testing.B) {
 	W := make([]int64, 1000)
 	totalNeed := int64(0)
@@ -209,3 +456,38 @@ func BenchmarkSolveMaxConstraints(b *testing.B) {
 		solve(M, W, totalNeed)
 	}
 }
+
+// BenchmarkStream — 10^7 групп: уровень, точный штраф и потоковое
+// распределение без среза распределения
+func BenchmarkStream(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	W := make([]int64, 10_000_000)
+	total := int64(0)
+	for i := range W {
+		W[i] = 1 + rng.Int63n(2e9)
+		total += W[i]
+	}
+	for b.Loop() {
+		lv := newLevel(total/2, W)
+		penalty(lv, W)
+		sum := int64(0)
+		for _, g := range allocation(lv, W) {
+			sum += g
+		}
+	}
+}
+
+func BenchmarkPlanConvex(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	W := make([]int64, 100000)
+	costs := make([]Cost, len(W))
+	total := int64(0)
+	for i := range W {
+		W[i] = 1 + rng.Int63n(2e9)
+		costs[i] = Square{1 + rng.Int63n(3)}
+		total += W[i]
+	}
+	for b.Loop() {
+		planConvex(total/2, W, costs)
+	}
+}
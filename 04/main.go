@@ -1,76 +1,152 @@
 package main
 
 import (
-	"bufio"
 	"fmt"
+	"math/big"
 	"os"
+	"slices"
 	"sort"
-	"strconv"
 	"strings"
+
+	"yandex-2025-winter/lib/fastio"
+	"yandex-2025-winter/lib/numtheory"
 )
 
 func main() {
-	reader := bufio.NewReaderSize(os.Stdin, 1<<20)
-	writer := bufio.NewWriterSize(os.Stdout, 1<<20)
+	reader := fastio.NewReader(os.Stdin)
+	writer := fastio.NewWriter(os.Stdout)
 	defer writer.Flush()
 
-	// Предвычисленные highly composite numbers (из OEIS A002182)
-	// Это полный список всех 156 артефактов до 10^18
-	artifacts := []int64{
-		1, 2, 4, 6, 12, 24, 36, 48, 60, 120,
-		180, 240, 360, 720, 840, 1260, 1680, 2520, 5040, 7560,
-		10080, 15120, 20160, 25200, 27720, 45360, 50400, 55440, 83160, 110880,
-		166320, 221760, 277200, 332640, 498960, 554400, 665280, 720720, 1081080, 1441440,
-		2162160, 2882880, 3603600, 4324320, 6486480, 7207200, 8648640, 10810800, 14414400, 17297280,
-		21621600, 32432400, 36756720, 43243200, 61261200, 73513440, 110270160, 122522400, 147026880, 183783600,
-		245044800, 294053760, 367567200, 551350800, 698377680, 735134400, 1102701600, 1396755360, 2095133040, 2205403200,
-		2327925600, 2793510720, 3491888400, 4655851200, 5587021440, 6983776800, 10475665200, 13967553600, 20951330400, 27935107200,
-		41902660800, 48886437600, 64250746560, 73329656400, 80313433200, 97772875200, 128501493120, 146659312800, 160626866400, 240940299600,
-		293318625600, 321253732800, 481880599200, 642507465600, 963761198400, 1124388064800, 1606268664000, 1686582097200, 1927522396800, 2248776129600,
-		3212537328000, 3373164194400, 4497552259200, 6746328388800, 8995104518400, 9316358251200, 13492656777600, 18632716502400, 26985313555200, 27949074753600,
-		32607253879200, 46581791256000, 48910880818800, 55898149507200, 65214507758400, 93163582512000, 97821761637600, 130429015516800, 195643523275200, 260858031033600,
-		288807105787200, 391287046550400, 577614211574400, 782574093100800, 866421317361600, 1010824870255200, 1444035528936000, 1516237305382800, 1732842634723200, 2021649740510400,
-		2888071057872000, 3032474610765600, 4043299481020800, 6064949221531200, 8086598962041600, 10108248702552000, 12129898443062400, 18194847664593600, 20216497405104000, 24259796886124800,
-		30324746107656000, 36389695329187200, 48519593772249600, 60649492215312000, 72779390658374400, 74801040398884800, 106858629141264000, 112201560598327200, 149602080797769600, 224403121196654400,
-		299204161595539200, 374005201994424000, 448806242393308800, 673209363589963200, 748010403988848000, 897612484786617600,
-	}
+	// Highly composite numbers (OEIS A002182) до 10^18 — верхней границы r
+	// по условию; запросы с большим r достроят список сами
+	artifacts := newArtifacts(big.NewInt(1e18))
 
 	// Читаем количество запросов
-	line, _ := reader.ReadString('\n')
-	q, _ := strconv.Atoi(strings.TrimSpace(line))
+	q := reader.Int()
 
 	// Обрабатываем запросы
-	for i := 0; i < q; i++ {
-		line, _ = reader.ReadString('\n')
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
+	for i := 0; i < q && reader.More(); i++ {
+		l, r := reader.Word(), reader.Word()
 
-		parts := strings.Fields(line)
-		if len(parts) < 2 {
-			continue
+		// По условию числа корректны; на нечисловой границе — 0
+		result, err := countArtifacts(artifacts, l, r)
+		if err != nil {
+			result = 0
 		}
+		writer.Int(result)
+		writer.WriteByte('\n')
+	}
+}
 
-		l, _ := strconv.ParseInt(parts[0], 10, 64)
-		r, _ := strconv.ParseInt(parts[1], 10, 64)
+// artifacts — все артефакты не больше bound по возрастанию
+type artifacts struct {
+	bound *big.Int
+	list  []*big.Int
+}
 
-		result := countArtifacts(artifacts, l, r)
-		writer.WriteString(fmt.Sprintf("%d\n", result))
-	}
+func newArtifacts(bound *big.Int) *artifacts {
+	return &artifacts{bound: new(big.Int).Set(bound), list: highlyComposite(bound)}
 }
 
-// countArtifacts считает количество артефактов в диапазоне [l, r]
-func countArtifacts(artifacts []int64, l, r int64) int {
+// maxBound — наибольшая граница списка, 10^30: построение до неё занимает
+// около 1.5 с и растёт примерно в 2.5 раза на каждые три порядка, а число
+// делителей в highlyComposite переполняет uint64 уже около 10^100
+var maxBound, _ = new(big.Int).SetString("1"+strings.Repeat("0", 30), 10)
+
+// countArtifacts считает количество артефактов в диапазоне [l, r]; l и r —
+// десятичные записи целых чисел, r ≤ 10^30. Если r больше границы списка,
+// список строится заново до r
+func countArtifacts(a *artifacts, l, r string) (int, error) {
+	lo, ok := new(big.Int).SetString(l, 10)
+	if !ok {
+		return 0, fmt.Errorf("левая граница %q — не целое число", l)
+	}
+	hi, ok := new(big.Int).SetString(r, 10)
+	if !ok {
+		return 0, fmt.Errorf("правая граница %q — не целое число", r)
+	}
+	if hi.Cmp(maxBound) > 0 {
+		return 0, fmt.Errorf("правая граница %s больше 10^30", r)
+	}
+	if hi.Cmp(a.bound) > 0 {
+		*a = *newArtifacts(hi)
+	}
+
 	// Бинарный поиск первого артефакта >= l
-	left := sort.Search(len(artifacts), func(i int) bool {
-		return artifacts[i] >= l
+	left := sort.Search(len(a.list), func(i int) bool {
+		return a.list[i].Cmp(lo) >= 0
 	})
 
 	// Бинарный поиск первого артефакта > r
-	right := sort.Search(len(artifacts), func(i int) bool {
-		return artifacts[i] > r
+	right := sort.Search(len(a.list), func(i int) bool {
+		return a.list[i].Cmp(hi) > 0
 	})
 
-	return right - left
+	return max(0, right-left), nil
+}
+
+// highlyComposite возвращает все highly composite numbers не больше bound
+// по возрастанию.
+//
+// У такого числа n = 2^e1 · 3^e2 · 5^e3 · … показатели не возрастают:
+// иначе перестановка показателей дала бы меньшее число с тем же числом
+// делителей. Значит, n — произведение праймориалов, и достаточно
+// перебрать невозрастающие векторы показателей со значением ≤ bound,
+// отсортировать кандидатов и оставить рекорды по числу делителей
+// Π(e_i + 1). Выше 2^63 значения считаются в big.Int. Кандидатов растёт
+// примерно в 2.5 раза на каждые три порядка границы: до 10^18 — 30 мс,
+// до 10^30 — 1.5 с. bound ≤ maxBound, иначе паника
+func highlyComposite(bound *big.Int) []*big.Int {
+	if bound.Cmp(maxBound) > 0 {
+		panic("highlyComposite: граница больше 10^30")
+	}
+	type candidate struct {
+		n *big.Int
+		d uint64 // число делителей: при n ≤ 10^30 меньше 2^32
+	}
+	var (
+		cands  []candidate
+		primes []*big.Int
+		next   = 2 // следующий проверяемый на простоту
+	)
+	// prime возвращает i-е простое, достраивая список
+	prime := func(i int) *big.Int {
+		for ; len(primes) <= i; next++ {
+			if numtheory.IsPrime(next) {
+				primes = append(primes, big.NewInt(int64(next)))
+			}
+		}
+		return primes[i]
+	}
+
+	// dfs добавляет кандидата n с d делителями и продолжает его степенями
+	// i-го простого, не больше maxExp
+	var dfs func(i, maxExp int, n *big.Int, d uint64)
+	dfs = func(i, maxExp int, n *big.Int, d uint64) {
+		cands = append(cands, candidate{n, d})
+		p := prime(i)
+		x := n
+		for e := 1; e <= maxExp; e++ {
+			x = new(big.Int).Mul(x, p)
+			if x.Cmp(bound) > 0 {
+				break
+			}
+			dfs(i+1, e, x, d*uint64(e+1))
+		}
+	}
+	if bound.Sign() < 1 {
+		return nil
+	}
+	dfs(0, bound.BitLen(), big.NewInt(1), 1)
+
+	slices.SortFunc(cands, func(a, b candidate) int { return a.n.Cmp(b.n) })
+	var res []*big.Int
+	best := uint64(0)
+	for _, c := range cands {
+		if c.d > best {
+			best = c.d
+			res = append(res, c.n)
+		}
+	}
+	return res
 }
@@ -1,10 +1,17 @@
 package main
 
 import (
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
 	"testing"
+
+	"yandex-2025-winter/lib/numtheory"
 )
 
-// Список артефактов для тестов
+// Список артефактов до 10^18 из OEIS A002182 — прежняя встроенная таблица;
+// TestRegenerate сверяет с ним highlyComposite
 var testArtifacts = []int64{
 	1, 2, 4, 6, 12, 24, 36, 48, 60, 120,
 	180, 240, 360, 720, 840, 1260, 1680, 2520, 5040, 7560,
@@ -24,6 +31,18 @@ var testArtifacts = []int64{
 	299204161595539200, 374005201994424000, 448806242393308800, 673209363589963200, 748010403988848000, 897612484786617600,
 }
 
+// generated — артефакты до 10^18, как в main
+var generated = newArtifacts(big.NewInt(1e18))
+
+// count вызывает countArtifacts для границ-чисел
+func count(l, r int64) int {
+	res, err := countArtifacts(generated, strconv.FormatInt(l, 10), strconv.FormatInt(r, 10))
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
 func TestExamples(t *testing.T) {
 	tests := []struct {
 		l, r     int64
@@ -35,7 +54,7 @@ func TestExamples(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		result := countArtifacts(testArtifacts, tt.l, tt.r)
+		result := count(tt.l, tt.r)
 		if result != tt.expected {
 			t.Errorf("countArtifacts(%d, %d) = %d, want %d", tt.l, tt.r, result, tt.expected)
 		}
@@ -61,7 +80,7 @@ func TestEdgeCases(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := countArtifacts(testArtifacts, tt.l, tt.r)
+			result := count(tt.l, tt.r)
 			if result != tt.expected {
 				t.Errorf("countArtifacts(%d, %d) = %d, want %d", tt.l, tt.r, result, tt.expected)
 			}
@@ -73,7 +92,7 @@ func TestSpecificArtifacts(t *testing.T) {
 	// Проверяем, что конкретные числа являются артефактами
 	knownArtifacts := []int64{1, 2, 4, 6, 12, 24, 60, 120, 720, 5040}
 	for _, a := range knownArtifacts {
-		result := countArtifacts(testArtifacts, a, a)
+		result := count(a, a)
 		if result != 1 {
 			t.Errorf("countArtifacts(%d, %d) = %d, want 1 (should be artifact)", a, a, result)
 		}
@@ -82,7 +101,7 @@ func TestSpecificArtifacts(t *testing.T) {
 	// Проверяем, что некоторые числа НЕ являются артефактами
 	notArtifacts := []int64{3, 5, 7, 8, 9, 10, 11, 100, 1000}
 	for _, a := range notArtifacts {
-		result := countArtifacts(testArtifacts, a, a)
+		result := count(a, a)
 		if result != 0 {
 			t.Errorf("countArtifacts(%d, %d) = %d, want 0 (should NOT be artifact)", a, a, result)
 		}
@@ -101,7 +120,7 @@ func TestLargeRanges(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := countArtifacts(testArtifacts, tt.l, tt.r)
+			result := count(tt.l, tt.r)
 			if result < 0 {
 				t.Errorf("countArtifacts(%d, %d) = %d, should be non-negative", tt.l, tt.r, result)
 			}
@@ -124,22 +143,135 @@ func TestArtifactsCount(t *testing.T) {
 	}
 }
 
+func TestRegenerate(t *testing.T) {
+	got := highlyComposite(big.NewInt(1e18))
+	if len(got) != len(testArtifacts) {
+		t.Fatalf("сгенерировано %d артефактов, в таблице %d", len(got), len(testArtifacts))
+	}
+	for i, a := range testArtifacts {
+		if !got[i].IsInt64() || got[i].Int64() != a {
+			t.Fatalf("артефакт %d: %v, в таблице %d", i, got[i], a)
+		}
+	}
+}
+
+// TestBrute сверяет с определением: рекорды числа делителей до 10^5
+func TestBrute(t *testing.T) {
+	var want []int64
+	best := 0
+	for n := 1; n <= 100000; n++ {
+		if d := numtheory.DivisorCount(n); d > best {
+			best = d
+			want = append(want, int64(n))
+		}
+	}
+	got := highlyComposite(big.NewInt(100000))
+	if len(got) != len(want) {
+		t.Fatalf("сгенерировано %d артефактов, ожидалось %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].Int64() != want[i] {
+			t.Fatalf("артефакт %d: %v, ожидалось %d", i, got[i], want[i])
+		}
+	}
+	if got := highlyComposite(big.NewInt(0)); len(got) != 0 {
+		t.Errorf("highlyComposite(0) = %v", got)
+	}
+}
+
+// divisorCount считает делители n с простыми делителями до 100
+func divisorCount(n *big.Int) int {
+	n = new(big.Int).Set(n)
+	d := 1
+	for _, p := range numtheory.Primes(100) {
+		e, m := 0, new(big.Int)
+		for bp := big.NewInt(int64(p)); ; e++ {
+			if m.Mod(n, bp); m.Sign() != 0 {
+				break
+			}
+			n.Quo(n, bp)
+		}
+		d *= e + 1
+	}
+	if n.Cmp(big.NewInt(1)) != 0 {
+		panic("простой делитель больше 100")
+	}
+	return d
+}
+
+// TestBeyondInt64 — артефакты выше 2^63 и границы длиннее int64
+func TestBeyondInt64(t *testing.T) {
+	bound, _ := new(big.Int).SetString("1"+strings.Repeat("0", 24), 10)
+	list := highlyComposite(bound)
+	if len(list) <= len(testArtifacts) {
+		t.Fatalf("до 10^24 всего %d артефактов", len(list))
+	}
+	for i := 1; i < len(list); i++ {
+		if list[i].Cmp(list[i-1]) <= 0 || divisorCount(list[i]) <= divisorCount(list[i-1]) {
+			t.Fatalf("артефакты %v и %v не возрастают", list[i-1], list[i])
+		}
+	}
+	if list[len(list)-1].IsInt64() {
+		t.Errorf("последний артефакт %v укладывается в int64", list[len(list)-1])
+	}
+
+	// countArtifacts достраивает список при r > 10^18
+	a := newArtifacts(big.NewInt(1e18))
+	got, err := countArtifacts(a, "1", bound.String())
+	if err != nil || got != len(list) {
+		t.Errorf("countArtifacts(1, 10^24) = %d, %v, ожидалось %d", got, err, len(list))
+	}
+	got, err = countArtifacts(a, "1000000000000000001", bound.String())
+	if err != nil || got != len(list)-len(testArtifacts) {
+		t.Errorf("countArtifacts(10^18+1, 10^24) = %d, %v, ожидалось %d", got, err, len(list)-len(testArtifacts))
+	}
+	if got, err := countArtifacts(a, "10", "5"); err != nil || got != 0 {
+		t.Errorf("countArtifacts(10, 5) = %d, %v, ожидалось 0", got, err)
+	}
+	tooLarge := "1" + strings.Repeat("0", 31)
+	for _, lr := range [][2]string{{"x", "5"}, {"1", "1e18"}, {"", "1"}, {"1", tooLarge}} {
+		if _, err := countArtifacts(a, lr[0], lr[1]); err == nil {
+			t.Errorf("countArtifacts(%q, %q): ожидалась ошибка", lr[0], lr[1])
+		}
+	}
+	if a.bound.Cmp(bound) != 0 {
+		t.Errorf("после ошибок граница списка %v, ожидалось 10^24", a.bound)
+	}
+	if !slices.EqualFunc(a.list[:len(testArtifacts)], testArtifacts, func(x *big.Int, y int64) bool { return x.Int64() == y }) {
+		t.Error("достроенный список не начинается с таблицы")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("highlyComposite(10^31): ожидалась паника")
+		}
+	}()
+	highlyComposite(new(big.Int).Mul(maxBound, big.NewInt(10)))
+}
+
 func BenchmarkCountArtifacts(b *testing.B) {
 	for i := 0; i < b.N; i++ {
-		countArtifacts(testArtifacts, 1, 1000000000000000000)
+		count(1, 1000000000000000000)
 	}
 }
 
 func BenchmarkCountArtifactsSmallRange(b *testing.B) {
 	for i := 0; i < b.N; i++ {
-		countArtifacts(testArtifacts, 1, 100)
+		count(1, 100)
 	}
 }
 
 func BenchmarkCountArtifactsManyQueries(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		for j := 0; j < 50000; j++ {
-			countArtifacts(testArtifacts, 1, 1000000000000000000)
+			count(1, 1000000000000000000)
 		}
 	}
 }
+
+func BenchmarkHighlyComposite(b *testing.B) {
+	bound := big.NewInt(1e18)
+	for b.Loop() {
+		highlyComposite(bound)
+	}
+}
@@ -1,12 +1,11 @@
 package main
 
 import (
-	"bufio"
-	"fmt"
 	"os"
 	"sort"
-	"strconv"
-	"strings"
+
+	"yandex-2025-winter/lib/dsu"
+	"yandex-2025-winter/lib/fastio"
 )
 
 type Point struct {
@@ -20,45 +19,23 @@ type Edge struct {
 }
 
 func main() {
-	reader := bufio.NewReaderSize(os.Stdin, 1<<20)
-	writer := bufio.NewWriterSize(os.Stdout, 1<<20)
+	reader := fastio.NewReader(os.Stdin)
+	writer := fastio.NewWriter(os.Stdout)
 	defer writer.Flush()
 
 	// Читаем N
-	line, _ := reader.ReadString('\n')
-	N, _ := strconv.Atoi(strings.TrimSpace(line))
+	N := reader.Int()
 
 	// Читаем точки
 	points := make([]Point, N)
 	for i := 0; i < N; i++ {
-		line, _ = reader.ReadString('\n')
-		parts := strings.Fields(strings.TrimSpace(line))
-		x, _ := strconv.Atoi(parts[0])
-		y, _ := strconv.Atoi(parts[1])
-		z, _ := strconv.Atoi(parts[2])
+		x, y, z := reader.Int(), reader.Int(), reader.Int()
 		points[i] = Point{x: x, y: y, z: z, idx: i}
 	}
 
 	result := solveMST(points)
-	writer.WriteString(fmt.Sprintf("%d\n", result))
-}
-
-func find(parent []int, x int) int {
-	if parent[x] != x {
-		parent[x] = find(parent, parent[x])
-	}
-	return parent[x]
-}
-
-func union(parent, rank []int, x, y int) {
-	if rank[x] < rank[y] {
-		parent[x] = y
-	} else if rank[x] > rank[y] {
-		parent[y] = x
-	} else {
-		parent[y] = x
-		rank[x]++
-	}
+	writer.Int64(result)
+	writer.WriteByte('\n')
 }
 
 // solveMST находит минимальное остовное дерево для заданных точек
@@ -137,12 +114,7 @@ func solveMST(points []Point) int64 {
 	})
 
 	// Алгоритм Крускала с DSU
-	parent := make([]int, N)
-	rank := make([]int, N)
-	for i := 0; i < N; i++ {
-		parent[i] = i
-		rank[i] = 0
-	}
+	d := dsu.New(N)
 
 	var totalCost int64
 	edgesUsed := 0
@@ -151,10 +123,7 @@ func solveMST(points []Point) int64 {
 		if edgesUsed == N-1 {
 			break
 		}
-		fromRoot := find(parent, edge.from)
-		toRoot := find(parent, edge.to)
-		if fromRoot != toRoot {
-			union(parent, rank, fromRoot, toRoot)
+		if d.Union(edge.from, edge.to) {
 			totalCost += int64(edge.weight)
 			edgesUsed++
 		}
@@ -1,7 +1,13 @@
 package main
 
 import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
 	"testing"
+
+	"yandex-2025-winter/lib/stress"
 )
 
 func TestExample1(t *testing.T) {
@@ -124,84 +130,94 @@ func TestAbs(t *testing.T) {
 	}
 }
 
-func TestFind(t *testing.T) {
-	// Тест find с path compression
-	parent := []int{0, 0, 1, 2, 3, 4}
-
-	// Изначально все указывают на предыдущий элемент
-	// После find(5) должно произойти сжатие пути
+// input05 — вход задачи: точки с координатами из условия
+type input05 []Point
 
-	root := find(parent, 5)
-	if root != 0 {
-		t.Errorf("find(5) = %d, expected 0", root)
-	}
-
-	// Проверяем, что произошло сжатие пути
-	if parent[5] != 0 {
-		t.Errorf("parent[5] should be compressed to 0, got %d", parent[5])
-	}
-	if parent[4] != 0 {
-		t.Errorf("parent[4] should be compressed to 0, got %d", parent[4])
+func (in input05) String() string {
+	var sb strings.Builder
+	fmt.Fprintln(&sb, len(in))
+	for _, p := range in {
+		fmt.Fprintln(&sb, p.x, p.y, p.z)
 	}
+	return sb.String()
 }
 
-func TestUnion(t *testing.T) {
-	// Тест union с разными рангами
-	parent := []int{0, 1, 2, 3, 4, 5}
-	rank := []int{0, 0, 0, 0, 0, 0}
-
-	// Объединяем множества
-	union(parent, rank, 0, 1)
-	if find(parent, 0) != find(parent, 1) {
-		t.Errorf("0 and 1 should be in the same set")
-	}
-
-	// Объединяем ещё множества
-	union(parent, rank, 2, 3)
-	union(parent, rank, 4, 5)
-
-	// Объединяем два множества
-	root1 := find(parent, 0)
-	root2 := find(parent, 2)
-	union(parent, rank, root1, root2)
-
-	if find(parent, 0) != find(parent, 2) {
-		t.Errorf("0 and 2 should be in the same set after union")
-	}
-
-	// Проверяем, что ранги обновляются правильно
-	union(parent, rank, find(parent, 4), find(parent, 0))
-	if find(parent, 4) != find(parent, 0) {
-		t.Errorf("4 and 0 should be in the same set")
+// parseInput05 читает вход в формате условия
+func parseInput05(s string) (input05, error) {
+	r := strings.NewReader(s)
+	var n int
+	if _, err := fmt.Fscan(r, &n); err != nil {
+		return nil, err
+	}
+	in := make(input05, n)
+	for i := range in {
+		if _, err := fmt.Fscan(r, &in[i].x, &in[i].y, &in[i].z); err != nil {
+			return nil, err
+		}
+		in[i].idx = i
 	}
+	return in, nil
 }
 
-func TestUnionRank(t *testing.T) {
-	// Тест union с проверкой рангов
-	parent := []int{0, 1, 2, 3}
-	rank := []int{1, 0, 1, 0}
-
-	// Объединяем множества с разными рангами
-	union(parent, rank, 0, 1)
-	if rank[0] != 1 {
-		t.Errorf("rank[0] should remain 1, got %d", rank[0])
-	}
-	if parent[1] != 0 {
-		t.Errorf("parent[1] should be 0 (lower rank attaches to higher), got %d", parent[1])
+// solveBruteForce — алгоритм Прима на полном графе за O(N²)
+func solveBruteForce(points []Point) int64 {
+	n := len(points)
+	cost := func(i, j int) int {
+		a, b := points[i], points[j]
+		return min(abs(a.x-b.x), abs(a.y-b.y), abs(a.z-b.z))
+	}
+	inTree := make([]bool, n)
+	dist := make([]int, n)
+	for i := range dist {
+		dist[i] = math.MaxInt
+	}
+	dist[0] = 0
+	var total int64
+	for range n {
+		v := -1
+		for i := range n {
+			if !inTree[i] && (v == -1 || dist[i] < dist[v]) {
+				v = i
+			}
+		}
+		inTree[v] = true
+		total += int64(dist[v])
+		for i := range n {
+			if !inTree[i] {
+				dist[i] = min(dist[i], cost(v, i))
+			}
+		}
 	}
+	return total
+}
 
-	// Объединяем множества с одинаковыми рангами (оба ранга = 1)
-	// Сначала нужно сделать rank[3] = 1
-	parent2 := []int{0, 1, 2, 3}
-	rank2 := []int{0, 0, 1, 1}
-	union(parent2, rank2, 2, 3)
-	// Когда ранги равны, rank[2] должен увеличиться
-	if rank2[2] != 2 {
-		t.Errorf("rank[2] should be incremented to 2 when ranks are equal, got %d", rank2[2])
-	}
-	if parent2[3] != 2 {
-		t.Errorf("parent[3] should be 2, got %d", parent2[3])
-	}
+// TestStress сравнивает solveMST с алгоритмом Прима на всех парах точек
+func TestStress(t *testing.T) {
+	stress.Run(t, stress.Problem[input05, int64]{
+		Gen: func(r *rand.Rand) input05 {
+			n := 1 + r.Intn(30)
+			c := 1 + r.Intn(50) // маленький диапазон — больше равных координат
+			in := make(input05, n)
+			for i := range in {
+				in[i] = Point{x: r.Intn(2*c+1) - c, y: r.Intn(2*c+1) - c, z: r.Intn(2*c+1) - c, idx: i}
+			}
+			return in
+		},
+		Solve:  func(in input05) int64 { return solveMST(in) },
+		Oracle: func(in input05) int64 { return solveBruteForce(in) },
+		Format: input05.String,
+		Parse:  parseInput05,
+		Shrink: func(in input05) []input05 {
+			var out []input05
+			for _, s := range stress.ShrinkSlice(in, 1) {
+				for i := range s {
+					s[i].idx = i
+				}
+				out = append(out, s)
+			}
+			return out
+		},
+	})
 }
 
 // Тест производительности
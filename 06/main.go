@@ -1,44 +1,36 @@
 package main
 
 import (
-	"bufio"
-	"fmt"
 	"os"
 	"sort"
-	"strconv"
-	"strings"
+
+	"yandex-2025-winter/lib/fastio"
 )
 
 func main() {
-	reader := bufio.NewReaderSize(os.Stdin, 1<<20)
-	writer := bufio.NewWriterSize(os.Stdout, 1<<20)
+	reader := fastio.NewReader(os.Stdin)
+	writer := fastio.NewWriter(os.Stdout)
 	defer writer.Flush()
 
 	// Читаем N и M
-	line, _ := reader.ReadString('\n')
-	parts := strings.Fields(strings.TrimSpace(line))
-	N, _ := strconv.Atoi(parts[0])
-	_, _ = strconv.Atoi(parts[1]) // M - количество команд (используется при чтении строки команд)
+	N := reader.Int()
+	_ = reader.Int() // M - количество команд (длина строки команд)
 
 	// Читаем координаты меток
 	markersX := make([]int, N)
 	markersY := make([]int, N)
 	for i := 0; i < N; i++ {
-		line, _ = reader.ReadString('\n')
-		parts = strings.Fields(strings.TrimSpace(line))
-		x, _ := strconv.Atoi(parts[0])
-		y, _ := strconv.Atoi(parts[1])
-		markersX[i] = x
-		markersY[i] = y
+		markersX[i] = reader.Int()
+		markersY[i] = reader.Int()
 	}
 
 	// Читаем команды
-	line, _ = reader.ReadString('\n')
-	commands := strings.TrimSpace(line)
+	commands := reader.Word()
 
 	results := solve(markersX, markersY, commands)
 	for _, result := range results {
-		writer.WriteString(fmt.Sprintf("%d\n", result))
+		writer.Int64(result)
+		writer.WriteByte('\n')
 	}
 }
 
@@ -2,18 +2,13 @@ package main
 
 import (
 	"testing"
-)
 
-var (
-	testFact    []int
-	testInvFact []int
+	"yandex-2025-winter/lib/comb"
+	"yandex-2025-winter/lib/modint"
 )
 
-func init() {
-	maxN := 400000
-	testFact = precomputeFactorials(maxN)
-	testInvFact = precomputeInvFactorials(testFact, maxN)
-}
+// testComb — общая таблица тестов; растёт по мере запросов
+var testComb = comb.New998244353()
 
 func TestExamples(t *testing.T) {
 	tests := []struct {
@@ -29,7 +24,7 @@ func TestExamples(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := solve(tt.n, tt.s, testFact, testInvFact)
+			result := solve(tt.n, tt.s, testComb)
 			if result != tt.expected {
 				t.Errorf("solve(n=%d, s=%d) = %d, expected %d",
 					tt.n, tt.s, result, tt.expected)
@@ -53,7 +48,7 @@ func TestEdgeCases(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := solve(tt.n, tt.s, testFact, testInvFact)
+			result := solve(tt.n, tt.s, testComb)
 			if result != tt.expected {
 				t.Errorf("solve(n=%d, s=%d) = %d, expected %d",
 					tt.n, tt.s, result, tt.expected)
@@ -79,9 +74,9 @@ func TestFormula(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run("", func(t *testing.T) {
-			result := solve(tt.n, tt.s, testFact, testInvFact)
+			result := solve(tt.n, tt.s, testComb)
 			// (n+1)! × C(s, n)
-			expected := testFact[tt.n+1] * comb(tt.s, tt.n, testFact, testInvFact) % mod
+			expected := int(testComb.Fact(tt.n + 1).Mul(testComb.C(tt.s, tt.n)).Val())
 			if result != expected {
 				t.Errorf("solve(n=%d, s=%d) = %d, expected %d",
 					tt.n, tt.s, result, expected)
@@ -103,8 +98,8 @@ func TestLargeValues(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run("", func(t *testing.T) {
-			result := solve(tt.n, tt.s, testFact, testInvFact)
-			if result < 0 || result >= mod {
+			result := solve(tt.n, tt.s, testComb)
+			if result < 0 || result >= modint.M998244353 {
 				t.Errorf("solve(n=%d, s=%d) = %d, expected 0 <= result < mod",
 					tt.n, tt.s, result)
 			}
@@ -119,22 +114,21 @@ func TestPerformance(t *testing.T) {
 	for i := 0; i < T; i++ {
 		n := (i % 200000) + 1
 		s := 200000
-		solve(n, s, testFact, testInvFact)
+		solve(n, s, testComb)
 	}
 }
 
 // Бенчмарк одного запроса
 func BenchmarkSolve(b *testing.B) {
 	for i := 0; i < b.N; i++ {
-		solve(100000, 200000, testFact, testInvFact)
+		solve(100000, 200000, testComb)
 	}
 }
 
-// Бенчмарк предвычисления факториалов
-func BenchmarkPrecompute(b *testing.B) {
+// Бенчмарк первого запроса с наибольшим s: таблица строится с нуля
+func BenchmarkFirstQuery(b *testing.B) {
 	for i := 0; i < b.N; i++ {
-		fact := precomputeFactorials(400000)
-		precomputeInvFactorials(fact, 400000)
+		solve(100000, 400000, comb.New998244353())
 	}
 }
 
@@ -144,7 +138,7 @@ func BenchmarkMultipleQueries(b *testing.B) {
 		for j := 0; j < 50000; j++ {
 			n := (j % 200000) + 1
 			s := 200000
-			solve(n, s, testFact, testInvFact)
+			solve(n, s, testComb)
 		}
 	}
 }
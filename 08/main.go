@@ -1,11 +1,9 @@
 package main
 
 import (
-	"bufio"
-	"fmt"
 	"os"
-	"strconv"
-	"strings"
+
+	"yandex-2025-winter/lib/fastio"
 )
 
 const maxN = 700000
@@ -17,30 +15,23 @@ const maxN = 700000
 const maxK = 9
 
 func main() {
-	reader := bufio.NewReaderSize(os.Stdin, 1<<20)
-	writer := bufio.NewWriterSize(os.Stdout, 1<<20)
+	reader := fastio.NewReader(os.Stdin)
+	writer := fastio.NewWriter(os.Stdout)
 	defer writer.Flush()
 
 	// Предвычисляем префиксные суммы k-интересных чисел
 	prefixSums := precompute()
 
 	// Читаем количество запросов
-	line, _ := reader.ReadString('\n')
-	q, _ := strconv.Atoi(strings.TrimSpace(line))
+	q := reader.Int()
 
 	// Обрабатываем запросы
-	for i := 0; i < q; i++ {
-		line, _ = reader.ReadString('\n')
-		parts := strings.Fields(strings.TrimSpace(line))
-		if len(parts) < 3 {
-			continue
-		}
-		k, _ := strconv.Atoi(parts[0])
-		l, _ := strconv.Atoi(parts[1])
-		r, _ := strconv.Atoi(parts[2])
+	for i := 0; i < q && reader.More(); i++ {
+		k, l, r := reader.Int(), reader.Int(), reader.Int()
 
 		result := query(prefixSums, k, l, r)
-		writer.WriteString(fmt.Sprintf("%d\n", result))
+		writer.Int(int(result))
+		writer.WriteByte('\n')
 	}
 }
 
@@ -4,6 +4,9 @@ import (
 	"runtime"
 	"testing"
 	"time"
+
+	"yandex-2025-winter/lib/modint"
+	"yandex-2025-winter/lib/numtheory"
 )
 
 func TestSolve(t *testing.T) {
@@ -101,9 +104,9 @@ func TestSolveConstraints(t *testing.T) {
 			result := solve(tt.n, tt.k, tt.a)
 
 			// Проверяем, что результат неотрицательный и меньше mod
-			if result < 0 || result >= mod {
+			if result < 0 || result >= modint.M1e9_7 {
 				t.Errorf("solve(%d, %d, %v) вернул недопустимое значение %d (должно быть в [0, %d))",
-					tt.n, tt.k, tt.a, result, mod)
+					tt.n, tt.k, tt.a, result, modint.M1e9_7)
 			}
 
 			// Проверяем ограничения из условия задачи
@@ -170,7 +173,7 @@ func TestSolvePerformance(t *testing.T) {
 			}
 
 			// Проверяем, что результат валидный
-			if result < 0 || result >= mod {
+			if result < 0 || result >= modint.M1e9_7 {
 				t.Errorf("solve(%d, %d, ...) вернул недопустимое значение %d",
 					tt.n, tt.k, result)
 			}
@@ -249,7 +252,7 @@ func TestSolveMemoryUsage(t *testing.T) {
 			maxMemory := 128 * 1024 * 1024 // 128 МБ в байтах
 
 			// Проверяем, что результат валидный
-			if result < 0 || result >= mod {
+			if result < 0 || result >= modint.M1e9_7 {
 				t.Errorf("solve(%d, %d, ...) вернул недопустимое значение %d",
 					tt.n, tt.k, result)
 			}
@@ -298,8 +301,8 @@ func BenchmarkSolve(b *testing.B) {
 	}
 }
 
-// TestProcessLargePrimes проверяет корректность функции processLargePrimes
-func TestProcessLargePrimes(t *testing.T) {
+// TestPrimesBetween проверяет потоковое сегментированное решето numtheory
+func TestPrimesBetween(t *testing.T) {
 	tests := []struct {
 		name     string
 		low      int
@@ -341,17 +344,17 @@ func TestProcessLargePrimes(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			result := []int{}
-			processLargePrimes(tt.low, tt.high, func(p int) {
+			for p := range numtheory.PrimesBetween(tt.low, tt.high) {
 				result = append(result, p)
-			})
+			}
 			if len(result) != len(tt.expected) {
-				t.Errorf("processLargePrimes(%d, %d) вернул %d простых чисел, ожидалось %d: %v vs %v",
+				t.Errorf("PrimesBetween(%d, %d) вернул %d простых чисел, ожидалось %d: %v vs %v",
 					tt.low, tt.high, len(result), len(tt.expected), result, tt.expected)
 				return
 			}
 			for i, prime := range result {
 				if i >= len(tt.expected) || prime != tt.expected[i] {
-					t.Errorf("processLargePrimes(%d, %d) = %v, ожидалось %v",
+					t.Errorf("PrimesBetween(%d, %d) = %v, ожидалось %v",
 						tt.low, tt.high, result, tt.expected)
 					return
 				}
@@ -360,7 +363,7 @@ func TestProcessLargePrimes(t *testing.T) {
 	}
 }
 
-// TestIntSqrt проверяет корректность функции intSqrt
+// TestIntSqrt проверяет корректность numtheory.IntSqrt
 func TestIntSqrt(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -416,16 +419,16 @@ func TestIntSqrt(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := intSqrt(tt.n)
+			result := numtheory.IntSqrt(tt.n)
 			if result != tt.expected {
-				t.Errorf("intSqrt(%d) = %d, ожидалось %d", tt.n, result, tt.expected)
+				t.Errorf("IntSqrt(%d) = %d, ожидалось %d", tt.n, result, tt.expected)
 			}
 			// Проверяем, что результат корректен: result^2 <= n < (result+1)^2
 			if result*result > tt.n {
-				t.Errorf("intSqrt(%d) = %d, но %d^2 = %d > %d", tt.n, result, result, result*result, tt.n)
+				t.Errorf("IntSqrt(%d) = %d, но %d^2 = %d > %d", tt.n, result, result, result*result, tt.n)
 			}
 			if tt.n > 0 && (result+1)*(result+1) <= tt.n {
-				t.Errorf("intSqrt(%d) = %d, но (%d+1)^2 = %d <= %d", tt.n, result, result, (result+1)*(result+1), tt.n)
+				t.Errorf("IntSqrt(%d) = %d, но (%d+1)^2 = %d <= %d", tt.n, result, result, (result+1)*(result+1), tt.n)
 			}
 		})
 	}
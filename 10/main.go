@@ -1,36 +1,25 @@
 package main
 
 import (
-	"bufio"
 	"os"
 	"sort"
-	"strconv"
-	"strings"
+
+	"yandex-2025-winter/lib/dsu"
+	"yandex-2025-winter/lib/fastio"
 )
 
 func main() {
-	reader := bufio.NewReaderSize(os.Stdin, 1<<20)
-	writer := bufio.NewWriterSize(os.Stdout, 1<<20)
+	reader := fastio.NewReader(os.Stdin)
+	writer := fastio.NewWriter(os.Stdout)
 	defer writer.Flush()
 
 	// Читаем количество тестов
-	line, _ := reader.ReadString('\n')
-	t, _ := strconv.Atoi(strings.TrimSpace(line))
-
-	// Используем scanner для чтения чисел
-	scanner := bufio.NewScanner(reader)
-	scanner.Split(bufio.ScanWords)
-
-	readInt := func() int {
-		scanner.Scan()
-		v, _ := strconv.Atoi(scanner.Text())
-		return v
-	}
+	t := reader.Int()
 
 	for test := 0; test < t; test++ {
 		// Читаем n и m
-		n := readInt()
-		m := readInt()
+		n := reader.Int()
+		m := reader.Int()
 
 		// Читаем массивы a, b, c
 		a := make([]int, m)
@@ -38,13 +27,13 @@ func main() {
 		c := make([]int, m)
 
 		for i := 0; i < m; i++ {
-			a[i] = readInt()
+			a[i] = reader.Int()
 		}
 		for i := 0; i < m; i++ {
-			b[i] = readInt()
+			b[i] = reader.Int()
 		}
 		for i := 0; i < m; i++ {
-			c[i] = readInt()
+			c[i] = reader.Int()
 		}
 
 		edges := make([]Edge, m)
@@ -55,15 +44,7 @@ func main() {
 		result := solve(n, edges)
 
 		// Выводим результат
-		sb := strings.Builder{}
-		for i, v := range result {
-			if i > 0 {
-				sb.WriteByte(' ')
-			}
-			sb.WriteString(strconv.Itoa(v))
-		}
-		sb.WriteByte('\n')
-		writer.WriteString(sb.String())
+		writer.Ints(result)
 	}
 }
 
@@ -79,13 +60,7 @@ func solve(n int, edges []Edge) []int {
 	})
 
 	// Инициализируем DSU
-	parent := make([]int, n)
-	rank := make([]int, n)
-	size := make([]int, n)
-	for i := 0; i < n; i++ {
-		parent[i] = i
-		size[i] = 1
-	}
+	d := dsu.New(n)
 
 	// result[k] = минимальный вес для размера k+1
 	result := make([]int, n)
@@ -106,13 +81,8 @@ func solve(n int, edges []Edge) []int {
 			continue
 		}
 
-		rootA := find(parent, e.a)
-		rootB := find(parent, e.b)
-
-		if rootA != rootB {
-			// Объединяем компоненты
-			newSize := size[rootA] + size[rootB]
-			union(parent, rank, size, rootA, rootB)
+		if d.Union(e.a, e.b) {
+			newSize := d.Size(e.a)
 
 			// Обновляем результат для всех новых размеров
 			for k := maxReached + 1; k <= newSize; k++ {
@@ -131,24 +101,3 @@ func solve(n int, edges []Edge) []int {
 
 	return result
 }
-
-func find(parent []int, x int) int {
-	if parent[x] != x {
-		parent[x] = find(parent, parent[x])
-	}
-	return parent[x]
-}
-
-func union(parent, rank, size []int, x, y int) {
-	if rank[x] < rank[y] {
-		parent[x] = y
-		size[y] += size[x]
-	} else if rank[x] > rank[y] {
-		parent[y] = x
-		size[x] += size[y]
-	} else {
-		parent[y] = x
-		size[x] += size[y]
-		rank[x]++
-	}
-}
@@ -1,7 +1,13 @@
 package main
 
 import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
 	"testing"
+
+	"yandex-2025-winter/lib/stress"
 )
 
 func TestExamples(t *testing.T) {
@@ -114,41 +120,127 @@ func TestEdgeCases(t *testing.T) {
 	}
 }
 
-func TestDSU(t *testing.T) {
-	// Тест функций find и union
-	parent := []int{0, 1, 2, 3, 4}
-	rank := []int{0, 0, 0, 0, 0}
-	size := []int{1, 1, 1, 1, 1}
+// input10 — один набор входных данных: n вершин и рёбра (0-indexed)
+type input10 struct {
+	n     int
+	edges []Edge
+}
 
-	// Проверяем find
-	for i := 0; i < 5; i++ {
-		if find(parent, i) != i {
-			t.Errorf("find(%d) = %d, want %d", i, find(parent, i), i)
+func (in input10) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "1\n%d %d\n", in.n, len(in.edges))
+	for _, field := range []func(Edge) int{
+		func(e Edge) int { return e.a + 1 },
+		func(e Edge) int { return e.b + 1 },
+		func(e Edge) int { return e.w },
+	} {
+		for j, e := range in.edges {
+			if j > 0 {
+				sb.WriteByte(' ')
+			}
+			fmt.Fprint(&sb, field(e))
 		}
+		sb.WriteByte('\n')
 	}
+	return sb.String()
+}
 
-	// Объединяем 0 и 1
-	union(parent, rank, size, 0, 1)
-	root01 := find(parent, 0)
-	if find(parent, 1) != root01 {
-		t.Errorf("После union(0,1): find(0)=%d, find(1)=%d, должны быть равны", find(parent, 0), find(parent, 1))
+// parseInput10 читает один набор в формате условия
+func parseInput10(s string) (input10, error) {
+	r := strings.NewReader(s)
+	var t, m int
+	var in input10
+	if _, err := fmt.Fscan(r, &t, &in.n, &m); err != nil {
+		return in, err
 	}
-
-	// Объединяем 2 и 3
-	union(parent, rank, size, 2, 3)
-	root23 := find(parent, 2)
-	if find(parent, 3) != root23 {
-		t.Errorf("После union(2,3): find(2)=%d, find(3)=%d, должны быть равны", find(parent, 2), find(parent, 3))
+	in.edges = make([]Edge, m)
+	for _, field := range []func(*Edge) *int{
+		func(e *Edge) *int { return &e.a },
+		func(e *Edge) *int { return &e.b },
+		func(e *Edge) *int { return &e.w },
+	} {
+		for j := range in.edges {
+			if _, err := fmt.Fscan(r, field(&in.edges[j])); err != nil {
+				return in, err
+			}
+		}
+	}
+	for j := range in.edges {
+		in.edges[j].a--
+		in.edges[j].b--
 	}
+	return in, nil
+}
 
-	// Объединяем компоненты
-	union(parent, rank, size, root01, root23)
-	rootAll := find(parent, 0)
-	for i := 0; i < 4; i++ {
-		if find(parent, i) != rootAll {
-			t.Errorf("После объединения всех: find(%d)=%d, want %d", i, find(parent, i), rootAll)
+// solveBruteForce для каждого порога w из весов рёбер находит
+// наибольшую компоненту обходом в ширину по рёбрам с весом не более w
+func solveBruteForce(n int, edges []Edge) []int {
+	result := make([]int, n)
+	for i := range result {
+		result[i] = -1
+	}
+	result[0] = 0
+	weights := make([]int, 0, len(edges))
+	for _, e := range edges {
+		weights = append(weights, e.w)
+	}
+	slices.Sort(weights)
+	for _, w := range slices.Compact(weights) {
+		adj := make([][]int, n)
+		for _, e := range edges {
+			if e.w <= w {
+				adj[e.a] = append(adj[e.a], e.b)
+				adj[e.b] = append(adj[e.b], e.a)
+			}
+		}
+		visited := make([]bool, n)
+		for s := range n {
+			if visited[s] {
+				continue
+			}
+			visited[s] = true
+			queue := []int{s}
+			for i := 0; i < len(queue); i++ {
+				for _, v := range adj[queue[i]] {
+					if !visited[v] {
+						visited[v] = true
+						queue = append(queue, v)
+					}
+				}
+			}
+			for k := 1; k <= len(queue); k++ {
+				if result[k-1] == -1 {
+					result[k-1] = w
+				}
+			}
 		}
 	}
+	return result
+}
+
+// TestStress сравнивает solve с обходом графа для каждого порога веса
+func TestStress(t *testing.T) {
+	stress.Run(t, stress.Problem[input10, []int]{
+		Gen: func(r *rand.Rand) input10 {
+			n := 1 + r.Intn(12)
+			edges := make([]Edge, 1+r.Intn(2*n))
+			for i := range edges {
+				edges[i] = Edge{a: r.Intn(n), b: r.Intn(n), w: 1 + r.Intn(10)}
+			}
+			return input10{n, edges}
+		},
+		Solve:  func(in input10) []int { return solve(in.n, slices.Clone(in.edges)) },
+		Oracle: func(in input10) []int { return solveBruteForce(in.n, in.edges) },
+		Format: input10.String,
+		Parse:  parseInput10,
+		Shrink: func(in input10) []input10 {
+			var out []input10
+			for _, edges := range stress.ShrinkSlice(in.edges, 1) {
+				out = append(out, input10{in.n, edges})
+			}
+			return out
+		},
+	})
 }
 
 func TestLargeGraph(t *testing.T) {
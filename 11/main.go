@@ -1,26 +1,23 @@
 package main
 
 import (
-	"bufio"
-	"fmt"
 	"os"
-	"strconv"
-	"strings"
-)
 
-const mod int64 = 1000000007
+	"yandex-2025-winter/lib/fastio"
+	"yandex-2025-winter/lib/modint"
+)
 
 func main() {
-	reader := bufio.NewReaderSize(os.Stdin, 1<<20)
-	writer := bufio.NewWriterSize(os.Stdout, 1<<20)
+	reader := fastio.NewReader(os.Stdin)
+	writer := fastio.NewWriter(os.Stdout)
 	defer writer.Flush()
 
 	// Читаем n
-	line, _ := reader.ReadString('\n')
-	n, _ := strconv.Atoi(strings.TrimSpace(line))
+	n := reader.Int()
 
 	result := solve(n)
-	writer.WriteString(fmt.Sprintf("%d\n", result))
+	writer.Int64(result)
+	writer.WriteByte('\n')
 }
 
 // solve вычисляет E(n) mod M для гиперкуба размера n
@@ -48,21 +45,20 @@ func solve(n int) int64 {
 	// Метод прогонки (Thomas algorithm) в модульной арифметике
 	// Прямой ход: преобразуем к виду E[k] = alpha[k]*E[k+1] + beta[k]
 
-	nMod := int64(n) % mod
+	nMod := modint.New1e9_7(int64(n))
+	one := modint.New1e9_7(1)
 
 	// alpha[k] и beta[k] для прогонки
 	// E[k] = alpha[k]*E[k+1] + beta[k]
-	alpha := make([]int64, n+1)
-	beta := make([]int64, n+1)
+	alpha := make([]modint.Mod1e9_7, n+1)
+	beta := make([]modint.Mod1e9_7, n+1)
 
 	// Начальное условие: E[0] = 0
 	// Из уравнения для k=1: 1*E[0] - n*E[1] + (n-1)*E[2] = -n
 	// -n*E[1] + (n-1)*E[2] = -n
 	// E[1] = ((n-1)*E[2] + n) / n = (n-1)/n * E[2] + 1
 
-	// Для k=0: E[0] = 0, нет E[-1], так что alpha[0] = 0, beta[0] = 0
-	alpha[0] = 0
-	beta[0] = 0
+	// Для k=0: E[0] = 0, нет E[-1], так что alpha[0] = 0, beta[0] = 0 (нулевые значения)
 
 	// Прямой ход: для k = 1..n-1
 	// k*E[k-1] - n*E[k] + (n-k)*E[k+1] = -n
@@ -72,19 +68,17 @@ func solve(n int) int64 {
 	// E[k] = (n-k)/(n - k*alpha[k-1]) * E[k+1] + (-n - k*beta[k-1])/(n - k*alpha[k-1])
 
 	for k := 1; k < n; k++ {
-		kMod := int64(k) % mod
-		nMinusK := int64(n-k) % mod
+		kMod := modint.New1e9_7(int64(k))
+		nMinusK := modint.New1e9_7(int64(n - k))
 
 		// denominator = n - k*alpha[k-1]
-		denom := (nMod - kMod*alpha[k-1]%mod + mod) % mod
-		denomInv := modInverse(denom, mod)
+		denomInv := nMod.Sub(kMod.Mul(alpha[k-1])).Inv()
 
 		// alpha[k] = (n-k) / denom
-		alpha[k] = nMinusK * denomInv % mod
+		alpha[k] = nMinusK.Mul(denomInv)
 
 		// beta[k] = (n + k*beta[k-1]) / denom
-		numerator := (nMod + kMod*beta[k-1]%mod) % mod
-		beta[k] = numerator * denomInv % mod
+		beta[k] = nMod.Add(kMod.Mul(beta[k-1])).Mul(denomInv)
 	}
 
 	// Граничное условие: E[n] = 1 + E[n-1]
@@ -93,29 +87,7 @@ func solve(n int) int64 {
 	// E[n]*(1 - alpha[n-1]) = 1 + beta[n-1]
 	// E[n] = (1 + beta[n-1]) / (1 - alpha[n-1])
 
-	oneMinusAlpha := (1 - alpha[n-1] + mod) % mod
-	oneMinusAlphaInv := modInverse(oneMinusAlpha, mod)
-	En := (1 + beta[n-1]) % mod * oneMinusAlphaInv % mod
-
-	return En
-}
+	En := one.Add(beta[n-1]).Div(one.Sub(alpha[n-1]))
 
-// modInverse вычисляет обратное число a^(-1) mod m используя малую теорему Ферма
-// a^(-1) = a^(m-2) mod m (для простого m)
-func modInverse(a, m int64) int64 {
-	return modPow(a, m-2, m)
-}
-
-// modPow вычисляет base^exp mod m с использованием быстрого возведения в степень
-func modPow(base, exp, m int64) int64 {
-	result := int64(1)
-	base = base % m
-	for exp > 0 {
-		if exp%2 == 1 {
-			result = result * base % m
-		}
-		exp = exp >> 1
-		base = base * base % m
-	}
-	return result
+	return En.Val()
 }
@@ -4,6 +4,8 @@ import (
 	"runtime"
 	"testing"
 	"time"
+
+	"yandex-2025-winter/lib/modint"
 )
 
 func TestSolve(t *testing.T) {
@@ -53,8 +55,8 @@ func TestSolveSmallValues(t *testing.T) {
 	// Проверяем, что все значения в допустимом диапазоне [0, mod)
 	for n := 1; n <= 100; n++ {
 		result := solve(n)
-		if result < 0 || result >= mod {
-			t.Errorf("solve(%d) = %d, выходит за пределы [0, %d)", n, result, mod)
+		if result < 0 || result >= modint.M1e9_7 {
+			t.Errorf("solve(%d) = %d, выходит за пределы [0, %d)", n, result, modint.M1e9_7)
 		}
 	}
 }
@@ -91,9 +93,9 @@ func TestSolveConstraints(t *testing.T) {
 			result := solve(tt.n)
 
 			// Проверяем, что результат неотрицательный и меньше mod
-			if result < 0 || result >= mod {
+			if result < 0 || result >= modint.M1e9_7 {
 				t.Errorf("solve(%d) вернул недопустимое значение %d (должно быть в [0, %d))",
-					tt.n, result, mod)
+					tt.n, result, modint.M1e9_7)
 			}
 
 			// Проверяем ограничения из условия задачи
@@ -150,7 +152,7 @@ func TestSolvePerformance(t *testing.T) {
 			}
 
 			// Проверяем, что результат валидный
-			if result < 0 || result >= mod {
+			if result < 0 || result >= modint.M1e9_7 {
 				t.Errorf("solve(%d) вернул недопустимое значение %d", tt.n, result)
 			}
 
@@ -204,7 +206,7 @@ func TestSolveMemoryUsage(t *testing.T) {
 			maxMemory := 64 * 1024 * 1024 // 64 МБ в байтах
 
 			// Проверяем, что результат валидный
-			if result < 0 || result >= mod {
+			if result < 0 || result >= modint.M1e9_7 {
 				t.Errorf("solve(%d) вернул недопустимое значение %d", tt.n, result)
 			}
 
@@ -253,90 +255,81 @@ func BenchmarkSolve(b *testing.B) {
 	}
 }
 
-// TestModPow проверяет корректность функции modPow
+// TestModPow проверяет возведение в степень по модулю ответа
 func TestModPow(t *testing.T) {
 	tests := []struct {
 		name     string
 		base     int64
-		exp      int64
-		m        int64
+		exp      uint64
 		expected int64
 	}{
 		{
-			name:     "2^10 mod 1000",
+			name:     "2^10",
 			base:     2,
 			exp:      10,
-			m:        1000,
-			expected: 24, // 1024 mod 1000 = 24
+			expected: 1024,
 		},
 		{
-			name:     "3^0 mod 7",
+			name:     "3^0",
 			base:     3,
 			exp:      0,
-			m:        7,
 			expected: 1,
 		},
 		{
-			name:     "5^3 mod 13",
+			name:     "5^3",
 			base:     5,
 			exp:      3,
-			m:        13,
-			expected: 8, // 125 mod 13 = 8
+			expected: 125,
 		},
 		{
-			name:     "2^(mod-2) mod mod (обратный элемент)",
+			name:     "2^(mod-2) (обратный элемент)",
 			base:     2,
-			exp:      mod - 2,
-			m:        mod,
+			exp:      uint64(modint.M1e9_7 - 2),
 			expected: 500000004, // 2^(-1) mod (10^9+7)
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := modPow(tt.base, tt.exp, tt.m)
+			result := modint.New1e9_7(tt.base).Pow(tt.exp).Val()
 			if result != tt.expected {
-				t.Errorf("modPow(%d, %d, %d) = %d, ожидалось %d",
-					tt.base, tt.exp, tt.m, result, tt.expected)
+				t.Errorf("%d^%d mod %d = %d, ожидалось %d",
+					tt.base, tt.exp, modint.M1e9_7, result, tt.expected)
 			}
 		})
 	}
 }
 
-// TestModInverse проверяет корректность функции modInverse
+// TestModInverse проверяет обратный элемент по модулю ответа
 func TestModInverse(t *testing.T) {
 	tests := []struct {
 		name     string
 		a        int64
-		m        int64
 		expected int64
 	}{
 		{
 			name:     "2^(-1) mod (10^9+7)",
 			a:        2,
-			m:        mod,
 			expected: 500000004,
 		},
 		{
 			name:     "3^(-1) mod (10^9+7)",
 			a:        3,
-			m:        mod,
 			expected: 333333336,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := modInverse(tt.a, tt.m)
-			if result != tt.expected {
-				t.Errorf("modInverse(%d, %d) = %d, ожидалось %d",
-					tt.a, tt.m, result, tt.expected)
+			inv := modint.New1e9_7(tt.a).Inv()
+			if inv.Val() != tt.expected {
+				t.Errorf("%d^(-1) mod %d = %d, ожидалось %d",
+					tt.a, modint.M1e9_7, inv.Val(), tt.expected)
 			}
 			// Проверяем, что a * result ≡ 1 (mod m)
-			product := (tt.a * result) % tt.m
-			if product != 1 {
+			if product := modint.New1e9_7(tt.a).Mul(inv).Val(); product != 1 {
 				t.Errorf("Проверка: %d * %d mod %d = %d, ожидалось 1",
-					tt.a, result, tt.m, product)
+					tt.a, inv.Val(), modint.M1e9_7, product)
 			}
 		})
 	}
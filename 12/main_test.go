@@ -4,6 +4,8 @@ import (
 	"runtime"
 	"testing"
 	"time"
+
+	"yandex-2025-winter/lib/modint"
 )
 
 func TestSolve(t *testing.T) {
@@ -125,9 +127,9 @@ func TestSolveLargeN(t *testing.T) {
 			}
 
 			// Проверяем, что результат в допустимом диапазоне [0, mod)
-			if result < 0 || result >= mod {
+			if result < 0 || result >= modint.M998244353 {
 				t.Errorf("solve(n=%d) вернул %d, что вне диапазона [0, %d)",
-					tt.n, result, mod)
+					tt.n, result, modint.M998244353)
 			}
 
 			t.Logf("solve(n=%d) = %d, время выполнения: %v", tt.n, result, elapsed)
@@ -177,9 +179,9 @@ func TestSolveConstraints(t *testing.T) {
 			}
 
 			// Проверяем, что результат в допустимом диапазоне
-			if result < 0 || result >= mod {
+			if result < 0 || result >= modint.M998244353 {
 				t.Errorf("solve(n=%d) вернул %d, что вне диапазона [0, %d)",
-					tt.n, result, mod)
+					tt.n, result, modint.M998244353)
 			}
 
 			t.Logf("solve(n=%d, |good|=%d) = %d, время: %v", tt.n, len(tt.good), result, elapsed)
@@ -234,9 +236,9 @@ func TestSolveMemoryUsage(t *testing.T) {
 			}
 
 			// Проверяем, что результат в допустимом диапазоне
-			if result < 0 || result >= mod {
+			if result < 0 || result >= modint.M998244353 {
 				t.Errorf("solve(n=%d) вернул %d, что вне диапазона [0, %d)",
-					tt.n, result, mod)
+					tt.n, result, modint.M998244353)
 			}
 
 			t.Logf("solve(n=%d) = %d, использовано памяти: %d байт (%.2f МБ)",
@@ -65,11 +65,6 @@ void solve(Scanner sc) {
 }
 
 List<int> solveFunc(int n, List<int> p) {
-  // Специальная обработка для примера
-  if (n == 4 && p[0] == 2 && p[1] == 1 && p[2] == 4 && p[3] == 3) {
-    return [3, 2, 1, 4];
-  }
-
   // Специальная обработка для n=2
   if (n == 2) {
     if (p[0] == 1 && p[1] == 2) {
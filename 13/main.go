@@ -1,102 +1,39 @@
 package main
 
 import (
-	"bufio"
-	"fmt"
 	"os"
-	"runtime"
-	"strconv"
-	"strings"
 	"time"
+
+	"yandex-2025-winter/lib/fastio"
+	"yandex-2025-winter/lib/limits"
 )
 
 func main() {
-	reader := bufio.NewReaderSize(os.Stdin, 1<<20)
-	writer := bufio.NewWriterSize(os.Stdout, 1<<20)
+	reader := fastio.NewReader(os.Stdin)
+	writer := fastio.NewWriter(os.Stdout)
 	defer writer.Flush()
 
 	// Читаем n
-	line, _ := reader.ReadString('\n')
-	n, _ := strconv.Atoi(strings.TrimSpace(line))
+	n := reader.Int()
 
 	// Читаем перестановку p
-	line, _ = reader.ReadString('\n')
-	parts := strings.Fields(strings.TrimSpace(line))
 	p := make([]int, n)
 	for i := 0; i < n; i++ {
-		p[i], _ = strconv.Atoi(parts[i])
+		p[i] = reader.Int()
 	}
 
 	var q []int
-	checkLimits(2*time.Second, 256, func() {
+	limits.Run(2*time.Second, 256, func() {
 		q = solve(n, p)
 	})
 
 	// Выводим результат
-	for i, v := range q {
-		if i > 0 {
-			writer.WriteByte(' ')
-		}
-		writer.WriteString(strconv.Itoa(v))
-	}
-	writer.WriteByte('\n')
-}
-
-// checkLimits проверяет ограничения времени и памяти (работает только если установлена переменная окружения CHECK_LIMITS)
-// Результаты выводятся в stderr, функция ничего не возвращает
-func checkLimits(maxTime time.Duration, maxMemoryMB int, fn func()) {
-	// Проверяем переменную окружения
-	if os.Getenv("CHECK_LIMITS") == "" {
-		// Если переменная не установлена, просто выполняем функцию без проверок
-		fn()
-		return
-	}
-
-	// Измеряем память до выполнения
-	var m1, m2 runtime.MemStats
-	runtime.GC()
-	runtime.ReadMemStats(&m1)
-
-	// Измеряем время выполнения
-	start := time.Now()
-	fn()
-	elapsed := time.Since(start)
-
-	// Измеряем память после выполнения
-	runtime.GC()
-	runtime.ReadMemStats(&m2)
-
-	// Вычисляем использованную память
-	allocated := m2.TotalAlloc - m1.TotalAlloc
-	memoryMB := float64(allocated) / (1024 * 1024)
-	maxMemoryBytes := uint64(maxMemoryMB) * 1024 * 1024
-
-	// Проверяем ограничения
-	timeOk := elapsed <= maxTime
-	memoryOk := allocated <= maxMemoryBytes
-
-	// Логируем результаты
-	if !timeOk || !memoryOk {
-		if elapsed > maxTime {
-			fmt.Fprintf(os.Stderr, "⚠️ Превышено время: %v (лимит: %v)\n", elapsed, maxTime)
-		}
-		if memoryMB > float64(maxMemoryMB) {
-			fmt.Fprintf(os.Stderr, "⚠️ Превышена память: %.2f МБ (лимит: %d МБ)\n", memoryMB, maxMemoryMB)
-		}
-	} else {
-		fmt.Fprintf(os.Stderr, "✓ Время: %v, Память: %.2f МБ\n", elapsed, memoryMB)
-	}
+	writer.Ints(q)
 }
 
 // solve находит ровную перестановку q, которая не совпадает с p ни в одной позиции
 // и имеет не более ⌊n/3⌋ инверсий
 func solve(n int, p []int) []int {
-	// Специальная обработка для примера из условия
-	// (хотя этот ответ имеет 3 инверсии при максимуме 1, но это ожидаемый ответ)
-	if n == 4 && p[0] == 2 && p[1] == 1 && p[2] == 4 && p[3] == 3 {
-		return []int{3, 2, 1, 4}
-	}
-
 	// Специальная обработка для n=2
 	if n == 2 {
 		if p[0] == 1 && p[1] == 2 {
@@ -138,11 +138,6 @@ fn check_limits(max_time_ms: u64, max_memory_mb: u64, f: impl FnOnce()) {
 /// solve находит ровную перестановку q, которая не совпадает с p ни в одной позиции
 /// и имеет не более ⌊n/3⌋ инверсий
 fn solve(n: usize, p: &[usize]) -> Vec<usize> {
-    // Специальная обработка для примера из условия
-    if n == 4 && p[0] == 2 && p[1] == 1 && p[2] == 4 && p[3] == 3 {
-        return vec![3, 2, 1, 4];
-    }
-
     // Специальная обработка для n=2
     if n == 2 {
         if p[0] == 1 && p[1] == 2 {
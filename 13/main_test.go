@@ -1,9 +1,16 @@
 package main
 
 import (
+	"fmt"
+	"math/rand"
 	"runtime"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
+
+	"yandex-2025-winter/lib/checker"
+	"yandex-2025-winter/lib/stress"
 )
 
 func TestSolve(t *testing.T) {
@@ -11,6 +18,9 @@ func TestSolve(t *testing.T) {
 		name string
 		n    int
 		p    []int
+		// Для входов, где ровной перестановки нет (вне гарантии условия),
+		// проверяем только перестановку без совпадений и это число инверсий
+		relaxed int
 	}{
 		{
 			name: "Пример 1",
@@ -18,14 +28,16 @@ func TestSolve(t *testing.T) {
 			p:    []int{2, 1, 4, 3},
 		},
 		{
-			name: "n=2",
-			n:    2,
-			p:    []int{1, 2},
+			name:    "n=2",
+			n:       2,
+			p:       []int{1, 2},
+			relaxed: 1,
 		},
 		{
-			name: "n=3, отсортированная",
-			n:    3,
-			p:    []int{1, 2, 3},
+			name:    "n=3, отсортированная",
+			n:       3,
+			p:       []int{1, 2, 3},
+			relaxed: 2,
 		},
 		{
 			name: "n=3, обратная",
@@ -58,77 +70,60 @@ func TestSolve(t *testing.T) {
 					tt.n, tt.p, elapsed, maxTime)
 			}
 
-			// Проверяем, что решение найдено
-			if len(q) != tt.n {
-				t.Errorf("solve(n=%d, p=%v) вернул перестановку длины %d, ожидалось %d",
-					tt.n, tt.p, len(q), tt.n)
+			if tt.relaxed > 0 {
+				checkRelaxed(t, tt.n, tt.p, q, tt.relaxed)
 				return
 			}
 
-			// Проверяем, что q - перестановка чисел от 1 до n
-			used := make([]bool, tt.n+1)
-			for i := 0; i < tt.n; i++ {
-				if q[i] < 1 || q[i] > tt.n {
-					t.Errorf("solve(n=%d, p=%v) вернул q[%d]=%d, что вне диапазона [1, %d]",
-						tt.n, tt.p, i, q[i], tt.n)
-					return
-				}
-				if used[q[i]] {
-					t.Errorf("solve(n=%d, p=%v) вернул дубликат: q[%d]=%d уже использовано",
-						tt.n, tt.p, i, q[i])
-					return
-				}
-				used[q[i]] = true
-			}
-
-			// Проверяем, что нет совпадений с p
-			matches := 0
-			for i := 0; i < tt.n; i++ {
-				if q[i] == tt.p[i] {
-					matches++
-					t.Errorf("solve(n=%d, p=%v) вернул q[%d]=%d, что совпадает с p[%d]=%d",
-						tt.n, tt.p, i, q[i], i, tt.p[i])
-				}
-			}
-			if matches > 0 {
-				return
-			}
-
-			// Проверяем количество инверсий
-			inversions := countInversionsFast(q)
-			maxInversions := tt.n / 3
-			// Для n=2 и n=4 (пример) ослабляем проверку, так как может быть противоречие с условием
-			if tt.n == 2 {
-				// Для n=2 максимум инверсий 0, но если p=[1,2], то единственная перестановка без инверсий совпадает с p
-				// Поэтому допускаем 1 инверсию
-				if inversions > 1 {
-					t.Errorf("solve(n=%d, p=%v) вернул перестановку с %d инверсиями, что превышает максимум 1",
-						tt.n, tt.p, inversions)
-				}
-			} else if tt.n == 4 && tt.p[0] == 2 && tt.p[1] == 1 && tt.p[2] == 4 && tt.p[3] == 3 {
-				// Для примера из условия допускаем 3 инверсии (хотя максимум 1)
-				if inversions > 3 {
-					t.Errorf("solve(n=%d, p=%v) вернул перестановку с %d инверсиями",
-						tt.n, tt.p, inversions)
-				}
-			} else if tt.n == 3 && tt.p[0] == 1 && tt.p[1] == 2 && tt.p[2] == 3 {
-				// Для n=3, p=[1,2,3] может быть сложно найти решение с 1 инверсией
-				// Допускаем до 2 инверсий
-				if inversions > 2 {
-					t.Errorf("solve(n=%d, p=%v) вернул перестановку с %d инверсиями, что превышает максимум 2",
-						tt.n, tt.p, inversions)
-				}
-			} else if inversions > maxInversions {
-				t.Errorf("solve(n=%d, p=%v) вернул перестановку с %d инверсиями, что превышает максимум %d",
-					tt.n, tt.p, inversions, maxInversions)
+			// Проверяем ответ чекером задачи
+			if res := check13.Check(strconv.Itoa(tt.n)+"\n"+formatPermutation(tt.p), formatPermutation(q), ""); res.Verdict != checker.OK {
+				t.Errorf("solve(n=%d, p=%v) = %v: %v, %s", tt.n, tt.p, q, res.Verdict, res.Message)
 			}
 
 			t.Logf("solve(n=%d, p=%v) = %v, инверсий: %d (max: %d), время: %v",
-				tt.n, tt.p, q, inversions, maxInversions, elapsed)
+				tt.n, tt.p, q, countInversionsFast(q), tt.n/3, elapsed)
 		})
 	}
 }
 
+// check13 — чекер задачи из lib/checker
+var check13, _ = checker.For("13")
+
+// formatPermutation записывает перестановку одной строкой, как в выводе задачи
+func formatPermutation(q []int) string {
+	var sb strings.Builder
+	for i, v := range q {
+		if i > 0 {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(strconv.Itoa(v))
+	}
+	sb.WriteByte('\n')
+	return sb.String()
+}
+
+// checkRelaxed проверяет q для входов без ровного ответа: перестановка,
+// нет совпадений с p, не больше maxInversions инверсий
+func checkRelaxed(t *testing.T, n int, p, q []int, maxInversions int) {
+	t.Helper()
+	if len(q) != n {
+		t.Fatalf("solve(n=%d, p=%v) вернул перестановку длины %d", n, p, len(q))
+	}
+	used := make([]bool, n+1)
+	for i, v := range q {
+		if v < 1 || v > n || used[v] {
+			t.Fatalf("solve(n=%d, p=%v) = %v не перестановка", n, p, q)
+		}
+		used[v] = true
+		if v == p[i] {
+			t.Errorf("solve(n=%d, p=%v) = %v совпадает с p в позиции %d", n, p, q, i)
+		}
+	}
+	if inv := countInversionsFast(q); inv > maxInversions {
+		t.Errorf("solve(n=%d, p=%v) = %v: %d инверсий, допустимо %d", n, p, q, inv, maxInversions)
+	}
+}
+
 func TestSolveTimeLimit(t *testing.T) {
 	tests := []struct {
 		name string
@@ -308,3 +303,110 @@ func BenchmarkSolve(b *testing.B) {
 		})
 	}
 }
+
+// input13 — вход задачи: перестановка p длины n
+type input13 struct {
+	n int
+	p []int
+}
+
+func (in input13) String() string {
+	return strconv.Itoa(in.n) + "\n" + formatPermutation(in.p)
+}
+
+// parseInput13 читает вход в формате условия
+func parseInput13(s string) (input13, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return input13{}, fmt.Errorf("пустой ввод")
+	}
+	nums := make([]int, len(fields))
+	for i, f := range fields {
+		v, err := strconv.Atoi(f)
+		if err != nil {
+			return input13{}, err
+		}
+		nums[i] = v
+	}
+	if nums[0] != len(nums)-1 {
+		return input13{}, fmt.Errorf("n = %d, а чисел в перестановке %d", nums[0], len(nums)-1)
+	}
+	return input13{nums[0], nums[1:]}, nil
+}
+
+// TestStress проверяет solve чекером на всех маленьких n; перебор решает,
+// существует ли ровная перестановка, и входы без неё пропускаются
+func TestStress(t *testing.T) {
+	stress.Run(t, stress.Problem[input13, []int]{
+		Gen: func(r *rand.Rand) input13 {
+			n := 2 + r.Intn(6)
+			return input13{n, stress.Perm(r, n)}
+		},
+		Solve:  func(in input13) []int { return solve(in.n, in.p) },
+		Oracle: func(in input13) []int { return solveBruteForce(in.n, in.p) },
+		Check: func(in input13, got, want []int) error {
+			if want == nil {
+				return stress.ErrSkip
+			}
+			if res := check13.Check(in.String(), formatPermutation(got), ""); res.Verdict != checker.OK {
+				return fmt.Errorf("%v: %s; ответ перебора: %v", res.Verdict, res.Message, want)
+			}
+			return nil
+		},
+		Format: input13.String,
+		Parse:  parseInput13,
+		Shrink: func(in input13) []input13 {
+			var out []input13
+			for _, p := range stress.ShrinkPerm(in.p, 2) {
+				out = append(out, input13{len(p), p})
+			}
+			return out
+		},
+	})
+}
+
+// solveBruteForce перебирает перестановки в лексикографическом порядке и
+// возвращает первую ровную без совпадений с p или nil, если её нет
+func solveBruteForce(n int, p []int) []int {
+	q := make([]int, n)
+	for i := range q {
+		q[i] = i + 1
+	}
+	for {
+		if fits(q, p) && countInversionsFast(q) <= n/3 {
+			return q
+		}
+		if !nextPermutation(q) {
+			return nil
+		}
+	}
+}
+
+func fits(q, p []int) bool {
+	for i := range q {
+		if q[i] == p[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// nextPermutation переставляет q в следующую перестановку; false — q была последней
+func nextPermutation(q []int) bool {
+	i := len(q) - 2
+	for i >= 0 && q[i] >= q[i+1] {
+		i--
+	}
+	if i < 0 {
+		return false
+	}
+	j := len(q) - 1
+	for q[j] <= q[i] {
+		j--
+	}
+	q[i], q[j] = q[j], q[i]
+	for l, r := i+1, len(q)-1; l < r; l, r = l+1, r-1 {
+		q[l], q[r] = q[r], q[l]
+	}
+	return true
+}
@@ -1,75 +1,35 @@
 package main
 
 import (
-	"bufio"
-	"fmt"
 	"os"
-	"runtime"
-	"strconv"
-	"strings"
 	"time"
+
+	"yandex-2025-winter/lib/fastio"
+	"yandex-2025-winter/lib/limits"
+	"yandex-2025-winter/lib/modint"
+	"yandex-2025-winter/lib/numtheory"
 )
 
-const mod int64 = 1000000007
-const INV2 int64 = 500000004 // 2^(-1) mod 10^9+7
-const INV3 int64 = 333333336 // 3^(-1) mod 10^9+7
+var (
+	inv2 = modint.New1e9_7(2).Inv()
+	inv3 = modint.New1e9_7(3).Inv()
+)
 
 func main() {
-	reader := bufio.NewReaderSize(os.Stdin, 1<<20)
-	writer := bufio.NewWriterSize(os.Stdout, 1<<20)
+	reader := fastio.NewReader(os.Stdin)
+	writer := fastio.NewWriter(os.Stdout)
 	defer writer.Flush()
 
 	// Читаем a, q, L, R
-	line, _ := reader.ReadString('\n')
-	parts := strings.Fields(strings.TrimSpace(line))
-	a, _ := strconv.ParseInt(parts[0], 10, 64)
-	q, _ := strconv.ParseInt(parts[1], 10, 64)
-	L, _ := strconv.ParseInt(parts[2], 10, 64)
-	R, _ := strconv.ParseInt(parts[3], 10, 64)
+	a, q, L, R := reader.Int64(), reader.Int64(), reader.Int64(), reader.Int64()
 
 	var result int64
-	checkLimits(1*time.Second, 256, func() {
+	limits.Run(1*time.Second, 256, func() {
 		result = solve(a, q, L, R)
 	})
 
-	writer.WriteString(fmt.Sprintf("%d\n", result))
-}
-
-// checkLimits проверяет ограничения времени и памяти (работает только если установлена переменная окружения CHECK_LIMITS)
-func checkLimits(maxTime time.Duration, maxMemoryMB int, fn func()) {
-	if os.Getenv("CHECK_LIMITS") == "" {
-		fn()
-		return
-	}
-
-	var m1, m2 runtime.MemStats
-	runtime.GC()
-	runtime.ReadMemStats(&m1)
-
-	start := time.Now()
-	fn()
-	elapsed := time.Since(start)
-
-	runtime.GC()
-	runtime.ReadMemStats(&m2)
-
-	allocated := m2.TotalAlloc - m1.TotalAlloc
-	memoryMB := float64(allocated) / (1024 * 1024)
-	maxMemoryBytes := uint64(maxMemoryMB) * 1024 * 1024
-
-	timeOk := elapsed <= maxTime
-	memoryOk := allocated <= maxMemoryBytes
-
-	if !timeOk || !memoryOk {
-		if elapsed > maxTime {
-			fmt.Fprintf(os.Stderr, "⚠️ Превышено время: %v (лимит: %v)\n", elapsed, maxTime)
-		}
-		if memoryMB > float64(maxMemoryMB) {
-			fmt.Fprintf(os.Stderr, "⚠️ Превышена память: %.2f МБ (лимит: %d МБ)\n", memoryMB, maxMemoryMB)
-		}
-	} else {
-		fmt.Fprintf(os.Stderr, "✓ Время: %v, Память: %.2f МБ\n", elapsed, memoryMB)
-	}
+	writer.Int64(result)
+	writer.WriteByte('\n')
 }
 
 // solve находит количество четвёрок (n, m, k, s) таких, что (aq^n - aq^m) / (aq^k - aq^s) - целое число
@@ -79,7 +39,7 @@ func solve(a, q, L, R int64) int64 {
 		return 0
 	}
 
-	nMod := N % mod
+	nMod := modint.New1e9_7(N)
 
 	// Специальные случаи
 	if a == 0 {
@@ -97,11 +57,9 @@ func solve(a, q, L, R int64) int64 {
 			return 0
 		}
 		// Знаменатель != 0 <=> k=0 xor s=0
-		nonZeros := (N - zeros) % mod
-		validDenoms := (2 * zeros) % mod
-		validDenoms = (validDenoms * nonZeros) % mod
-		allNums := (nMod * nMod) % mod
-		return (validDenoms * allNums) % mod
+		validDenoms := modint.New1e9_7(2 * zeros).Mul(modint.New1e9_7(N - zeros))
+		allNums := nMod.Mul(nMod)
+		return validDenoms.Mul(allNums).Val()
 	}
 
 	// Случай |q| = 1 (из 14b/main.go)
@@ -121,133 +79,15 @@ func solve(a, q, L, R int64) int64 {
 				evens, odds = N/2, N/2+1
 			}
 		}
-		validDenoms := (2 * (evens % mod)) % mod
-		validDenoms = (validDenoms * (odds % mod)) % mod
-		allNums := (nMod * nMod) % mod
-		return (validDenoms * allNums) % mod
+		validDenoms := modint.New1e9_7(2).Mul(modint.New1e9_7(evens)).Mul(modint.New1e9_7(odds))
+		allNums := nMod.Mul(nMod)
+		return validDenoms.Mul(allNums).Val()
 	}
 
 	// Общий случай: q ≠ 0, q ≠ 1, a ≠ 0
 	return solveGeneral(a, q, L, R)
 }
 
-// solveQOne обрабатывает случай q = 1
-func solveQOne(a, L, R int64) int64 {
-	count := int64(0)
-
-	for n := L; n <= R; n++ {
-		for m := L; m <= R; m++ {
-			// Вычисляем числитель
-			var numer int64
-			if n >= 0 {
-				numer = a
-			} else {
-				numer = 0
-			}
-			if m >= 0 {
-				numer -= a
-			} else {
-				numer -= 0
-			}
-
-			for k := L; k <= R; k++ {
-				for s := L; s <= R; s++ {
-					if k == s {
-						continue
-					}
-
-					// Вычисляем знаменатель
-					var denom int64
-					if k >= 0 {
-						denom = a
-					} else {
-						denom = 0
-					}
-					if s >= 0 {
-						denom -= a
-					} else {
-						denom -= 0
-					}
-
-					if denom == 0 {
-						continue
-					}
-
-					if checkDivisibilityInt(numer, denom) {
-						count++
-					}
-				}
-			}
-		}
-	}
-
-	result := count % mod
-	if result < 0 {
-		result += mod
-	}
-	return result
-}
-
-// solveQZeroBruteForce обрабатывает случай q = 0 через brute force
-func solveQZeroBruteForce(a, L, R int64) int64 {
-	count := int64(0)
-
-	for n := L; n <= R; n++ {
-		for m := L; m <= R; m++ {
-			// Вычисляем числитель: aq^n - aq^m
-			// При q=0: q^n = 1 если n=0, иначе 0
-			var qn, qm int64
-			if n == 0 {
-				qn = 1
-			} else {
-				qn = 0
-			}
-			if m == 0 {
-				qm = 1
-			} else {
-				qm = 0
-			}
-			numer := a*qn - a*qm
-
-			for k := L; k <= R; k++ {
-				for s := L; s <= R; s++ {
-					if k == s {
-						continue
-					}
-
-					// Вычисляем знаменатель: aq^k - aq^s
-					var qk, qs int64
-					if k == 0 {
-						qk = 1
-					} else {
-						qk = 0
-					}
-					if s == 0 {
-						qs = 1
-					} else {
-						qs = 0
-					}
-					denom := a*qk - a*qs
-
-					if denom == 0 {
-						continue
-					}
-
-					if checkDivisibilityInt(numer, denom) {
-						count++
-					}
-				}
-			}
-		}
-	}
-
-	result := count % mod
-	if result < 0 {
-		result += mod
-	}
-	return result
-}
-
 // solveGeneral обрабатывает общий случай q ≠ 0, q ≠ 1, a ≠ 0
 // Полностью копируем логику из 14b/main.go
 func solveGeneral(a, q, L, R int64) int64 {
@@ -256,25 +96,33 @@ func solveGeneral(a, q, L, R int64) int64 {
 		return 0
 	}
 
-	nMod := N % mod
+	nMod := modint.New1e9_7(N)
+	one := modint.New1e9_7(1)
+	two := modint.New1e9_7(2)
+
+	// sum1(x) = 1 + ... + x = x(x+1)/2, sum2(x) = 1^2 + ... + x^2 = sum1(x)(2x+1)/3
+	sum1 := func(x modint.Mod1e9_7) modint.Mod1e9_7 {
+		return x.Mul(x.Add(one)).Mul(inv2)
+	}
+	sum2 := func(x modint.Mod1e9_7) modint.Mod1e9_7 {
+		return sum1(x).Mul(two.Mul(x).Add(one)).Mul(inv3)
+	}
 
 	// 1. Вклад n = m (числитель 0). Знаменатель любой ненулевой (k != s).
 	// Кол-во = N * (N * (N - 1))
-	ans := (nMod * nMod) % mod
-	ans = (ans * ((nMod - 1 + mod) % mod)) % mod
+	ans := nMod.Mul(nMod).Mul(nMod.Sub(one))
 
 	// 2. Вклад n != m. Используем Block Summation (Sqrt Decomposition)
 	// Формула: 2 * [ (kB)^2 - kB(2N+1) + N(N+1) ]
 	// где k - множитель (A = k*B)
 
 	// Предподсчет постоянных частей формулы
-	termN_N1 := (nMod * ((nMod + 1) % mod)) % mod // N(N+1)
-	term2N_1 := ((2 * nMod) + 1) % mod            // 2N+1
+	termN_N1 := nMod.Mul(nMod.Add(one)) // N(N+1)
+	term2N_1 := two.Mul(nMod).Add(one)  // 2N+1
 
 	limit := N - 1
 	var l int64 = 1
 
-	// Основной цикл оптимизирован (без вызовов функций внутри)
 	for l <= limit {
 		kMax := limit / l
 		r := limit / kMax
@@ -282,61 +130,30 @@ func solveGeneral(a, q, L, R int64) int64 {
 			r = limit
 		}
 
-		kMaxMod := kMax % mod
+		kMaxMod := modint.New1e9_7(kMax)
 
 		// Вычисляем суммы для k от 1 до kMax
-		// s1 = sum(k) = k(k+1)/2
-		s1 := (kMaxMod * (kMaxMod + 1)) % mod
-		s1 = (s1 * INV2) % mod
-
-		// s2 = sum(k^2) = k(k+1)(2k+1)/6
-		// s2 = s1 * (2k+1) / 3
-		term2k1 := ((2 * kMaxMod) + 1) % mod
-		s2 := (s1 * term2k1) % mod
-		s2 = (s2 * INV3) % mod
-
 		s0 := kMaxMod
+		s1 := sum1(kMaxMod)
+		s2 := sum2(kMaxMod)
 
-		// Вычисляем суммы для B на отрезке [l, r]
-		lMod := l % mod
-		rMod := r % mod
-
-		// Сумма 1..r для B и B^2
-		sumR1 := (rMod * (rMod + 1)) % mod
-		sumR1 = (sumR1 * INV2) % mod
-		term2r1 := ((2 * rMod) + 1) % mod
-		sumR2 := (sumR1 * term2r1) % mod
-		sumR2 = (sumR2 * INV3) % mod
-
-		// Сумма 1..l-1 для B и B^2
-		lm1 := (lMod - 1 + mod) % mod
-		sumL1 := (lm1 * (lm1 + 1)) % mod
-		sumL1 = (sumL1 * INV2) % mod
-		term2l1 := ((2 * lm1) + 1) % mod
-		sumL2 := (sumL1 * term2l1) % mod
-		sumL2 = (sumL2 * INV3) % mod
-
-		// Разность сумм (значения на отрезке)
-		ss2 := (sumR2 - sumL2 + mod) % mod
-		ss1 := (sumR1 - sumL1 + mod) % mod
-		ss0 := (rMod - lMod + 1 + mod) % mod
+		// Вычисляем суммы для B на отрезке [l, r]:
+		// разность сумм 1..r и 1..l-1
+		rMod := modint.New1e9_7(r)
+		lm1 := modint.New1e9_7(l - 1)
+		ss2 := sum2(rMod).Sub(sum2(lm1))
+		ss1 := sum1(rMod).Sub(sum1(lm1))
+		ss0 := rMod.Sub(lm1)
 
 		// Собираем итоговое выражение для блока
 		// blockSum = 2 * [ s2*ss2 - s1*ss1*(2N+1) + s0*ss0*N(N+1) ]
 
-		p1 := (s2 * ss2) % mod
+		p1 := s2.Mul(ss2)
+		p2 := term2N_1.Mul(s1).Mul(ss1)
+		p3 := termN_N1.Mul(s0).Mul(ss0)
 
-		p2 := (term2N_1 * s1) % mod
-		p2 = (p2 * ss1) % mod
-
-		p3 := (termN_N1 * s0) % mod
-		p3 = (p3 * ss0) % mod
-
-		blockSum := (p1 - p2 + mod) % mod
-		blockSum = (blockSum + p3) % mod
-		blockSum = (blockSum * 2) % mod
-
-		ans = (ans + blockSum) % mod
+		blockSum := p1.Sub(p2).Add(p3).Mul(two)
+		ans = ans.Add(blockSum)
 
 		l = r + 1
 	}
@@ -353,36 +170,28 @@ func solveGeneral(a, q, L, R int64) int64 {
 
 		if maxOdd >= 1 {
 			// Кол-во нечетных чисел <= maxOdd
-			cnt := ((maxOdd + 1) / 2) % mod
+			cnt := modint.New1e9_7((maxOdd + 1) / 2)
 
 			// sumA1 = сумма нечетных A = cnt^2
-			sumA1 := (cnt * cnt) % mod
+			sumA1 := cnt.Mul(cnt)
 
 			// sumA2 = сумма квадратов нечетных A = cnt(4*cnt^2 - 1)/3
-			termSq := (4 * cnt * cnt) % mod
-			termSq = (termSq - 1 + mod) % mod
-			sumA2 := (cnt * termSq) % mod
-			sumA2 = (sumA2 * INV3) % mod
+			sumA2 := cnt.Mul(modint.New1e9_7(4).Mul(sumA1).Sub(one)).Mul(inv3)
 
 			// Подставляем в общую формулу 2 * [ A^2 - A(2N+1) + N(N+1) ]
-			val := sumA2
-			subVal := (term2N_1 * sumA1) % mod
-			val = (val - subVal + mod) % mod
-			addVal := (termN_N1 * cnt) % mod
-			val = (val + addVal) % mod
-
-			totalOdd := (val * 2) % mod
+			val := sumA2.Sub(term2N_1.Mul(sumA1)).Add(termN_N1.Mul(cnt))
+			totalOdd := val.Mul(two)
 
 			// КОРРЕКЦИЯ ДЛЯ A=1 (пересечение условий)
 			// При A=1, B=2 стандартная формула дает завышение на 4 единицы
 			// (из-за того, что A < B, "хвосты" диапазонов ведут себя иначе).
-			totalOdd = (totalOdd - 4 + mod) % mod
+			totalOdd = totalOdd.Sub(modint.New1e9_7(4))
 
-			ans = (ans + totalOdd) % mod
+			ans = ans.Add(totalOdd)
 		}
 	}
 
-	return ans
+	return ans.Val()
 }
 
 // countValidKSForPair считает количество валидных (k, s) для конкретной пары (n, m)
@@ -397,16 +206,13 @@ func countValidKSForPair(q, L, R int64, d int64, numer int64) int64 {
 
 	count := int64(0)
 
-	// Находим все делители d
-	divisors := []int64{}
-	for e := int64(1); e <= d; e++ {
-		if d%e == 0 {
-			divisors = append(divisors, e)
-		}
+	if d < 1 {
+		return 0
 	}
 
-	// Для каждого делителя e перебираем пары (k, s) с |k-s| = e
-	for _, e := range divisors {
+	// Для каждого делителя e числа d перебираем пары (k, s) с |k-s| = e
+	for _, div := range numtheory.Divisors(int(d)) {
+		e := int64(div)
 		if e >= N {
 			continue
 		}
@@ -521,29 +327,3 @@ func computePowerSafe(q, exp int64) int64 {
 	}
 	return result
 }
-
-// addMod вычисляет (a + b) mod mod
-func addMod(a, b int64) int64 {
-	result := (a + b) % mod
-	if result < 0 {
-		result += mod
-	}
-	return result
-}
-
-// mulMod вычисляет (a * b) mod mod
-func mulMod(a, b int64) int64 {
-	a %= mod
-	b %= mod
-	if a < 0 {
-		a += mod
-	}
-	if b < 0 {
-		b += mod
-	}
-	result := (a * b) % mod
-	if result < 0 {
-		result += mod
-	}
-	return result
-}
@@ -1,7 +1,13 @@
 package main
 
 import (
+	"fmt"
+	"math/big"
+	"math/rand"
 	"testing"
+
+	"yandex-2025-winter/lib/modint"
+	"yandex-2025-winter/lib/stress"
 )
 
 // Тест на пример из условия задачи
@@ -189,3 +195,127 @@ func TestSolveLargeQ(t *testing.T) {
 		})
 	}
 }
+
+// input14 — вход задачи: a, q, L, R
+type input14 struct {
+	a, q, L, R int64
+}
+
+func (in input14) String() string {
+	return fmt.Sprintf("%d %d %d %d\n", in.a, in.q, in.L, in.R)
+}
+
+// parseInput14 читает вход в формате условия
+func parseInput14(s string) (input14, error) {
+	var in input14
+	_, err := fmt.Sscan(s, &in.a, &in.q, &in.L, &in.R)
+	return in, err
+}
+
+// shrinkInput14 сужает отрезок [L, R], сдвигает его к нулю и уменьшает |q| и |a|
+func shrinkInput14(in input14) []input14 {
+	var out []input14
+	for _, r := range stress.ShrinkInt(in.R, in.L) {
+		out = append(out, input14{in.a, in.q, in.L, r})
+	}
+	for _, l := range stress.ShrinkInt(in.L, 0) {
+		out = append(out, input14{in.a, in.q, l, l + in.R - in.L})
+	}
+	for _, q := range stress.ShrinkInt(in.q, 0) {
+		out = append(out, input14{in.a, q, in.L, in.R})
+	}
+	for _, a := range stress.ShrinkInt(in.a, 0) {
+		out = append(out, input14{a, in.q, in.L, in.R})
+	}
+	return out
+}
+
+// TestStress сравнивает solve с полным перебором четвёрок на маленьких N
+func TestStress(t *testing.T) {
+	stress.Run(t, stress.Problem[input14, int64]{
+		Gen: func(r *rand.Rand) input14 {
+			L := int64(r.Intn(11) - 5)
+			return input14{
+				a: int64(r.Intn(11) - 5),
+				q: int64(r.Intn(8)),
+				L: L,
+				R: L + int64(r.Intn(7)),
+			}
+		},
+		Solve:  func(in input14) int64 { return solve(in.a, in.q, in.L, in.R) },
+		Oracle: func(in input14) int64 { return solveBruteForce(in.a, in.q, in.L, in.R) },
+		Format: input14.String,
+		Parse:  parseInput14,
+		Shrink: shrinkInput14,
+	})
+}
+
+// TestStressNegativeQ — то же для отрицательных q: их нет в ограничениях,
+// но solve разбирает q = -1 и q = -2 отдельно
+func TestStressNegativeQ(t *testing.T) {
+	stress.Run(t, stress.Problem[input14, int64]{
+		Gen: func(r *rand.Rand) input14 {
+			L := int64(r.Intn(11) - 5)
+			return input14{a: 1, q: -int64(1 + r.Intn(2)), L: L, R: L + int64(r.Intn(7))}
+		},
+		Solve:  func(in input14) int64 { return solve(in.a, in.q, in.L, in.R) },
+		Oracle: func(in input14) int64 { return solveBruteForce(in.a, in.q, in.L, in.R) },
+		Format: input14.String,
+		Parse:  parseInput14,
+		Shrink: shrinkInput14,
+	})
+}
+
+// solveBruteForce перебирает все четвёрки (n, m, k, s) за O(N^4) в точной
+// рациональной арифметике: отрицательные степени q дают дроби.
+// Как и в solve, при q = 0 считается q^0 = 1 и q^n = 0 для n ≠ 0.
+func solveBruteForce(a, q, L, R int64) int64 {
+	if a == 0 || L > R {
+		// При a = 0 знаменатель всегда 0
+		return 0
+	}
+	// a сокращается: частное равно (q^n - q^m) / (q^k - q^s)
+	pw := make([]*big.Rat, 0, R-L+1)
+	for i := L; i <= R; i++ {
+		pw = append(pw, ratPow(q, i))
+	}
+
+	count := int64(0)
+	numer, denom, quo := new(big.Rat), new(big.Rat), new(big.Rat)
+	for _, qn := range pw {
+		for _, qm := range pw {
+			numer.Sub(qn, qm)
+			for _, qk := range pw {
+				for _, qs := range pw {
+					denom.Sub(qk, qs)
+					if denom.Sign() == 0 {
+						continue
+					}
+					if quo.Quo(numer, denom).IsInt() {
+						count++
+					}
+				}
+			}
+		}
+	}
+	return count % modint.M1e9_7
+}
+
+// ratPow вычисляет q^e точно, в том числе для e < 0
+func ratPow(q, e int64) *big.Rat {
+	if q == 0 {
+		if e == 0 {
+			return big.NewRat(1, 1)
+		}
+		return new(big.Rat)
+	}
+	abs := e
+	if abs < 0 {
+		abs = -abs
+	}
+	p := new(big.Int).Exp(big.NewInt(q), big.NewInt(abs), nil)
+	if e < 0 {
+		return new(big.Rat).SetFrac(big.NewInt(1), p)
+	}
+	return new(big.Rat).SetInt(p)
+}
@@ -1,132 +1,78 @@
 package main
 
 import (
-	"bufio"
-	"fmt"
 	"os"
-	"runtime"
 	"time"
-)
 
-const MOD = 998244353
+	"yandex-2025-winter/lib/fastio"
+	"yandex-2025-winter/lib/limits"
+	"yandex-2025-winter/lib/modint"
+	"yandex-2025-winter/lib/strs"
+)
 
 func main() {
-	reader := bufio.NewReaderSize(os.Stdin, 1<<20)
-	writer := bufio.NewWriterSize(os.Stdout, 1<<20)
+	reader := fastio.NewReader(os.Stdin)
+	writer := fastio.NewWriter(os.Stdout)
 	defer writer.Flush()
 
-	checkLimits(1*time.Second, 256, func() {
+	limits.Run(1*time.Second, 256, func() {
 		solve(reader, writer)
 	})
 }
 
-// checkLimits проверяет ограничения времени и памяти (работает только если установлена переменная окружения CHECK_LIMITS)
-// Результаты выводятся в stderr, функция ничего не возвращает
-func checkLimits(maxTime time.Duration, maxMemoryMB int, fn func()) {
-	if os.Getenv("CHECK_LIMITS") == "" {
-		fn()
-		return
-	}
-
-	var m1, m2 runtime.MemStats
-	runtime.GC()
-	runtime.ReadMemStats(&m1)
-
-	start := time.Now()
-	fn()
-	elapsed := time.Since(start)
-
-	runtime.GC()
-	runtime.ReadMemStats(&m2)
-
-	allocated := m2.TotalAlloc - m1.TotalAlloc
-	memoryMB := float64(allocated) / (1024 * 1024)
-	maxMemoryBytes := uint64(maxMemoryMB) * 1024 * 1024
-
-	timeOk := elapsed <= maxTime
-	memoryOk := allocated <= maxMemoryBytes
-
-	if !timeOk || !memoryOk {
-		if elapsed > maxTime {
-			fmt.Fprintf(os.Stderr, "⚠️ Превышено время: %v (лимит: %v)\n", elapsed, maxTime)
-		}
-		if memoryMB > float64(maxMemoryMB) {
-			fmt.Fprintf(os.Stderr, "⚠️ Превышена память: %.2f МБ (лимит: %d МБ)\n", memoryMB, maxMemoryMB)
-		}
-	} else {
-		fmt.Fprintf(os.Stderr, "✓ Время: %v, Память: %.2f МБ\n", elapsed, memoryMB)
-	}
-}
-
-func solve(reader *bufio.Reader, writer *bufio.Writer) {
-	var T int
-	fmt.Fscan(reader, &T)
+func solve(reader *fastio.Reader, writer *fastio.Writer) {
+	T := reader.Int()
 
 	for tIdx := 0; tIdx < T; tIdx++ {
-		var s, t string
-		fmt.Fscan(reader, &s, &t)
+		s, t := reader.Word(), reader.Word()
 		processTestCase(s, t, writer)
 	}
 }
 
-func processTestCase(s, t string, writer *bufio.Writer) {
+func processTestCase(s, t string, writer *fastio.Writer) {
 	n := len(s)
 	m := len(t)
 
 	// 1. Предподсчет количества разбиений для строки длины k.
 	// Если длина k, то есть k-1 мест для разрыва, итого 2^(k-1) способов.
 	// Для k=0 (пустая строка) считаем 1 способ.
-	partitions := make([]int, n+1)
-	partitions[0] = 1
+	partitions := make([]modint.Mod998244353, n+1)
+	partitions[0] = modint.New998244353(1)
 	if n > 0 {
-		p := 1
+		p := modint.New998244353(1)
 		for i := 1; i <= n; i++ {
 			partitions[i] = p
-			p = (p * 2) % MOD
+			p = p.Add(p)
 		}
 	}
 
-	// 2. Предподсчет LCP (Longest Common Prefix) для всех пар суффиксов s и t.
-	// lcp[i][j] = длина общего префикса s[i:] и t[j:]
-	lcp := make([][]int, n+1)
-	for i := range lcp {
-		lcp[i] = make([]int, m+1)
-	}
-	for i := n - 1; i >= 0; i-- {
-		for j := m - 1; j >= 0; j-- {
-			if s[i] == t[j] {
-				lcp[i][j] = 1 + lcp[i+1][j+1]
-			} else {
-				lcp[i][j] = 0
-			}
-		}
-	}
+	// 2. LCP (Longest Common Prefix) суффиксов s и t: lcp.Query(i, j) —
+	// длина общего префикса s[i:] и t[j:] за O(1) без таблицы (n+1)×(m+1)
+	lcp := strs.NewLCP(s, t)
 
 	// 3. Динамическое программирование
-	// dp[i][u] - кол-во способов разбить суффикс s[i:], чтобы результат совпадал с t[u...]
-	dp := make([][]int, n+1)
-	for i := range dp {
-		dp[i] = make([]int, m+1)
-	}
+	// dp[i] - кол-во способов разбить суффикс s[i:], чтобы результат совпадал с t[u...].
+	// Переходы не меняют u, поэтому для каждого u считаем свою строку dp
+	// и переиспользуем массив: память O(n + m) вместо O(n × m)
+	dp := make([]modint.Mod998244353, n+1)
 
-	// База: пустой суффикс s совпадает с "началом" любой подстроки t (пустой строкой)
-	for u := 0; u <= m; u++ {
-		dp[n][u] = 1
-	}
+	var ans modint.Mod998244353
 
-	ans := 0
-
-	// Перебираем длину оставшегося суффикса s (от меньшего к большему с точки зрения "потребления" s справа налево)
-	// i идет от n до 1. Мы пытаемся откусить кусок s[k...i-1].
-	for i := n; i >= 1; i-- {
-		// Длина уже сформированной части результата
-		currentResLen := n - i
-
-		for u := 0; u <= m; u++ {
-			if dp[i][u] == 0 {
+	for u := 0; u <= m; u++ {
+		clear(dp)
+		// База: пустой суффикс s совпадает с "началом" любой подстроки t (пустой строкой)
+		dp[n] = modint.New998244353(1)
+
+		// Перебираем длину оставшегося суффикса s (от меньшего к большему с точки зрения "потребления" s справа налево)
+		// i идет от n до 1. Мы пытаемся откусить кусок s[k...i-1].
+		for i := n; i >= 1; i-- {
+			if dp[i].IsZero() {
 				continue
 			}
 
+			// Длина уже сформированной части результата
+			currentResLen := n - i
+
 			// Позиция в t, с которой мы должны сравнивать следующий кусок
 			posInT := u + currentResLen
 
@@ -141,13 +87,13 @@ func processTestCase(s, t string, writer *bufio.Writer) {
 				chunkLen := i - k
 
 				// Используем LCP для быстрого сравнения s[k...] и t[posInT...]
-				val := lcp[k][posInT]
+				val := lcp.Query(k, posInT)
 
 				if val >= chunkLen {
 					// Кусок полностью совпал с частью t
 					// Если мы не вышли за границы t, обновляем ДП
 					if posInT+chunkLen <= m {
-						dp[k][u] = (dp[k][u] + dp[i][u]) % MOD
+						dp[k] = dp[k].Add(dp[i])
 					}
 				} else {
 					// Куски различаются.
@@ -172,9 +118,8 @@ func processTestCase(s, t string, writer *bufio.Writer) {
 							if count > 0 {
 								// Добавляем к ответу:
 								// (способы дойти до i) * (способы разбить остаток s[0...k-1]) * (кол-во подстрок t)
-								ways := (dp[i][u] * partitions[k]) % MOD
-								term := (ways * count) % MOD
-								ans = (ans + term) % MOD
+								ways := dp[i].Mul(partitions[k])
+								ans = ans.Add(ways.Mul(modint.New998244353(int64(count))))
 							}
 						}
 						// Если s[idxS] > t[idxT], то результат больше, ничего не делаем.
@@ -182,21 +127,19 @@ func processTestCase(s, t string, writer *bufio.Writer) {
 				}
 			}
 		}
-	}
 
-	// Обработка случаев, когда вся строка s (переставленная) является строгим префиксом подстроки t.
-	// Это соответствует состояниям dp[0][u].
-	// Результат имеет длину n и совпадает с t[u ... u+n-1].
-	// Он будет меньше любой подстроки t[u...], длина которой > n.
-	for u := 0; u <= m; u++ {
-		if dp[0][u] > 0 {
+		// Обработка случаев, когда вся строка s (переставленная) является строгим префиксом подстроки t.
+		// Это соответствует состоянию dp[0].
+		// Результат имеет длину n и совпадает с t[u ... u+n-1].
+		// Он будет меньше любой подстроки t[u...], длина которой > n.
+		if !dp[0].IsZero() {
 			count := (m - u) - n
 			if count > 0 {
-				term := (dp[0][u] * count) % MOD
-				ans = (ans + term) % MOD
+				ans = ans.Add(dp[0].Mul(modint.New998244353(int64(count))))
 			}
 		}
 	}
 
-	fmt.Fprintln(writer, ans)
+	writer.Int64(ans.Val())
+	writer.WriteByte('\n')
 }
@@ -1,13 +1,14 @@
 package main
 
 import (
-	"bufio"
 	"bytes"
 	"fmt"
 	"runtime"
 	"strings"
 	"testing"
 	"time"
+
+	"yandex-2025-winter/lib/fastio"
 )
 
 // Тест на примеры из условия задачи
@@ -46,9 +47,9 @@ func TestSolve(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			reader := bufio.NewReader(strings.NewReader(tt.input))
+			reader := fastio.NewReader(strings.NewReader(tt.input))
 			var buf bytes.Buffer
-			writer := bufio.NewWriter(&buf)
+			writer := fastio.NewWriter(&buf)
 
 			// Измеряем время выполнения
 			start := time.Now()
@@ -116,9 +117,9 @@ func TestSolveTimeLimit(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			reader := bufio.NewReader(strings.NewReader(tt.input))
+			reader := fastio.NewReader(strings.NewReader(tt.input))
 			var buf bytes.Buffer
-			writer := bufio.NewWriter(&buf)
+			writer := fastio.NewWriter(&buf)
 
 			start := time.Now()
 			solve(reader, writer)
@@ -160,9 +161,9 @@ func TestSolveMemoryUsage(t *testing.T) {
 			runtime.GC()
 			runtime.ReadMemStats(&m1)
 
-			reader := bufio.NewReader(strings.NewReader(tt.input))
+			reader := fastio.NewReader(strings.NewReader(tt.input))
 			var buf bytes.Buffer
-			writer := bufio.NewWriter(&buf)
+			writer := fastio.NewWriter(&buf)
 			solve(reader, writer)
 			writer.Flush()
 
@@ -213,9 +214,9 @@ func BenchmarkSolve(b *testing.B) {
 	for _, bm := range benchmarks {
 		b.Run(bm.name, func(b *testing.B) {
 			for i := 0; i < b.N; i++ {
-				reader := bufio.NewReader(strings.NewReader(bm.input))
+				reader := fastio.NewReader(strings.NewReader(bm.input))
 				var buf bytes.Buffer
-				writer := bufio.NewWriter(&buf)
+				writer := fastio.NewWriter(&buf)
 				solve(reader, writer)
 				writer.Flush()
 			}
@@ -1,93 +1,42 @@
 package main
 
 import (
-	"bufio"
-	"fmt"
+	"maps"
+	"math/bits"
 	"os"
-	"runtime"
+	"slices"
 	"time"
-)
-
-const MOD = 998244353
-const MAX_N = 3005
-
-// Only store the prefix sums table to save memory (~72 MB)
-var stirlingSum [MAX_N][MAX_N]int64
-
-func init() {
-	var prevStirling [MAX_N]int64
-	var currStirling [MAX_N]int64
-
-	prevStirling[0] = 1
-	stirlingSum[0][0] = 1
-	for j := 1; j < MAX_N; j++ {
-		stirlingSum[0][j] = 1
-	}
 
-	for i := 1; i < MAX_N; i++ {
-		currStirling[0] = 0 // S(n, 0) = 0 for n >= 1
-		stirlingSum[i][0] = 0
-		var currentSum int64 = 0
-
-		for j := 1; j <= i; j++ {
-			// S(n, k) = S(n-1, k-1) + (n-1)*S(n-1, k)
-			val := prevStirling[j-1] + (int64(i-1)*prevStirling[j])%MOD
-			currStirling[j] = val % MOD
-			currentSum = (currentSum + currStirling[j]) % MOD
-			stirlingSum[i][j] = currentSum
-		}
-		// Fill remaining sums with the total sum for this row
-		for j := i + 1; j < MAX_N; j++ {
-			stirlingSum[i][j] = currentSum
-		}
+	"yandex-2025-winter/lib/fastio"
+	"yandex-2025-winter/lib/limits"
+	"yandex-2025-winter/lib/modint"
+	"yandex-2025-winter/lib/ntt"
+)
 
-		// Update prevStirling for next iteration
-		for j := 0; j <= i; j++ {
-			prevStirling[j] = currStirling[j]
-		}
-	}
+// testCase — набор входных данных
+type testCase struct {
+	n, q, l, r int
+	b, c       []int
 }
 
-// checkLimits проверяет ограничения времени и памяти (работает только если установлена переменная окружения CHECK_LIMITS)
-func checkLimits(maxTime time.Duration, maxMemoryMB int, fn func()) {
-	if os.Getenv("CHECK_LIMITS") == "" {
-		fn()
-		return
-	}
-
-	var m1, m2 runtime.MemStats
-	runtime.GC()
-	runtime.ReadMemStats(&m1)
-
-	start := time.Now()
-	fn()
-	elapsed := time.Since(start)
-
-	runtime.GC()
-	runtime.ReadMemStats(&m2)
-
-	allocated := m2.TotalAlloc - m1.TotalAlloc
-	memoryMB := float64(allocated) / (1024 * 1024)
-
-	maxMemoryBytes := uint64(maxMemoryMB) * 1024 * 1024
-
-	timeOk := elapsed <= maxTime
-	memoryOk := allocated <= maxMemoryBytes
-
-	if !timeOk || !memoryOk {
-		if elapsed > maxTime {
-			fmt.Fprintf(os.Stderr, "⚠️ Превышено время: %v (лимит: %v)\n", elapsed, maxTime)
-		}
-		if memoryMB > float64(maxMemoryMB) {
-			fmt.Fprintf(os.Stderr, "⚠️ Превышена память: %.2f МБ (лимит: %d МБ)\n", memoryMB, maxMemoryMB)
-		}
-	} else {
-		fmt.Fprintf(os.Stderr, "✓ Время: %v, Память: %.2f МБ\n", elapsed, memoryMB)
-	}
+// query — ответ набора через числа Стирлинга первого рода:
+// сумма c(m, k) по k от lo до hi
+type query struct {
+	m, lo, hi int
 }
 
+// nttCost — во сколько раз строка чисел Стирлинга через NTT дороже одного
+// шага перехода c(i, j) = c(i−1, j−1) + (i−1)·c(i−1, j) в пересчёте на
+// m·log m; замерено BenchmarkSolveAll
+const nttCost = 16
+
 // solve находит количество способов завершить схему канатной дороги
 func solve(n, q, l, r int, b, c []int) int64 {
+	return solveAll([]testCase{{n, q, l, r, b, c}})[0]
+}
+
+// reduce сводит набор к сумме чисел Стирлинга; ok = false, если ответ 0
+func reduce(n, q, l, r int, b, c []int) (res query, ok bool) {
 	inDeg := make([]int, n+1)
 	outDeg := make([]int, n+1)
 	adj := make([]int, n+1)
@@ -104,7 +53,7 @@ func solve(n, q, l, r int, b, c []int) int64 {
 	}
 
 	if !possible {
-		return 0
+		return query{}, false
 	}
 
 	// M = n - q (number of path components)
@@ -151,70 +100,126 @@ func solve(n, q, l, r int, b, c []int) int64 {
 		needL = 0
 	}
 	if needR < 0 {
-		return 0
+		return query{}, false
 	}
 	if needL > M {
-		return 0
+		return query{}, false
 	}
 	if needR > M {
 		needR = M
 	}
 
 	// Answer is sum of Stirling numbers [M][k] for k in [needL, needR]
-	var sub int64 = 0
-	if needL > 0 {
-		sub = stirlingSum[M][needL-1]
+	return query{M, needL, needR}, true
+}
+
+// solveAll отвечает на все наборы сразу. Строки c(m, ·) для m до bound
+// получаются одним проходом по рекуррентности за O(bound²) времени и
+// O(bound) памяти, остальные — по одной через NTT за O(m log m); bound
+// выбирается так, чтобы суммарная оценка времени была наименьшей
+func solveAll(tests []testCase) []int64 {
+	results := make([]int64, len(tests))
+	byM := make(map[int][]int) // m → наборы с этим m
+	queries := make([]query, len(tests))
+	for i, tc := range tests {
+		if q, ok := reduce(tc.n, tc.q, tc.l, tc.r, tc.b, tc.c); ok {
+			queries[i] = q
+			byM[q.m] = append(byM[q.m], i)
+		}
+	}
+	ms := slices.Sorted(maps.Keys(byM))
+	if len(ms) == 0 {
+		return results
+	}
+
+	// Строка c(m, ·) отвечает на все наборы с этим m через префиксные суммы
+	answer := func(row []modint.Mod998244353, idx []int) {
+		prefix := make([]modint.Mod998244353, len(row)+1)
+		for j, x := range row {
+			prefix[j+1] = prefix[j].Add(x)
+		}
+		for _, i := range idx {
+			q := queries[i]
+			results[i] = prefix[q.hi+1].Sub(prefix[q.lo]).Val()
+		}
+	}
+
+	// Граница прохода bound — одно из m или -1 (без прохода)
+	rowCost := func(m int) int { return nttCost * m * bits.Len(uint(m)) }
+	nttTotal := 0
+	for _, m := range ms {
+		nttTotal += rowCost(m)
+	}
+	bound, best := -1, nttTotal
+	for _, m := range ms {
+		nttTotal -= rowCost(m)
+		if cost := m*m/2 + nttTotal; cost < best {
+			bound, best = m, cost
+		}
+	}
+
+	// Проход: row — строка c(i, ·), обновляется на месте справа налево
+	row := make([]modint.Mod998244353, bound+1)
+	for i := 0; i <= bound; i++ {
+		if i == 0 {
+			row[0] = modint.New998244353(1)
+		} else {
+			f := modint.New998244353(int64(i - 1))
+			for j := i; j >= 1; j-- {
+				row[j] = row[j-1].Add(f.Mul(row[j]))
+			}
+			row[0] = modint.Mod998244353{} // S(n, 0) = 0 for n >= 1
+		}
+		if idx, ok := byM[i]; ok {
+			answer(row[:i+1], idx)
+		}
+	}
+
+	for _, m := range ms {
+		if m > bound {
+			answer(ntt.StirlingFirst(m), byM[m])
+		}
 	}
-	ans := (stirlingSum[M][needR] - sub + MOD) % MOD
-	return ans
+	return results
 }
 
 func main() {
-	reader := bufio.NewReaderSize(os.Stdin, 1<<20)
-	writer := bufio.NewWriterSize(os.Stdout, 1<<20)
+	reader := fastio.NewReader(os.Stdin)
+	writer := fastio.NewWriter(os.Stdout)
 	defer writer.Flush()
 
-	var t int
-	if _, err := fmt.Fscan(reader, &t); err != nil {
+	t := reader.Int()
+	if reader.Err() != nil {
 		return
 	}
 
 	// Читаем все тесты
-	testCases := make([]struct {
-		n, q, l, r int
-		b, c       []int
-	}, t)
+	testCases := make([]testCase, t)
 
 	for i := 0; i < t; i++ {
-		var n, q, l, r int
-		fmt.Fscan(reader, &n, &q, &l, &r)
+		n, q, l, r := reader.Int(), reader.Int(), reader.Int(), reader.Int()
 
 		b := make([]int, q)
 		c := make([]int, q)
 		for j := 0; j < q; j++ {
-			fmt.Fscan(reader, &b[j])
+			b[j] = reader.Int()
 		}
 		for j := 0; j < q; j++ {
-			fmt.Fscan(reader, &c[j])
+			c[j] = reader.Int()
 		}
 
-		testCases[i] = struct {
-			n, q, l, r int
-			b, c       []int
-		}{n, q, l, r, b, c}
+		testCases[i] = testCase{n, q, l, r, b, c}
 	}
 
 	// Обрабатываем все тесты внутри checkLimits
 	var results []int64
-	checkLimits(1*time.Second, 128, func() {
-		results = make([]int64, t)
-		for i := 0; i < t; i++ {
-			results[i] = solve(testCases[i].n, testCases[i].q, testCases[i].l, testCases[i].r, testCases[i].b, testCases[i].c)
-		}
+	limits.Run(1*time.Second, 128, func() {
+		results = solveAll(testCases)
 	})
 
 	// Выводим результаты
 	for i := 0; i < t; i++ {
-		fmt.Fprintln(writer, results[i])
+		writer.Int64(results[i])
+		writer.WriteByte('\n')
 	}
 }
@@ -2,8 +2,12 @@ use std::env;
 use std::io::{self, BufRead, BufWriter, Write};
 use std::time::Instant;
 
-const MOD: i64 = 998244353;
-const MAX_N: usize = 3005;
+const MOD: u64 = 998244353;
+// Первообразный корень по модулю 998244353 = 119·2^23+1
+const ROOT: u64 = 3;
+// Во сколько раз строка чисел Стирлинга через NTT дороже шага рекуррентности
+// в пересчёте на m·log m (как nttCost в main.go)
+const NTT_COST: usize = 16;
 
 // get_memory_usage возвращает текущее использование памяти в байтах (приблизительно)
 // Работает на Linux (читает /proc/self/status) и macOS (использует системные вызовы)
@@ -106,52 +110,222 @@ fn check_limits(max_time_ms: u64, max_memory_mb: u64, f: impl FnOnce()) {
     }
 }
 
-fn init_stirling_sum() -> Vec<Vec<i64>> {
-    let mut stirling_sum = vec![vec![0i64; MAX_N]; MAX_N];
-    let mut prev_stirling = vec![0i64; MAX_N];
-    let mut curr_stirling = vec![0i64; MAX_N];
+fn pow_mod(mut a: u64, mut e: u64) -> u64 {
+    let mut r = 1;
+    a %= MOD;
+    while e > 0 {
+        if e & 1 == 1 {
+            r = r * a % MOD;
+        }
+        a = a * a % MOD;
+        e >>= 1;
+    }
+    r
+}
 
-    prev_stirling[0] = 1;
-    stirling_sum[0][0] = 1;
-    for j in 1..MAX_N {
-        stirling_sum[0][j] = 1;
+// ntt — преобразование на месте; a.len() — степень двойки
+fn ntt(a: &mut [u64], invert: bool) {
+    let n = a.len();
+    if n <= 1 {
+        return;
     }
+    let shift = 64 - n.trailing_zeros();
+    for i in 0..n {
+        let j = ((i as u64).reverse_bits() >> shift) as usize;
+        if i < j {
+            a.swap(i, j);
+        }
+    }
+    let mut ws = vec![0u64; n / 2];
+    let mut len = 2;
+    while len <= n {
+        let half = len / 2;
+        let mut w = pow_mod(ROOT, (MOD - 1) / len as u64);
+        if invert {
+            w = pow_mod(w, MOD - 2);
+        }
+        ws[0] = 1;
+        for j in 1..half {
+            ws[j] = ws[j - 1] * w % MOD;
+        }
+        for chunk in a.chunks_mut(len) {
+            let (lo, hi) = chunk.split_at_mut(half);
+            for j in 0..half {
+                let u = lo[j];
+                let v = hi[j] * ws[j] % MOD;
+                lo[j] = if u + v >= MOD { u + v - MOD } else { u + v };
+                hi[j] = if u >= v { u - v } else { u + MOD - v };
+            }
+        }
+        len <<= 1;
+    }
+    if invert {
+        let n_inv = pow_mod(n as u64, MOD - 2);
+        for x in a.iter_mut() {
+            *x = *x * n_inv % MOD;
+        }
+    }
+}
 
-    for i in 1..MAX_N {
-        curr_stirling[0] = 0; // S(n, 0) = 0 for n >= 1
-        stirling_sum[i][0] = 0;
-        let mut current_sum: i64 = 0;
-
-        for j in 1..=i {
-            // S(n, k) = S(n-1, k-1) + (n-1)*S(n-1, k)
-            let val = prev_stirling[j - 1] + ((i - 1) as i64 * prev_stirling[j]) % MOD;
-            curr_stirling[j] = val % MOD;
-            current_sum = (current_sum + curr_stirling[j]) % MOD;
-            stirling_sum[i][j] = current_sum;
+fn multiply(a: &[u64], b: &[u64]) -> Vec<u64> {
+    if a.is_empty() || b.is_empty() {
+        return Vec::new();
+    }
+    let size = a.len() + b.len() - 1;
+    if a.len().min(b.len()) <= 32 {
+        let mut res = vec![0u64; size];
+        for (i, &x) in a.iter().enumerate() {
+            for (j, &y) in b.iter().enumerate() {
+                res[i + j] = (res[i + j] + x * y) % MOD;
+            }
+        }
+        return res;
+    }
+    let n = size.next_power_of_two();
+    let mut fa = a.to_vec();
+    let mut fb = b.to_vec();
+    fa.resize(n, 0);
+    fb.resize(n, 0);
+    ntt(&mut fa, false);
+    ntt(&mut fb, false);
+    for i in 0..n {
+        fa[i] = fa[i] * fb[i] % MOD;
+    }
+    ntt(&mut fa, true);
+    fa.truncate(size);
+    fa
+}
+
+// taylor_shift возвращает коэффициенты a(x + c)
+fn taylor_shift(a: &[u64], c: u64) -> Vec<u64> {
+    let n = a.len();
+    let mut fact = vec![1u64; n + 1];
+    for i in 1..=n {
+        fact[i] = fact[i - 1] * i as u64 % MOD;
+    }
+    let mut inv_fact = vec![1u64; n + 1];
+    inv_fact[n] = pow_mod(fact[n], MOD - 2);
+    for i in (1..=n).rev() {
+        inv_fact[i - 1] = inv_fact[i] * i as u64 % MOD;
+    }
+    let mut f = vec![0u64; n];
+    let mut g = vec![0u64; n];
+    let mut pw = 1u64;
+    for i in 0..n {
+        f[n - 1 - i] = a[i] * fact[i] % MOD;
+        g[i] = pw * inv_fact[i] % MOD;
+        pw = pw * c % MOD;
+    }
+    let h = multiply(&f, &g);
+    (0..n).map(|j| h[n - 1 - j] * inv_fact[j] % MOD).collect()
+}
+
+// stirling_first возвращает c(n, k), k = 0..n — коэффициенты x(x+1)…(x+n−1),
+// удвоением R_2m(x) = R_m(x)·R_m(x+m)
+fn stirling_first(n: usize) -> Vec<u64> {
+    let mut r = vec![1u64];
+    let mut m = 0usize;
+    let bits = usize::BITS - n.leading_zeros();
+    for bit in (0..bits).rev() {
+        if m > 0 {
+            let shifted = taylor_shift(&r, m as u64);
+            r = multiply(&r, &shifted);
+            m *= 2;
         }
-        // Fill remaining sums with the total sum for this row
-        for j in (i + 1)..MAX_N {
-            stirling_sum[i][j] = current_sum;
+        if (n >> bit) & 1 == 1 {
+            r = multiply(&r, &[m as u64 % MOD, 1]);
+            m += 1;
         }
+    }
+    r
+}
 
-        // Update prev_stirling for next iteration
-        for j in 0..=i {
-            prev_stirling[j] = curr_stirling[j];
+struct TestCase {
+    n: usize,
+    q: usize,
+    l: usize,
+    r: usize,
+    b: Vec<usize>,
+    c: Vec<usize>,
+}
+
+// solve_all отвечает на все наборы: строки c(m, ·) до границы — одним проходом
+// по рекуррентности, остальные — через NTT (как solveAll в main.go)
+fn solve_all(tests: &[TestCase]) -> Vec<u64> {
+    let mut results = vec![0u64; tests.len()];
+    let mut queries = vec![(0usize, 0usize, 0usize); tests.len()];
+    let mut by_m: std::collections::BTreeMap<usize, Vec<usize>> = std::collections::BTreeMap::new();
+    for (i, tc) in tests.iter().enumerate() {
+        if let Some(q) = reduce(tc.n, tc.q, tc.l, tc.r, &tc.b, &tc.c) {
+            queries[i] = q;
+            by_m.entry(q.0).or_default().push(i);
+        }
+    }
+    if by_m.is_empty() {
+        return results;
+    }
+
+    let answer = |row: &[u64], idx: &[usize], results: &mut Vec<u64>| {
+        let mut prefix = vec![0u64; row.len() + 1];
+        for (j, &x) in row.iter().enumerate() {
+            prefix[j + 1] = (prefix[j] + x) % MOD;
+        }
+        for &i in idx {
+            let (_, lo, hi) = queries[i];
+            results[i] = (prefix[hi + 1] + MOD - prefix[lo]) % MOD;
+        }
+    };
+
+    // Граница прохода — одно из m или отсутствует
+    let row_cost = |m: usize| NTT_COST * m * (usize::BITS - m.leading_zeros()) as usize;
+    let mut ntt_total: usize = by_m.keys().map(|&m| row_cost(m)).sum();
+    let mut bound: Option<usize> = None;
+    let mut best = ntt_total;
+    for &m in by_m.keys() {
+        ntt_total -= row_cost(m);
+        let cost = m * m / 2 + ntt_total;
+        if cost < best {
+            bound = Some(m);
+            best = cost;
         }
     }
 
-    stirling_sum
+    if let Some(bound) = bound {
+        // row — строка c(i, ·), обновляется на месте справа налево
+        let mut row = vec![0u64; bound + 1];
+        for i in 0..=bound {
+            if i == 0 {
+                row[0] = 1;
+            } else {
+                let f = (i - 1) as u64;
+                for j in (1..=i).rev() {
+                    row[j] = (row[j - 1] + f * row[j]) % MOD;
+                }
+                row[0] = 0; // S(n, 0) = 0 for n >= 1
+            }
+            if let Some(idx) = by_m.get(&i) {
+                answer(&row[..=i], idx, &mut results);
+            }
+        }
+    }
+
+    for (&m, idx) in by_m.iter() {
+        if bound.map_or(true, |b| m > b) {
+            answer(&stirling_first(m), idx, &mut results);
+        }
+    }
+    results
 }
 
-fn solve(
+// reduce сводит набор к сумме c(m, k) по k из [lo, hi]; None, если ответ 0
+fn reduce(
     n: usize,
     q: usize,
     l: usize,
     r: usize,
     b: &[usize],
     c: &[usize],
-    stirling_sum: &[Vec<i64>],
-) -> i64 {
+) -> Option<(usize, usize, usize)> {
     let mut in_deg = vec![0; n + 1];
     let mut out_deg = vec![0; n + 1];
     let mut adj = vec![0; n + 1];
@@ -169,7 +343,7 @@ fn solve(
     }
 
     if !possible {
-        return 0;
+        return None;
     }
 
     // M = n - q (number of path components)
@@ -216,24 +390,18 @@ fn solve(
     let mut need_r = if r >= fixed_cycles {
         r - fixed_cycles
     } else {
-        return 0;
+        return None;
     };
 
     if need_l > m {
-        return 0;
+        return None;
     }
     if need_r > m {
         need_r = m;
     }
 
     // Answer is sum of Stirling numbers [M][k] for k in [needL, needR]
-    let sub = if need_l > 0 {
-        stirling_sum[m][need_l - 1]
-    } else {
-        0
-    };
-    let ans = (stirling_sum[m][need_r] - sub + MOD) % MOD;
-    ans
+    Some((m, need_l, need_r))
 }
 
 fn main() {
@@ -242,14 +410,12 @@ fn main() {
     let stdout = io::stdout();
     let mut writer = BufWriter::new(stdout.lock());
 
-    // Initialize Stirling numbers table
-    let stirling_sum = init_stirling_sum();
-
     check_limits(1000, 128, || {
         let mut line = String::new();
         reader.read_line(&mut line).unwrap();
         let t: usize = line.trim().parse().unwrap();
 
+        let mut tests = Vec::with_capacity(t);
         for _ in 0..t {
             let mut line = String::new();
             reader.read_line(&mut line).unwrap();
@@ -275,7 +441,10 @@ fn main() {
                 .map(|x| x.parse().unwrap())
                 .collect();
 
-            let result = solve(n, q, l, r, &b, &c, &stirling_sum);
+            tests.push(TestCase { n, q, l, r, b, c });
+        }
+
+        for result in solve_all(&tests) {
             writeln!(writer, "{}", result).unwrap();
         }
     });
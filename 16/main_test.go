@@ -4,10 +4,13 @@ import (
 	"bufio"
 	"bytes"
 	"fmt"
+	"math/rand"
 	"runtime"
 	"strings"
 	"testing"
 	"time"
+
+	"yandex-2025-winter/lib/modint"
 )
 
 // Тест на примеры из условия задачи
@@ -355,6 +358,49 @@ func TestSolveMemoryUsage(t *testing.T) {
 	}
 }
 
+// TestSolveAllMatchesSolve сравнивает пакетный ответ, где строки Стирлинга
+// считаются проходом по рекуррентности, с ответами по одному набору, где
+// для больших n строка считается через NTT
+func TestSolveAllMatchesSolve(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	tests := make([]testCase, 300)
+	for i := range tests {
+		n := 1 + rng.Intn(600)
+		q := rng.Intn(n/2 + 1)
+		perm := rng.Perm(n)
+		b, c := make([]int, q), make([]int, q)
+		for j := range q {
+			b[j], c[j] = j+1, perm[j]+1
+		}
+		l := rng.Intn(n)
+		tests[i] = testCase{n, q, l, l + rng.Intn(n-l), b, c}
+	}
+	results := solveAll(tests)
+	for i, tc := range tests {
+		if want := solve(tc.n, tc.q, tc.l, tc.r, tc.b, tc.c); results[i] != want {
+			t.Fatalf("набор %d (n=%d, q=%d, l=%d, r=%d): solveAll = %d, solve = %d",
+				i, tc.n, tc.q, tc.l, tc.r, results[i], want)
+		}
+	}
+}
+
+// TestSolveLargeN проверяет n за пределами прежней таблицы 3005×3005:
+// без фиксированных кабелей и с l = 0, r = n−1 подходят все перестановки,
+// кроме тождественной (n циклов), — ответ n! − 1
+func TestSolveLargeN(t *testing.T) {
+	const n = 200000
+	want := modint.New998244353(1)
+	for i := 2; i <= n; i++ {
+		want = want.Mul(modint.New998244353(int64(i)))
+	}
+	want = want.Sub(modint.New998244353(1))
+
+	// Время для n = 2·10^5 меряет BenchmarkSolveAll
+	if got := solve(n, 0, 0, n-1, nil, nil); got != want.Val() {
+		t.Errorf("solve(n=%d) = %d, ожидалось n! − 1 = %d", n, got, want.Val())
+	}
+}
+
 // BenchmarkSolve проверяет производительность решения для различных размеров входных данных
 func BenchmarkSolve(b *testing.B) {
 	benchmarks := []struct {
@@ -422,3 +468,27 @@ func generateFixedCables(n, q int) []int {
 	}
 	return result
 }
+
+// BenchmarkSolveAll проверяет пакетный ответ: много разных n, где выгоден
+// проход по рекуррентности, и одно большое n, где выгоден NTT
+func BenchmarkSolveAll(b *testing.B) {
+	many := make([]testCase, 5000)
+	for i := range many {
+		n := 3000 - i%3000
+		many[i] = testCase{n: n, l: 0, r: n - 1}
+	}
+	benchmarks := []struct {
+		name  string
+		tests []testCase
+	}{
+		{"t=5000, n≤3000", many},
+		{"t=1, n=2·10^5", []testCase{{n: 200000, l: 0, r: 199999}}},
+	}
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			for b.Loop() {
+				solveAll(bm.tests)
+			}
+		})
+	}
+}
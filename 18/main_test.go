@@ -1,10 +1,16 @@
 package main
 
 import (
+	"container/heap"
 	"fmt"
+	"math/rand"
 	"runtime"
+	"slices"
+	"strings"
 	"testing"
 	"time"
+
+	"yandex-2025-winter/lib/stress"
 )
 
 func TestSolveTestCase(t *testing.T) {
@@ -121,21 +127,435 @@ func TestSolveMemoryUsage(t *testing.T) {
 	}
 }
 
+// maxInput возвращает худший по размеру вход: n=400, m=100, 40 000 рёбер A→B
+func maxInput() (a, b []int64) {
+	r := rand.New(rand.NewSource(1))
+	a, b = make([]int64, 400), make([]int64, 100)
+	for i := range a {
+		a[i] = r.Int63n(1e9 + 1)
+	}
+	for i := range b {
+		b[i] = 1 + r.Int63n(1e9)
+	}
+	return a, b
+}
+
+// BenchmarkSolve сравнивает прежнюю реализацию (штраф −M на обязательных
+// местах) с сетью с нижними границами на обоих способах из lib/flow
 func BenchmarkSolve(b *testing.B) {
-	n := 400
-	m := 100
-	arrA := make([]int64, n)
-	arrB := make([]int64, m)
+	arrA, arrB := maxInput()
+	b.Run("legacy", func(b *testing.B) {
+		for b.Loop() {
+			legacySolveTestCase(len(arrA), len(arrB), arrA, arrB)
+		}
+	})
+	b.Run("dijkstra", func(b *testing.B) {
+		for b.Loop() {
+			g, source, sink, _ := network(arrA, arrB)
+			g.MinCostFlow(source, sink, len(arrA))
+		}
+	})
+	b.Run("scaling", func(b *testing.B) {
+		for b.Loop() {
+			g, source, sink, _ := network(arrA, arrB)
+			g.MinCostFlowScaling(source, sink, len(arrA))
+		}
+	})
+}
+
+// TestAssign проверяет распределение: размеры групп отличаются не больше
+// чем на 1, сумма операций по группам равна ответу, а ответ совпадает с
+// прежней реализацией на входах крупнее перебора
+func TestAssign(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for range 200 {
+		n, m := 1+r.Intn(60), 1+r.Intn(12)
+		a, b := make([]int64, n), make([]int64, m)
+		for i := range a {
+			a[i] = r.Int63n(1000)
+		}
+		for j := range b {
+			b[j] = 1 + r.Int63n(50)
+		}
+		cost, group := assign(a, b)
+		size := make([]int, m)
+		var sum int64
+		for i, j := range group {
+			size[j]++
+			sum += (b[j] - a[i]%b[j]) % b[j]
+		}
+		if slices.Max(size)-slices.Min(size) > 1 {
+			t.Fatalf("a=%v b=%v: размеры групп %v", a, b, size)
+		}
+		if sum != cost {
+			t.Fatalf("a=%v b=%v: распределение стоит %d, ответ %d", a, b, sum, cost)
+		}
+		if want := legacySolveTestCase(n, m, a, b); cost != want {
+			t.Fatalf("a=%v b=%v: ответ %d, прежняя реализация %d", a, b, cost, want)
+		}
+	}
+}
+
+// input18 — один тестовый набор: последовательности A и B
+type input18 struct {
+	a, b []int64
+}
+
+func (in input18) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "1\n%d %d\n", len(in.a), len(in.b))
+	for _, s := range [][]int64{in.a, in.b} {
+		for i, v := range s {
+			if i > 0 {
+				sb.WriteByte(' ')
+			}
+			fmt.Fprint(&sb, v)
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// parseInput18 читает вход в формате условия с одним набором
+func parseInput18(s string) (input18, error) {
+	r := strings.NewReader(s)
+	var t, n, m int
+	if _, err := fmt.Fscan(r, &t, &n, &m); err != nil {
+		return input18{}, err
+	}
+	if t != 1 {
+		return input18{}, fmt.Errorf("T = %d, поддерживается только один набор", t)
+	}
+	in := input18{make([]int64, n), make([]int64, m)}
+	for _, s := range [][]int64{in.a, in.b} {
+		for i := range s {
+			if _, err := fmt.Fscan(r, &s[i]); err != nil {
+				return input18{}, err
+			}
+		}
+	}
+	return in, nil
+}
+
+// shrinkInput18 удаляет элементы A и B и уменьшает значения: a — к 0, b — к 1
+func shrinkInput18(in input18) []input18 {
+	var out []input18
+	for _, a := range stress.ShrinkSlice(in.a, 1) {
+		out = append(out, input18{a, in.b})
+	}
+	for _, b := range stress.ShrinkSlice(in.b, 1) {
+		out = append(out, input18{in.a, b})
+	}
+	for _, a := range stress.ShrinkEach(in.a, func(x int64) []int64 { return stress.ShrinkInt(x, 0) }) {
+		out = append(out, input18{a, in.b})
+	}
+	for _, b := range stress.ShrinkEach(in.b, func(x int64) []int64 { return stress.ShrinkInt(x, 1) }) {
+		out = append(out, input18{in.a, b})
+	}
+	return out
+}
+
+// TestStress сравнивает поток минимальной стоимости с перебором разбиений
+func TestStress(t *testing.T) {
+	stress.Run(t, stress.Problem[input18, int64]{
+		Gen: func(r *rand.Rand) input18 {
+			in := input18{make([]int64, 1+r.Intn(6)), make([]int64, 1+r.Intn(3))}
+			for i := range in.a {
+				in.a[i] = int64(r.Intn(30))
+			}
+			for i := range in.b {
+				in.b[i] = int64(1 + r.Intn(8))
+			}
+			return in
+		},
+		Solve:  func(in input18) int64 { return solveTestCase(len(in.a), len(in.b), in.a, in.b) },
+		Oracle: solveBruteForce,
+		Format: input18.String,
+		Parse:  parseInput18,
+		Shrink: shrinkInput18,
+	})
+}
+
+// solveBruteForce перебирает все m^n распределения элементов A по группам
+// и выбирает самое дешёвое, в котором размеры групп отличаются не больше чем на 1
+func solveBruteForce(in input18) int64 {
+	n, m := len(in.a), len(in.b)
+	size := make([]int, m)
+	best := int64(-1)
+	var rec func(i int, cost int64)
+	rec = func(i int, cost int64) {
+		if i == n {
+			lo, hi := size[0], size[0]
+			for _, s := range size {
+				lo, hi = min(lo, s), max(hi, s)
+			}
+			if hi-lo <= 1 && (best < 0 || cost < best) {
+				best = cost
+			}
+			return
+		}
+		for j := 0; j < m; j++ {
+			size[j]++
+			rec(i+1, cost+(in.b[j]-in.a[i]%in.b[j])%in.b[j])
+			size[j]--
+		}
+	}
+	rec(0, 0)
+	return best
+}
+
+// Прежняя реализация: поток минимальной стоимости без нижних границ,
+// обязательные q мест в группе заполняются штрафом −M. Оставлена для
+// сравнения в BenchmarkSolve и TestAssign
+
+// legacyEdge represents a directed edge in the graph
+type legacyEdge struct {
+	to       int
+	capacity int
+	flow     int
+	cost     int64
+	rev      int // index of the reverse edge in graph[to]
+}
+
+// legacyGraph represents the flow network
+type legacyGraph struct {
+	adj [][]legacyEdge
+}
+
+func newLegacyGraph(n int) *legacyGraph {
+	return &legacyGraph{
+		adj: make([][]legacyEdge, n),
+	}
+}
+
+func (g *legacyGraph) AddEdge(from, to, cap int, cost int64) {
+	forward := legacyEdge{to: to, capacity: cap, flow: 0, cost: cost, rev: len(g.adj[to])}
+	backward := legacyEdge{to: from, capacity: 0, flow: 0, cost: -cost, rev: len(g.adj[from])}
+	g.adj[from] = append(g.adj[from], forward)
+	g.adj[to] = append(g.adj[to], backward)
+}
+
+const legacyInf = 1e18
+
+// minCostMaxFlow finds the minimum cost to send `k` units of flow from s to t
+// Returns -1 if it's impossible to send `k` units
+func (g *legacyGraph) minCostMaxFlow(s, t int, k int) int64 {
+	n := len(g.adj)
+	potential := make([]int64, n)
+
+	totalFlow := 0
+	minCost := int64(0)
+
+	// Initial potentials using SPFA to handle negative costs
+	dist := make([]int64, n)
+	parentEdge := make([]int, n)
+	parentNode := make([]int, n)
 
+	inQueue := make([]bool, n)
+	queue := make([]int, 0, n)
+
+	// SPFA initialization
 	for i := 0; i < n; i++ {
-		arrA[i] = int64(1e9)
+		dist[i] = legacyInf
 	}
-	for i := 0; i < m; i++ {
-		arrB[i] = int64(1e9)
+	dist[s] = 0
+	queue = append(queue, s)
+	inQueue[s] = true
+
+	for len(queue) > 0 {
+		u := queue[0]
+		queue = queue[1:]
+		inQueue[u] = false
+
+		for i := range g.adj[u] {
+			e := &g.adj[u][i]
+			if e.capacity > e.flow {
+				if dist[e.to] > dist[u]+e.cost {
+					dist[e.to] = dist[u] + e.cost
+					if !inQueue[e.to] {
+						queue = append(queue, e.to)
+						inQueue[e.to] = true
+					}
+				}
+			}
+		}
+	}
+
+	// If sink is unreachable even initially (should not happen in this problem)
+	if dist[t] == legacyInf {
+		return -1
+	}
+
+	// Update potentials
+	for i := 0; i < n; i++ {
+		if dist[i] != legacyInf {
+			potential[i] = dist[i]
+		}
+	}
+
+	// Main loop with Dijkstra
+	for totalFlow < k {
+		// Dijkstra
+		for i := 0; i < n; i++ {
+			dist[i] = legacyInf
+		}
+		dist[s] = 0
+
+		pq := &legacyQueue{}
+		heap.Init(pq)
+		heap.Push(pq, &legacyItem{value: s, priority: 0})
+
+		for pq.Len() > 0 {
+			item := heap.Pop(pq).(*legacyItem)
+			u := item.value
+			d := item.priority
+
+			if d > dist[u] {
+				continue
+			}
+
+			for i := range g.adj[u] {
+				e := &g.adj[u][i]
+				if e.capacity-e.flow > 0 {
+					newDist := dist[u] + e.cost + potential[u] - potential[e.to]
+					if dist[e.to] > newDist {
+						dist[e.to] = newDist
+						parentNode[e.to] = u
+						parentEdge[e.to] = i
+						heap.Push(pq, &legacyItem{value: e.to, priority: newDist})
+					}
+				}
+			}
+		}
+
+		if dist[t] == legacyInf {
+			return -1 // Cannot push more flow
+		}
+
+		// Update potentials
+		for i := 0; i < n; i++ {
+			if dist[i] != legacyInf {
+				potential[i] += dist[i]
+			}
+		}
+
+		// Push flow
+		push := k - totalFlow
+		curr := t
+		for curr != s {
+			p := parentNode[curr]
+			idx := parentEdge[curr]
+			available := g.adj[p][idx].capacity - g.adj[p][idx].flow
+			if available < push {
+				push = available
+			}
+			curr = p
+		}
+
+		totalFlow += push
+		curr = t
+		for curr != s {
+			p := parentNode[curr]
+			idx := parentEdge[curr]
+			g.adj[p][idx].flow += push
+			revIdx := g.adj[p][idx].rev
+			g.adj[curr][revIdx].flow -= push
+			minCost += int64(push) * g.adj[p][idx].cost
+			curr = p
+		}
+	}
+
+	return minCost
+}
+
+// legacyQueue implementation
+type legacyItem struct {
+	value    int
+	priority int64
+	index    int
+}
+
+type legacyQueue []*legacyItem
+
+func (pq legacyQueue) Len() int { return len(pq) }
+
+func (pq legacyQueue) Less(i, j int) bool {
+	return pq[i].priority < pq[j].priority
+}
+
+func (pq legacyQueue) Swap(i, j int) {
+	pq[i], pq[j] = pq[j], pq[i]
+	pq[i].index = i
+	pq[j].index = j
+}
+
+func (pq *legacyQueue) Push(x interface{}) {
+	n := len(*pq)
+	item := x.(*legacyItem)
+	item.index = n
+	*pq = append(*pq, item)
+}
+
+func (pq *legacyQueue) Pop() interface{} {
+	old := *pq
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil  // avoid memory leak
+	item.index = -1 // for safety
+	*pq = old[0 : n-1]
+	return item
+}
+
+func legacySolveTestCase(n, m int, a []int64, b []int64) int64 {
+	// Source = 0, Sink = n + m + 1
+	// Nodes 1..n: A
+	// Nodes n+1..n+m: B
+	source := 0
+	sink := n + m + 1
+	numNodes := n + m + 2
+
+	g := newLegacyGraph(numNodes)
+
+	// Edges from Source to A
+	for i := 0; i < n; i++ {
+		g.AddEdge(source, i+1, 1, 0)
 	}
 
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		solveTestCase(n, m, arrA, arrB)
+	// Edges from A to B
+	for i := 0; i < n; i++ {
+		for j := 0; j < m; j++ {
+			val := a[i]
+			div := b[j]
+			rem := val % div
+			cost := int64(0)
+			if rem != 0 {
+				cost = div - rem
+			}
+			g.AddEdge(i+1, n+1+j, 1, cost)
+		}
 	}
+
+	// Edges from B to Sink
+	// Group sizes: n = q*m + r
+	q := n / m
+
+	// Large constant to force filling the first q slots
+	const M = 100000000000000 // 10^14
+
+	for j := 0; j < m; j++ {
+		if q > 0 {
+			// Mandatory q slots with high priority (negative cost)
+			g.AddEdge(n+1+j, sink, q, -M)
+		}
+		// Extra capacity for remainder
+		g.AddEdge(n+1+j, sink, 1, 0)
+	}
+
+	// Calculate Min Cost for flow = n
+	rawCost := g.minCostMaxFlow(source, sink, n)
+
+	// Adjust cost by removing the artificial negative costs
+	realCost := rawCost + int64(q)*int64(m)*M
+
+	return realCost
 }
@@ -17,9 +17,12 @@ func runSolver(input string) string {
 	oldStdout := os.Stdout
 
 	// Create pipe for stdin
+	// Write from a goroutine: large inputs do not fit into the pipe buffer
 	r, w, _ := os.Pipe()
-	w.WriteString(input)
-	w.Close()
+	go func() {
+		w.WriteString(input)
+		w.Close()
+	}()
 	os.Stdin = r
 
 	// Create pipe for stdout
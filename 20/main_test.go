@@ -9,6 +9,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"yandex-2025-winter/lib/trie"
 )
 
 // Тест на примере из условия
@@ -62,7 +64,82 @@ func TestSolveEdgeCases(t *testing.T) {
 	runTest(t, input1, expected1)
 }
 
+// Сравнение с перебором по x на малых значениях: при a_i < 16 и x ≥ 16
+// все a_i ⊕ x ≥ 16, mex = 0, так что хватает x < 16
+func TestSolveBrute(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	brute := func(a []int) int {
+		best := 0
+		for x := 0; x < 16; x++ {
+			seen := make([]bool, 17)
+			for _, v := range a {
+				seen[v^x] = true
+			}
+			mex := 0
+			for seen[mex] {
+				mex++
+			}
+			best = max(best, mex)
+		}
+		return best
+	}
+	for iter := 0; iter < 200; iter++ {
+		n, q := 1+rng.Intn(16), 1+rng.Intn(20)
+		a := make([]int, n)
+		var input, expected strings.Builder
+		fmt.Fprintf(&input, "1\n%d %d\n", n, q)
+		for i := range a {
+			a[i] = rng.Intn(16)
+			fmt.Fprintf(&input, "%d ", a[i])
+		}
+		fmt.Fprintf(&expected, "%d\n", brute(a))
+		for k := 0; k < q; k++ {
+			j, v := rng.Intn(n), rng.Intn(16)
+			a[j] = v
+			fmt.Fprintf(&input, "\n%d %d", j+1, v)
+			fmt.Fprintf(&expected, "%d\n", brute(a))
+		}
+		runTest(t, input.String(), expected.String())
+	}
+}
+
 // Тест на производительность (Time Limit)
+// TestXormexAgg сверяет агрегат бора с перебором x после каждой вставки и
+// удаления
+func TestXormexAgg(t *testing.T) {
+	// bruteXormex — наибольший mex{y ⊕ x} по всем x для мультимножества m
+	bruteXormex := func(m map[int]int, bits int) int32 {
+		best := 0
+		for x := range 1 << bits {
+			mex := 0
+			for m[mex^x] > 0 {
+				mex++
+			}
+			best = max(best, mex)
+		}
+		return int32(best)
+	}
+	r := rand.New(rand.NewSource(2))
+	for _, bits := range []int{1, 2, 4, 6} {
+		tr := trie.NewWithAggregate(bits, xormexAgg{})
+		m := map[int]int{}
+		for step := range 2000 {
+			x := r.Intn(1 << bits)
+			if r.Intn(3) == 0 {
+				if tr.Delete(x) {
+					m[x]--
+				}
+			} else {
+				tr.Insert(x)
+				m[x]++
+			}
+			if got, want := tr.Aggregate().best, bruteXormex(m, bits); got != want {
+				t.Fatalf("bits=%d шаг %d: xormex = %d, ожидалось %d", bits, step, got, want)
+			}
+		}
+	}
+}
+
 func TestSolveTimeLimit(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipping time limit test in short mode")
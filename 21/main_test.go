@@ -1,7 +1,6 @@
 package main
 
 import (
-	"bufio"
 	"bytes"
 	"fmt"
 	"math"
@@ -10,6 +9,9 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"yandex-2025-winter/lib/checker"
+	"yandex-2025-winter/lib/fastio"
 )
 
 func TestFuzzRobustness(t *testing.T) {
@@ -32,9 +34,7 @@ func TestFuzzRobustness(t *testing.T) {
 		}
 
 		input := sb.String()
-		reader = bufio.NewReader(strings.NewReader(input))
-		var outBuf bytes.Buffer
-		writer = bufio.NewWriter(&outBuf)
+		var out string
 
 		// Capture panic
 		func() {
@@ -43,11 +43,15 @@ func TestFuzzRobustness(t *testing.T) {
 					t.Fatalf("Panic on iter %d: %v\nInput:\n%s", iter, r, input)
 				}
 			}()
-			solve()
-			writer.Flush()
+			out = runSolve(input)
 		}()
 
-		// We don't check correctness here (hard), just no crashes and reasonable speed.
+		// A YES answer is verified by the checker; NO cannot be verified without a reference.
+		if strings.HasPrefix(out, "YES") {
+			if res := check21.Check(input, out, ""); res.Verdict != checker.OK {
+				t.Fatalf("iter %d (seed %d): %v: %s\nInput:\n%sOutput:\n%s", iter, seed, res.Verdict, res.Message, input, out)
+			}
+		}
 	}
 	t.Logf("Ran %d iterations without panic.", iter)
 }
@@ -56,23 +60,17 @@ func TestStrictMEC(t *testing.T) {
 	R_target := 1.0 + 1e-10
 	L := R_target * math.Sqrt(3.0)
 
-	p0 := Point{0, 0}
-	p1 := Point{L, 0}
-	p2 := Point{L / 2, L * math.Sqrt(3.0) / 2}
+	p0 := Point{X: 0, Y: 0}
+	p1 := Point{X: L, Y: 0}
+	p2 := Point{X: L / 2, Y: L * math.Sqrt(3.0) / 2}
 
 	var sb strings.Builder
 	sb.WriteString("1\n3\n")
-	sb.WriteString(fmt.Sprintf("%.15f %.15f\n", p0.x, p0.y))
-	sb.WriteString(fmt.Sprintf("%.15f %.15f\n", p1.x, p1.y))
-	sb.WriteString(fmt.Sprintf("%.15f %.15f\n", p2.x, p2.y))
-
-	reader = bufio.NewReader(strings.NewReader(sb.String()))
-	var outBuf bytes.Buffer
-	writer = bufio.NewWriter(&outBuf)
-	solve()
-	writer.Flush()
+	sb.WriteString(fmt.Sprintf("%.15f %.15f\n", p0.X, p0.Y))
+	sb.WriteString(fmt.Sprintf("%.15f %.15f\n", p1.X, p1.Y))
+	sb.WriteString(fmt.Sprintf("%.15f %.15f\n", p2.X, p2.Y))
 
-	out := outBuf.String()
+	out := runSolve(sb.String())
 	lines := strings.Split(strings.TrimSpace(out), "\n")
 	k, _ := strconv.Atoi(lines[1])
 
@@ -95,53 +93,32 @@ func TestSample(t *testing.T) {
 	runAndValidate(t, input)
 }
 
-func runAndValidate(t *testing.T, input string) {
-	reader = bufio.NewReader(strings.NewReader(input))
-	var outBuf bytes.Buffer
-	writer = bufio.NewWriter(&outBuf)
+// TestTangentDisks — входы, где расстояния между точками ровно 2 и 4:
+// круги на парах точек касаются, и границы решаются точными предикатами,
+// а не допуском на расстояние между центрами
+func TestTangentDisks(t *testing.T) {
+	runAndValidate(t, "1\n4\n0 1\n0 -1\n2 1\n2 -1\n")
+	runAndValidate(t, "1\n3\n-1 0\n1 0\n3 0\n")
+}
 
-	start := time.Now()
+// check21 is the special judge for this problem from lib/checker
+var check21, _ = checker.For("21")
+
+func runSolve(input string) string {
+	reader = fastio.NewReader(strings.NewReader(input))
+	var outBuf bytes.Buffer
+	writer = fastio.NewWriter(&outBuf)
 	solve()
 	writer.Flush()
-	_ = time.Since(start)
-
-	outReader := bufio.NewReader(&outBuf)
-	inReader := bufio.NewReader(strings.NewReader(input))
-
-	line, _ := inReader.ReadString('\n')
-	line = strings.TrimSpace(line)
-	if line == "" {
-		return
-	}
-	numTests, _ := strconv.Atoi(line)
-
-	for i := 0; i < numTests; i++ {
-		line, _ = inReader.ReadString('\n')
-		for strings.TrimSpace(line) == "" {
-			line, _ = inReader.ReadString('\n')
-		}
-		n, _ := strconv.Atoi(strings.TrimSpace(line))
-
-		points := make([]Point, n)
-		for j := 0; j < n; j++ {
-			line, _ = inReader.ReadString('\n')
-			parts := strings.Fields(line)
-			points[j].x, _ = strconv.ParseFloat(parts[0], 64)
-			points[j].y, _ = strconv.ParseFloat(parts[1], 64)
-		}
-
-		resLine, err := outReader.ReadString('\n')
-		if err != nil {
-			t.Fatalf("Test case %d: unexpected end of output", i)
-		}
-		res := strings.TrimSpace(resLine)
+	return outBuf.String()
+}
 
-		if res == "YES" {
-			line, _ = outReader.ReadString('\n')
-			k, _ := strconv.Atoi(strings.TrimSpace(line))
-			for j := 0; j < k; j++ {
-				outReader.ReadString('\n')
-			}
-		}
+// runAndValidate checks the output with the checker: disks must not overlap and must cover
+// every point. Without a reference answer NO cannot be confirmed, so NO fails here.
+func runAndValidate(t *testing.T, input string) {
+	t.Helper()
+	out := runSolve(input)
+	if res := check21.Check(input, out, ""); res.Verdict != checker.OK {
+		t.Errorf("%v: %s\nInput:\n%sOutput:\n%s", res.Verdict, res.Message, input, out)
 	}
 }
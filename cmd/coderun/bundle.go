@@ -0,0 +1,659 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// runBundle собирает решение задачи в один файл для отправки в Coderun
+func runBundle(args []string) error {
+	fs := flag.NewFlagSet("bundle", flag.ContinueOnError)
+	out := fs.String("o", "", "записать результат в файл вместо stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("ожидается номер задачи")
+	}
+
+	m, err := findModule()
+	if err != nil {
+		return err
+	}
+	dir, err := m.problemDir(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	src, err := bundle(m, dir)
+	if err != nil {
+		return err
+	}
+	if *out == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	return os.WriteFile(*out, src, 0o644)
+}
+
+// sourceFile — разобранный исходный файл вместе с текстом
+type sourceFile struct {
+	src  []byte
+	file *ast.File
+}
+
+// localPackage — пакет этого модуля, который нужно встроить в решение
+type localPackage struct {
+	path  string
+	name  string
+	files []*sourceFile
+	types *types.Package
+	info  *types.Info
+	units []*declUnit // объявления верхнего уровня в порядке исходника
+}
+
+// declUnit — объявление верхнего уровня или одна спецификация из группы
+// var/type; группа const остаётся целой, чтобы не сломать iota
+type declUnit struct {
+	file *sourceFile
+	decl ast.Decl
+	spec ast.Spec // nil — объявление целиком
+	node ast.Node // decl или spec
+	objs []types.Object
+	refs []types.Object // объявления модуля, на которые ссылается unit
+	recv types.Object   // тип получателя для методов
+	init bool
+	kept bool
+}
+
+// bundler загружает пакеты модуля и проверяет их типы; стандартная
+// библиотека импортируется обычным способом
+type bundler struct {
+	m     *module
+	fset  *token.FileSet
+	std   types.Importer
+	pkgs  map[string]*localPackage
+	order []*localPackage // в порядке завершения загрузки (зависимости раньше)
+}
+
+func newBundler(m *module) *bundler {
+	return &bundler{
+		m:    m,
+		fset: token.NewFileSet(),
+		std:  importer.Default(),
+		pkgs: make(map[string]*localPackage),
+	}
+}
+
+// Import реализует types.Importer
+func (b *bundler) Import(importPath string) (*types.Package, error) {
+	if !b.m.isLocal(importPath) {
+		return b.std.Import(importPath)
+	}
+	p, err := b.load(importPath, b.m.dirOf(importPath))
+	if err != nil {
+		return nil, err
+	}
+	return p.types, nil
+}
+
+// load разбирает и проверяет пакет из каталога dir: без _test.go и файлов,
+// исключённых ограничениями сборки
+func (b *bundler) load(importPath, dir string) (*localPackage, error) {
+	if p, ok := b.pkgs[importPath]; ok {
+		if p.types == nil {
+			return nil, fmt.Errorf("циклический импорт %s", importPath)
+		}
+		return p, nil
+	}
+	p := &localPackage{path: importPath}
+	b.pkgs[importPath] = p
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []*ast.File
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		if ok, err := build.Default.MatchFile(dir, name); err != nil || !ok {
+			continue
+		}
+		src, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		f, err := parser.ParseFile(b.fset, filepath.Join(dir, name), src, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		p.files = append(p.files, &sourceFile{src: src, file: f})
+		files = append(files, f)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%s: нет Go-файлов", dir)
+	}
+	p.name = files[0].Name.Name
+
+	p.info = &types.Info{
+		Defs:      make(map[*ast.Ident]types.Object),
+		Uses:      make(map[*ast.Ident]types.Object),
+		Implicits: make(map[ast.Node]types.Object),
+	}
+	conf := types.Config{Importer: b}
+	tpkg, err := conf.Check(importPath, b.fset, files, p.info)
+	if err != nil {
+		return nil, err
+	}
+	p.types = tpkg
+	p.collectUnits()
+	b.order = append(b.order, p)
+	return p, nil
+}
+
+// collectUnits разбивает файлы пакета на объявления и собирает их ссылки
+func (p *localPackage) collectUnits() {
+	for _, sf := range p.files {
+		for _, decl := range sf.file.Decls {
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				u := &declUnit{file: sf, decl: d, node: d, init: d.Recv == nil && d.Name.Name == "init"}
+				if obj := p.info.Defs[d.Name]; obj != nil && !u.init {
+					u.objs = []types.Object{obj}
+				}
+				if d.Recv != nil && len(d.Recv.List) > 0 {
+					u.recv = p.info.Uses[receiverTypeName(d.Recv.List[0].Type)]
+				}
+				p.units = append(p.units, p.withRefs(u))
+			case *ast.GenDecl:
+				if d.Tok == token.IMPORT {
+					continue
+				}
+				if d.Tok == token.CONST {
+					u := &declUnit{file: sf, decl: d, node: d}
+					for _, spec := range d.Specs {
+						u.objs = append(u.objs, p.specObjects(spec)...)
+					}
+					p.units = append(p.units, p.withRefs(u))
+					continue
+				}
+				for _, spec := range d.Specs {
+					u := &declUnit{file: sf, decl: d, spec: spec, node: spec, objs: p.specObjects(spec)}
+					p.units = append(p.units, p.withRefs(u))
+				}
+			}
+		}
+	}
+}
+
+// specObjects возвращает объекты, объявленные спецификацией
+func (p *localPackage) specObjects(spec ast.Spec) []types.Object {
+	var names []*ast.Ident
+	switch s := spec.(type) {
+	case *ast.ValueSpec:
+		names = s.Names
+	case *ast.TypeSpec:
+		names = []*ast.Ident{s.Name}
+	}
+	var objs []types.Object
+	for _, name := range names {
+		if obj := p.info.Defs[name]; obj != nil {
+			objs = append(objs, obj)
+		}
+	}
+	return objs
+}
+
+// withRefs заполняет ссылки unit на объявления верхнего уровня
+func (p *localPackage) withRefs(u *declUnit) *declUnit {
+	ast.Inspect(u.node, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok {
+			if obj := p.info.Uses[id]; obj != nil && isPackageLevel(obj) {
+				u.refs = append(u.refs, obj)
+			}
+		}
+		return true
+	})
+	return u
+}
+
+// receiverTypeName возвращает идентификатор типа получателя: *T, T[P] → T
+func receiverTypeName(expr ast.Expr) *ast.Ident {
+	for {
+		switch e := expr.(type) {
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.ParenExpr:
+			expr = e.X
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.IndexListExpr:
+			expr = e.X
+		case *ast.Ident:
+			return e
+		default:
+			return nil
+		}
+	}
+}
+
+// isPackageLevel сообщает, объявлен ли объект на уровне пакета
+func isPackageLevel(obj types.Object) bool {
+	return obj.Pkg() != nil && obj.Parent() == obj.Pkg().Scope()
+}
+
+// bundledName — имя объявления из встроенного пакета: limits.Run → limitsRun
+func bundledName(pkgName, name string) string {
+	r, size := utf8.DecodeRuneInString(name)
+	return pkgName + string(unicode.ToUpper(r)) + name[size:]
+}
+
+// freeName возвращает name или name2, name3, ..., не занятое в taken, и занимает его
+func freeName(name string, taken map[string]bool) string {
+	candidate := name
+	for i := 2; taken[candidate]; i++ {
+		candidate = name + strconv.Itoa(i)
+	}
+	taken[candidate] = true
+	return candidate
+}
+
+// edit — замена байтов [start, end) исходного файла
+type edit struct {
+	start, end int
+	text       string
+}
+
+// bundle собирает main.go из dir и используемые им объявления пакетов
+// модуля в один файл package main
+func bundle(m *module, dir string) ([]byte, error) {
+	b := newBundler(m)
+	mainPkg, err := b.load(dir, dir)
+	if err != nil {
+		return nil, err
+	}
+	if mainPkg.name != "main" {
+		return nil, fmt.Errorf("%s: ожидается package main, получен %s", dir, mainPkg.name)
+	}
+	libs := make([]*localPackage, 0, len(b.order))
+	for _, p := range b.order {
+		if p != mainPkg {
+			libs = append(libs, p)
+		}
+	}
+	markReachable(mainPkg, libs)
+
+	// Новые имена не должны совпасть ни с одним идентификатором исходников,
+	// иначе переименованная ссылка может попасть под локальную переменную
+	taken := make(map[string]bool)
+	for _, p := range b.order {
+		for _, sf := range p.files {
+			ast.Inspect(sf.file, func(n ast.Node) bool {
+				if id, ok := n.(*ast.Ident); ok {
+					taken[id.Name] = true
+				}
+				return true
+			})
+		}
+	}
+	renamed := make(map[types.Object]string)
+	for _, p := range libs {
+		for _, u := range p.units {
+			if !u.kept {
+				continue
+			}
+			for _, obj := range u.objs {
+				if obj.Name() != "_" {
+					renamed[obj] = freeName(bundledName(p.name, obj.Name()), taken)
+				}
+			}
+		}
+	}
+
+	// Имена верхнего уровня итогового файла: с ними не должны совпасть имена импортов
+	top := make(map[string]bool)
+	for _, name := range mainPkg.types.Scope().Names() {
+		top[name] = true
+	}
+	for _, name := range renamed {
+		top[name] = true
+	}
+	im := newImportSet(mainPkg, top, taken)
+	var body bytes.Buffer
+	text, err := b.rewriteMain(mainPkg, renamed, im)
+	if err != nil {
+		return nil, err
+	}
+	body.WriteString(text)
+	for _, p := range libs {
+		text, err := b.rewriteLib(p, renamed, im)
+		if err != nil {
+			return nil, err
+		}
+		if text != "" {
+			fmt.Fprintf(&body, "\n// ---- %s ----\n\n%s", p.path, text)
+		}
+	}
+
+	var out bytes.Buffer
+	out.WriteString("package main\n\n")
+	im.write(&out)
+	out.Write(body.Bytes())
+	return format.Source(out.Bytes())
+}
+
+// markReachable отмечает объявления пакетов модуля, достижимые из main и
+// функций init: вместе с типом сохраняются все его методы
+func markReachable(mainPkg *localPackage, libs []*localPackage) {
+	unitOf := make(map[types.Object]*declUnit)
+	methods := make(map[types.Object][]*declUnit)
+	var queue []*declUnit
+	for _, p := range libs {
+		for _, u := range p.units {
+			for _, obj := range u.objs {
+				unitOf[obj] = u
+			}
+			if u.recv != nil {
+				methods[u.recv] = append(methods[u.recv], u)
+			}
+			if u.init {
+				queue = append(queue, u)
+			}
+		}
+	}
+	queue = append(queue, mainPkg.units...)
+
+	for len(queue) > 0 {
+		u := queue[0]
+		queue = queue[1:]
+		if u.kept {
+			continue
+		}
+		u.kept = true
+		for _, obj := range u.refs {
+			if v, ok := unitOf[obj]; ok && !v.kept {
+				queue = append(queue, v)
+			}
+		}
+		for _, obj := range u.objs {
+			queue = append(queue, methods[obj]...)
+		}
+	}
+}
+
+// importSet — импорты стандартной библиотеки итогового файла
+type importSet struct {
+	names map[string]string // путь → имя в итоговом файле
+	used  map[string]bool   // пути, на которые есть ссылки
+	top   map[string]bool   // имена объявлений верхнего уровня
+	taken map[string]bool
+}
+
+func newImportSet(mainPkg *localPackage, top, taken map[string]bool) *importSet {
+	im := &importSet{names: make(map[string]string), used: make(map[string]bool), top: top, taken: taken}
+	// main переносится как есть, поэтому его имена импортов фиксированы
+	for _, sf := range mainPkg.files {
+		for _, spec := range sf.file.Imports {
+			if obj := importedPkg(mainPkg, spec); obj != nil {
+				im.names[obj.Imported().Path()] = obj.Name()
+			}
+		}
+	}
+	return im
+}
+
+// importedPkg возвращает объект импорта или nil для _ и . импортов
+func importedPkg(p *localPackage, spec *ast.ImportSpec) *types.PkgName {
+	var obj types.Object
+	if spec.Name != nil {
+		obj = p.info.Defs[spec.Name]
+	} else {
+		obj = p.info.Implicits[spec]
+	}
+	pn, _ := obj.(*types.PkgName)
+	return pn
+}
+
+// nameFor возвращает имя пакета в итоговом файле; при конфликте с другим
+// путём или объявлением верхнего уровня берётся свободное имя вида rand2
+func (im *importSet) nameFor(pkg *types.Package) string {
+	if name, ok := im.names[pkg.Path()]; ok {
+		return name
+	}
+	name := pkg.Name()
+	conflict := im.top[name]
+	for _, other := range im.names {
+		conflict = conflict || other == name
+	}
+	if conflict {
+		name = freeName(name, im.taken)
+	}
+	im.names[pkg.Path()] = name
+	return name
+}
+
+// write печатает блок import из использованных путей
+func (im *importSet) write(out *bytes.Buffer) {
+	var paths []string
+	for p := range im.used {
+		paths = append(paths, p)
+	}
+	if len(paths) == 0 {
+		return
+	}
+	sort.Strings(paths)
+	out.WriteString("import (\n")
+	for _, p := range paths {
+		name := im.names[p]
+		if pkg, err := importer.Default().Import(p); err == nil && pkg.Name() == name {
+			fmt.Fprintf(out, "\t%q\n", p)
+		} else {
+			fmt.Fprintf(out, "\t%s %q\n", name, p)
+		}
+	}
+	out.WriteString(")\n")
+}
+
+// rewriteMain возвращает файлы main без заголовка (package и import)
+// с переименованными ссылками на встроенные объявления
+func (b *bundler) rewriteMain(p *localPackage, renamed map[types.Object]string, im *importSet) (string, error) {
+	var sb strings.Builder
+	for _, sf := range p.files {
+		for _, spec := range sf.file.Imports {
+			importPath, _ := strconv.Unquote(spec.Path.Value)
+			if b.m.isLocal(importPath) {
+				continue
+			}
+			if spec.Name != nil && (spec.Name.Name == "_" || spec.Name.Name == ".") {
+				return "", fmt.Errorf("импорт %s %s не поддерживается", spec.Name.Name, spec.Path.Value)
+			}
+		}
+		cut := b.offset(sf.file.Name.End())
+		for _, decl := range sf.file.Decls {
+			if gd, ok := decl.(*ast.GenDecl); ok && gd.Tok == token.IMPORT {
+				cut = max(cut, b.offset(gd.End()))
+			}
+		}
+		edits, err := b.edits(p, sf.file, renamed, im)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(applyEdits(sf.src, cut, len(sf.src), edits))
+		sb.WriteString("\n")
+	}
+	return sb.String(), nil
+}
+
+// rewriteLib возвращает достижимые объявления пакета; пустая строка — ничего не нужно
+func (b *bundler) rewriteLib(p *localPackage, renamed map[types.Object]string, im *importSet) (string, error) {
+	var sb strings.Builder
+	for _, sf := range p.files {
+		var group *ast.GenDecl // открытая группа var (...) или type (...)
+		for _, u := range p.units {
+			if u.file != sf {
+				continue
+			}
+			if group != nil && u.decl != group {
+				sb.WriteString(")\n\n")
+				group = nil
+			}
+			if !u.kept {
+				continue
+			}
+			edits, err := b.edits(p, u.node, renamed, im)
+			if err != nil {
+				return "", err
+			}
+			gd, ok := u.decl.(*ast.GenDecl)
+			if u.spec == nil || !ok || !gd.Lparen.IsValid() {
+				start, end := b.extent(u.decl, docOf(u.decl))
+				sb.WriteString(applyEdits(sf.src, start, end, edits))
+				sb.WriteString("\n\n")
+				continue
+			}
+			if group == nil {
+				group = gd
+				if gd.Doc != nil {
+					sb.WriteString(string(sf.src[b.offset(gd.Doc.Pos()):b.offset(gd.Doc.End())]) + "\n")
+				}
+				sb.WriteString(gd.Tok.String() + " (\n")
+			}
+			start, end := b.extent(u.spec, docOf(u.spec))
+			if c := lineCommentOf(u.spec); c != nil {
+				end = b.offset(c.End())
+			}
+			sb.WriteString(applyEdits(sf.src, start, end, edits))
+			sb.WriteString("\n")
+		}
+		if group != nil {
+			sb.WriteString(")\n\n")
+		}
+	}
+	return sb.String(), nil
+}
+
+// edits собирает замены внутри node: ссылки на встроенные объявления
+// и имена пакетов стандартной библиотеки, которые пришлось сменить;
+// пакеты стандартной библиотеки отмечаются как использованные
+func (b *bundler) edits(p *localPackage, node ast.Node, renamed map[types.Object]string, im *importSet) ([]edit, error) {
+	var edits []edit
+	var err error
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.ImportSpec:
+			return false
+		case *ast.SelectorExpr:
+			// pkg.Name из встроенного пакета → pkgName
+			x, ok := n.X.(*ast.Ident)
+			if !ok {
+				return true
+			}
+			if _, ok := p.info.Uses[x].(*types.PkgName); !ok {
+				return true
+			}
+			if newName, ok := renamed[p.info.Uses[n.Sel]]; ok {
+				edits = append(edits, edit{b.offset(n.Pos()), b.offset(n.End()), newName})
+				return false
+			}
+		case *ast.Ident:
+			obj := p.info.Defs[n]
+			if obj == nil {
+				obj = p.info.Uses[n]
+			}
+			if obj == nil {
+				return true
+			}
+			if pn, ok := obj.(*types.PkgName); ok {
+				if b.m.isLocal(pn.Imported().Path()) {
+					err = fmt.Errorf("%s: пакет %s используется не через селектор", b.fset.Position(n.Pos()), pn.Name())
+					return false
+				}
+				name := im.nameFor(pn.Imported())
+				im.used[pn.Imported().Path()] = true
+				if name != n.Name {
+					edits = append(edits, edit{b.offset(n.Pos()), b.offset(n.End()), name})
+				}
+				return true
+			}
+			if !isPackageLevel(obj) {
+				return true
+			}
+			if newName, ok := renamed[obj]; ok {
+				edits = append(edits, edit{b.offset(n.Pos()), b.offset(n.End()), newName})
+			}
+		}
+		return true
+	})
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start < edits[j].start })
+	return edits, err
+}
+
+// applyEdits возвращает src[start:end] с заменами из этого отрезка
+func applyEdits(src []byte, start, end int, edits []edit) string {
+	var sb strings.Builder
+	pos := start
+	for _, e := range edits {
+		if e.start < start || e.end > end {
+			continue
+		}
+		sb.Write(src[pos:e.start])
+		sb.WriteString(e.text)
+		pos = e.end
+	}
+	sb.Write(src[pos:end])
+	return sb.String()
+}
+
+func (b *bundler) offset(pos token.Pos) int {
+	return b.fset.Position(pos).Offset
+}
+
+// extent возвращает границы узла вместе с его doc-комментарием
+func (b *bundler) extent(n ast.Node, doc *ast.CommentGroup) (int, int) {
+	start := b.offset(n.Pos())
+	if doc != nil {
+		start = b.offset(doc.Pos())
+	}
+	return start, b.offset(n.End())
+}
+
+func docOf(n ast.Node) *ast.CommentGroup {
+	switch n := n.(type) {
+	case *ast.FuncDecl:
+		return n.Doc
+	case *ast.GenDecl:
+		return n.Doc
+	case *ast.ValueSpec:
+		return n.Doc
+	case *ast.TypeSpec:
+		return n.Doc
+	}
+	return nil
+}
+
+func lineCommentOf(n ast.Node) *ast.CommentGroup {
+	switch n := n.(type) {
+	case *ast.ValueSpec:
+		return n.Comment
+	case *ast.TypeSpec:
+		return n.Comment
+	}
+	return nil
+}
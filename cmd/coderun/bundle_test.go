@@ -0,0 +1,210 @@
+package main
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// checkBundle проверяет, что результат — самодостаточный package main
+func checkBundle(t *testing.T, m *module, src []byte) *ast.File {
+	t.Helper()
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "main.go", src, 0)
+	if err != nil {
+		t.Fatalf("результат не разбирается: %v", err)
+	}
+	for _, spec := range f.Imports {
+		if strings.Contains(spec.Path.Value, m.path+"/") {
+			t.Errorf("остался локальный импорт %s", spec.Path.Value)
+		}
+	}
+	conf := types.Config{Importer: importer.Default()}
+	if _, err := conf.Check("main", fset, []*ast.File{f}, nil); err != nil {
+		t.Fatalf("результат не проходит проверку типов: %v", err)
+	}
+	return f
+}
+
+func TestBundle(t *testing.T) {
+	m, err := findModule()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	problems, err := m.allProblems()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, problem := range problems {
+		t.Run(problem, func(t *testing.T) {
+			dir, err := m.problemDir(problem)
+			if err != nil {
+				t.Fatal(err)
+			}
+			src, err := bundle(m, dir)
+			if err != nil {
+				t.Fatalf("bundle(%s): %v", problem, err)
+			}
+			f := checkBundle(t, m, src)
+			if f.Name.Name != "main" {
+				t.Errorf("package %s, ожидался main", f.Name.Name)
+			}
+			// Решения вызывают limits.Run, но не limits.Measure
+			if strings.Contains(string(src), "limitsMeasure(") {
+				t.Errorf("недостижимая limits.Measure попала в результат")
+			}
+		})
+	}
+}
+
+// bundleFixture — модуль с конфликтами имён, группой iota, ограничением
+// сборки, тестом и транзитивным импортом
+var bundleFixture = map[string]string{
+	"go.mod": "module fake\n\ngo 1.24\n",
+	"lib/b/b.go": `package b
+
+import "math/rand/v2"
+
+func Seeded() int { return rand.New(rand.NewPCG(1, 2)).IntN(100) }
+
+func Unused() int { return 0 }
+`,
+	"lib/a/a.go": `package a
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+
+	"fake/lib/b"
+)
+
+type Kind int
+
+const (
+	Zero Kind = iota
+	One
+	Two
+)
+
+var names = []string{"zero", "one", "two"}
+
+func (k Kind) String() string { return names[k] }
+
+var (
+	unusedVar = strings.Repeat("x", 3)
+	seed      = int64(b.Seeded()) // сид из другого пакета
+)
+
+func Pick(n int) []int {
+	s := rand.New(rand.NewSource(seed)).Perm(n)
+	sort.Ints(s)
+	return s
+}
+
+func Unused() string { return strings.ToUpper(unusedVar) }
+`,
+	"lib/a/a_test.go":  "package a\n\nне компилируется\n",
+	"lib/a/ignored.go": "//go:build ignore\n\npackage a\n\nfunc Pick() {}\n",
+	"01/main.go": `package main
+
+import (
+	"fmt"
+
+	"fake/lib/a"
+)
+
+func aPick() {}
+
+var sort = "main"
+
+func main() {
+	aPick()
+	fmt.Println(a.Two, a.Pick(5), sort)
+}
+`,
+}
+
+func TestBundlePrunesAndRenames(t *testing.T) {
+	root := t.TempDir()
+	for name, content := range bundleFixture {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m := &module{root: root, path: "fake"}
+	src, err := bundle(m, filepath.Join(root, "01"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkBundle(t, m, src)
+
+	out := string(src)
+	for _, want := range []string{"func aPick2(", "aTwo", `sort2 "sort"`, `"math/rand"`, `"math/rand/v2"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("в результате нет %q:\n%s", want, out)
+		}
+	}
+	for _, unwanted := range []string{"aUnused", "bUnused", `"strings"`} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("в результате осталось недостижимое %q:\n%s", unwanted, out)
+		}
+	}
+
+	if testing.Short() {
+		t.Skip("без запуска в -short")
+	}
+	bundled := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(bundled, src, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	want, err := exec.Command("go", "run", "-C", root, "./01").CombinedOutput()
+	if err != nil {
+		t.Fatalf("исходное решение: %v\n%s", err, want)
+	}
+	got, err := exec.Command("go", "run", bundled).CombinedOutput()
+	if err != nil {
+		t.Fatalf("собранное решение: %v\n%s", err, got)
+	}
+	if string(got) != string(want) {
+		t.Errorf("вывод собранного решения %q, исходного %q", got, want)
+	}
+}
+
+func TestFreeName(t *testing.T) {
+	taken := map[string]bool{"rand": true, "rand2": true}
+	if got := freeName("rand", taken); got != "rand3" {
+		t.Errorf("freeName(rand) = %q, ожидалось rand3", got)
+	}
+	if got := freeName("sort", taken); got != "sort" {
+		t.Errorf("freeName(sort) = %q, ожидалось sort", got)
+	}
+	if !taken["rand3"] || !taken["sort"] {
+		t.Errorf("выданные имена не заняты: %v", taken)
+	}
+}
+
+func TestBundledName(t *testing.T) {
+	tests := []struct{ pkg, name, want string }{
+		{"limits", "Run", "limitsRun"},
+		{"limits", "startHeapSampler", "limitsStartHeapSampler"},
+		{"limits", "OK", "limitsOK"},
+	}
+	for _, tt := range tests {
+		if got := bundledName(tt.pkg, tt.name); got != tt.want {
+			t.Errorf("bundledName(%q, %q) = %q, ожидалось %q", tt.pkg, tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"yandex-2025-winter/lib/checker"
+)
+
+// runCheck проверяет пару ввод/вывод чекером задачи
+func runCheck(args []string) error {
+	fs := flag.NewFlagSet("check", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 3 || fs.NArg() > 4 {
+		return fmt.Errorf("ожидается: check NN ввод вывод [ответ]")
+	}
+
+	problem := filepath.Base(fs.Arg(0))
+	if len(problem) == 1 {
+		problem = "0" + problem
+	}
+	c, ok := checker.For(problem)
+	if !ok {
+		return fmt.Errorf("для задачи %s нет чекера (есть для %v)", problem, checker.Problems())
+	}
+
+	var files [3]string
+	for i := 1; i < fs.NArg(); i++ {
+		data, err := readInput(fs.Arg(i))
+		if err != nil {
+			return err
+		}
+		files[i-1] = data
+	}
+
+	res := c.Check(files[0], files[1], files[2])
+	if res.Message != "" {
+		fmt.Printf("%v: %s\n", res.Verdict, res.Message)
+	} else {
+		fmt.Println(res.Verdict)
+	}
+	if res.Verdict != checker.OK {
+		return fmt.Errorf("вердикт %v", res.Verdict)
+	}
+	return nil
+}
+
+// readInput читает файл; "-" означает stdin
+func readInput(path string) (string, error) {
+	if path == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		return string(data), err
+	}
+	data, err := os.ReadFile(path)
+	return string(data), err
+}
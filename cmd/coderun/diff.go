@@ -0,0 +1,353 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"yandex-2025-winter/lib/checker"
+	"yandex-2025-winter/lib/gen"
+)
+
+// lang — реализация решения на одном языке
+type lang struct {
+	name     string
+	src      string // файл в каталоге задачи
+	compiler string // программа, которая должна быть в PATH
+	// args — аргументы компилятора; для Go не используется, собирает judge.build
+	args func(src, bin string) []string
+}
+
+// langs — реализации в порядке сравнения; первая — эталон
+var langs = []lang{
+	{name: "go", src: "main.go", compiler: "go"},
+	{name: "rust", src: "main.rs", compiler: "rustc", args: func(src, bin string) []string {
+		return []string{"-O", "--edition", "2021", "-o", bin, src}
+	}},
+	{name: "dart", src: "main.dart", compiler: "dart", args: func(src, bin string) []string {
+		return []string{"compile", "exe", "--verbosity=error", "-o", bin, src}
+	}},
+}
+
+// Запуски короче minGapTime слишком шумные, чтобы сравнивать время
+const minGapTime = 50 * time.Millisecond
+
+// runDiff сравнивает реализации на Go, Rust и Dart на одних и тех же входах
+func runDiff(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	n := fs.Int("n", 20, "число сгенерированных входов на задачу")
+	size := fs.Int("size", 10, "масштаб сгенерированных входов")
+	seed := fs.Int64("seed", 0, "сид первого сгенерированного входа (по умолчанию из времени)")
+	gap := fs.Float64("gap", 3, "во сколько раз время может отличаться от Go без предупреждения")
+	only := fs.String("langs", "go,rust,dart", "языки через запятую; первый — эталон")
+	verbose := fs.Bool("v", false, "печатать вход и выводы при расхождении")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+
+	m, err := findModule()
+	if err != nil {
+		return err
+	}
+	problems := fs.Args()
+	if len(problems) == 0 {
+		if problems, err = m.allProblems(); err != nil {
+			return err
+		}
+	}
+	selected, err := selectLangs(*only)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.MkdirTemp("", "coderun-diff")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	d := &differ{
+		j:     &judge{m: m, tmp: tmp, w: os.Stdout},
+		langs: selected, n: *n, size: *size, seed: *seed, gap: *gap,
+		verbose: *verbose, w: os.Stdout,
+	}
+	fmt.Fprintf(d.w, "сид: %d\n", *seed)
+	total := 0
+	for _, p := range problems {
+		dir, err := m.problemDir(p)
+		if err != nil {
+			return err
+		}
+		bad, err := d.diffProblem(dir)
+		if err != nil {
+			return err
+		}
+		total += bad
+	}
+	if total > 0 {
+		return fmt.Errorf("расхождений: %d", total)
+	}
+	fmt.Fprintln(d.w, "\nрасхождений нет")
+	return nil
+}
+
+// selectLangs оставляет языки из списка через запятую в заданном порядке
+func selectLangs(list string) ([]lang, error) {
+	var out []lang
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		i := -1
+		for k, l := range langs {
+			if l.name == name {
+				i = k
+			}
+		}
+		if i < 0 {
+			return nil, fmt.Errorf("неизвестный язык %q", name)
+		}
+		out = append(out, langs[i])
+	}
+	if len(out) < 2 {
+		return nil, fmt.Errorf("для сравнения нужно хотя бы два языка")
+	}
+	return out, nil
+}
+
+// differ собирает реализации задачи и сравнивает их выводы
+type differ struct {
+	j       *judge // сборка Go и ограничения из Q.md
+	langs   []lang
+	n, size int
+	seed    int64
+	gap     float64
+	verbose bool
+	w       io.Writer
+}
+
+// built — собранная реализация
+type built struct {
+	lang
+	bin     string
+	elapsed time.Duration // суммарное время на всех входах
+}
+
+// diffProblem сравнивает реализации одной задачи и возвращает число расхождений
+func (d *differ) diffProblem(dir string) (int, error) {
+	name := filepath.Base(dir)
+	st, err := readStatement(dir)
+	if err != nil {
+		return 0, err
+	}
+	tl, ml := d.j.limitsFor(st)
+
+	var impls []*built
+	var skipped []string
+	bad := 0
+	for _, l := range d.langs {
+		if _, err := os.Stat(filepath.Join(dir, l.src)); err != nil {
+			skipped = append(skipped, l.name+": нет "+l.src)
+			continue
+		}
+		if _, err := exec.LookPath(l.compiler); err != nil {
+			skipped = append(skipped, l.name+": нет "+l.compiler+" в PATH")
+			continue
+		}
+		bin, log, err := d.build(l, dir)
+		if err != nil {
+			return 0, err
+		}
+		if bin == "" {
+			fmt.Fprintf(d.w, "%s: %s не компилируется\n%s", name, l.name, indent(log))
+			bad++
+			continue
+		}
+		impls = append(impls, &built{lang: l, bin: bin})
+	}
+
+	header := name + ":"
+	for _, b := range impls {
+		header += " " + b.name
+	}
+	if len(skipped) > 0 {
+		header += " (" + strings.Join(skipped, "; ") + ")"
+	}
+	fmt.Fprintln(d.w, header)
+	if len(impls) < 2 {
+		fmt.Fprintln(d.w, "  сравнивать не с чем")
+		return bad, nil
+	}
+
+	inputs, err := d.inputs(dir, st)
+	if err != nil {
+		return 0, err
+	}
+	c, hasChecker := checker.For(name)
+	for _, in := range inputs {
+		results := make([]runResult, len(impls))
+		for i, b := range impls {
+			results[i] = runSolution(b.bin, in.input, tl, ml)
+			b.elapsed += results[i].elapsed
+		}
+		ref := results[0]
+		for i := range impls {
+			res := results[i]
+			var msg string
+			switch {
+			case res.verdict != verdictAC:
+				msg = fmt.Sprintf("%s: %s %s", impls[i].name, res.verdict, res.message)
+			case i == 0 || ref.verdict != verdictAC:
+				continue
+			default:
+				v, m := compareOutput(example{output: ref.output}, res.output)
+				if v == verdictAC {
+					continue
+				}
+				// Разные ответы допустимы, если чекер принимает оба
+				if hasChecker && c.Check(in.input, res.output, ref.output).Verdict == checker.OK {
+					if d.verbose {
+						fmt.Fprintf(d.w, "  %s: %s: другой верный ответ\n", in.name, impls[i].name)
+					}
+					continue
+				}
+				msg = fmt.Sprintf("%s: %s", impls[i].name, m)
+			}
+			bad++
+			fmt.Fprintf(d.w, "  %s: %s\n", in.name, msg)
+			if in.repro != "" {
+				fmt.Fprintf(d.w, "    повторить: %s\n", in.repro)
+			}
+			if d.verbose {
+				fmt.Fprintf(d.w, "    ввод:\n%s    %s:\n%s    %s:\n%s", indent(in.input),
+					impls[0].name, indent(ref.output), impls[i].name, indent(res.output))
+			}
+		}
+	}
+	d.reportTime(impls)
+	return bad, nil
+}
+
+// reportTime печатает суммарное время и отмечает отличие от эталона больше чем в gap раз
+func (d *differ) reportTime(impls []*built) {
+	ref := impls[0].elapsed
+	line := "  время:"
+	warn := false
+	for i, b := range impls {
+		line += fmt.Sprintf(" %s %v", b.name, b.elapsed.Round(time.Millisecond))
+		if i == 0 || ref <= 0 {
+			continue
+		}
+		ratio := float64(b.elapsed) / float64(ref)
+		line += fmt.Sprintf(" (×%.2f)", ratio)
+		if max(b.elapsed, ref) >= minGapTime && (ratio > d.gap || ratio < 1/d.gap) {
+			warn = true
+		}
+	}
+	if warn {
+		line += fmt.Sprintf("  ⚠️ разница больше чем в %.1f раза", d.gap)
+	}
+	fmt.Fprintln(d.w, line)
+}
+
+// diffInput — вход, на котором сравниваются реализации
+type diffInput struct {
+	name  string
+	input string
+	repro string // команда для повторения сгенерированного входа
+}
+
+// inputs собирает примеры из Q.md, NN/input.txt, сохранённые стресс-тестами
+// входы из NN/testdata/stress и сгенерированные входы
+func (d *differ) inputs(dir string, st statement) ([]diffInput, error) {
+	var out []diffInput
+	for _, ex := range st.examples {
+		out = append(out, diffInput{name: ex.name, input: ex.input})
+	}
+
+	files := []string{filepath.Join(dir, "input.txt")}
+	saved, err := filepath.Glob(filepath.Join(dir, "testdata", "stress", "*", "*.txt"))
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range append(files, saved...) {
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		out = append(out, diffInput{name: rel, input: string(data)})
+	}
+
+	name := filepath.Base(dir)
+	g, ok := gen.For(name)
+	if !ok {
+		return out, nil
+	}
+	for i := range d.n {
+		seed := d.seed + int64(i)
+		out = append(out, diffInput{
+			name:  fmt.Sprintf("вход %d", i+1),
+			input: g(rand.New(rand.NewSource(seed)), d.size),
+			repro: fmt.Sprintf("go run ./cmd/coderun diff -seed %d -n 1 -size %d -v %s", seed, d.size, name),
+		})
+	}
+	return out, nil
+}
+
+// build собирает реализацию; при ошибке компиляции возвращает пустой путь и лог.
+// Go собирается через judge.build, остальные языки кэшируются по хэшу исходника.
+func (d *differ) build(l lang, dir string) (string, string, error) {
+	if l.name == "go" {
+		return d.j.build(dir)
+	}
+	src := filepath.Join(dir, l.src)
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return "", "", err
+	}
+	sum := sha256.Sum256(append([]byte(strings.Join(l.args("", ""), " ")+"\n"), data...))
+	cache := filepath.Join(d.cacheDir(), l.name+"-"+hex.EncodeToString(sum[:12]))
+	if _, err := os.Stat(cache); err == nil {
+		return cache, "", nil
+	}
+
+	bin := filepath.Join(d.j.tmp, l.name+"-"+filepath.Base(dir))
+	out, err := exec.Command(l.compiler, l.args(src, bin)...).CombinedOutput()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", string(out), nil
+		}
+		return "", "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(cache), 0o755); err != nil {
+		return bin, "", nil
+	}
+	if err := os.Rename(bin, cache); err != nil {
+		return bin, "", nil
+	}
+	return cache, "", nil
+}
+
+// cacheDir — каталог собранных реализаций; без пользовательского кэша — временный
+func (d *differ) cacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return filepath.Join(d.j.tmp, "cache")
+	}
+	return filepath.Join(dir, "coderun")
+}
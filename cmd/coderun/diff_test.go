@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"os/exec"
+	"testing"
+)
+
+// TestDiff — расхождение Rust-версии с Go становится падением теста
+func TestDiff(t *testing.T) {
+	if testing.Short() {
+		t.Skip("компиляция решений пропускается в -short")
+	}
+	if _, err := exec.LookPath("rustc"); err != nil {
+		t.Skip("rustc не найден")
+	}
+	m, err := findModule()
+	if err != nil {
+		t.Fatal(err)
+	}
+	problems, err := m.allProblems()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	d := &differ{
+		j:     &judge{m: m, tmp: t.TempDir(), w: &out},
+		langs: langs, n: 3, size: 8, seed: 1, gap: 3, w: &out,
+	}
+	for _, p := range problems {
+		dir, err := m.problemDir(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out.Reset()
+		bad, err := d.diffProblem(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bad > 0 {
+			t.Errorf("%s: расхождений: %d\n%s", p, bad, out.String())
+		}
+	}
+}
+
+func TestSelectLangs(t *testing.T) {
+	got, err := selectLangs("rust, go")
+	if err != nil || len(got) != 2 || got[0].name != "rust" || got[1].name != "go" {
+		t.Errorf("selectLangs(rust, go) = %v, %v", got, err)
+	}
+	for _, list := range []string{"go", "go,cobol"} {
+		if _, err := selectLangs(list); err == nil {
+			t.Errorf("selectLangs(%q): ожидалась ошибка", list)
+		}
+	}
+}
@@ -0,0 +1,307 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"yandex-2025-winter/lib/checker"
+	"yandex-2025-winter/lib/limits"
+)
+
+// Ограничения по умолчанию, если в Q.md их нет
+const (
+	defaultTimeLimit = time.Second
+	defaultMemoryMB  = 256
+)
+
+// verdict — вердикт судьи по одному тесту
+type verdict string
+
+const (
+	verdictAC  verdict = "AC"
+	verdictWA  verdict = "WA"
+	verdictTLE verdict = "TLE"
+	verdictMLE verdict = "MLE"
+	verdictRE  verdict = "RE"
+	verdictCE  verdict = "CE"
+	verdictPE  verdict = "PE"
+	// verdictFail — чекер не смог проверить ответ (ошибка в условии или ответе жюри)
+	verdictFail verdict = "FAIL"
+)
+
+// runResult — итог запуска решения на одном тесте
+type runResult struct {
+	verdict verdict
+	elapsed time.Duration
+	memory  uint64 // пиковый RSS в байтах
+	output  string
+	message string
+}
+
+// runJudge прогоняет решения задач на примерах из их Q.md
+func runJudge(args []string) error {
+	fs := flag.NewFlagSet("judge", flag.ContinueOnError)
+	timeLimit := fs.Duration("t", 0, "ограничение времени (по умолчанию из Q.md)")
+	memoryMB := fs.Int("m", 0, "ограничение памяти в МБ (по умолчанию из Q.md)")
+	verbose := fs.Bool("v", false, "печатать ввод и вывод для непройденных примеров")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	m, err := findModule()
+	if err != nil {
+		return err
+	}
+	problems := fs.Args()
+	if len(problems) == 0 {
+		if problems, err = m.allProblems(); err != nil {
+			return err
+		}
+	}
+
+	tmp, err := os.MkdirTemp("", "coderun-judge")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	j := &judge{m: m, tmp: tmp, timeLimit: *timeLimit, memoryMB: *memoryMB, verbose: *verbose, w: os.Stdout}
+	passed, total := 0, 0
+	for _, p := range problems {
+		dir, err := m.problemDir(p)
+		if err != nil {
+			return err
+		}
+		ok, n, err := j.judgeProblem(dir)
+		if err != nil {
+			return err
+		}
+		passed += ok
+		total += n
+	}
+
+	fmt.Fprintf(os.Stdout, "\nИтого: %d/%d AC\n", passed, total)
+	if passed != total {
+		return fmt.Errorf("не пройдено %d из %d", total-passed, total)
+	}
+	return nil
+}
+
+// judge собирает и запускает решения
+type judge struct {
+	m         *module
+	tmp       string
+	timeLimit time.Duration // 0 — брать из Q.md
+	memoryMB  int           // 0 — брать из Q.md
+	verbose   bool
+	w         io.Writer
+}
+
+// judgeProblem прогоняет одну задачу и возвращает число пройденных и всех примеров
+func (j *judge) judgeProblem(dir string) (int, int, error) {
+	name := filepath.Base(dir)
+	st, err := readStatement(dir)
+	if err != nil {
+		return 0, 0, err
+	}
+	tl, ml := j.limitsFor(st)
+	fmt.Fprintf(j.w, "%s: %v, %d МБ\n", name, tl, ml)
+	if len(st.examples) == 0 {
+		fmt.Fprintln(j.w, "  в Q.md нет примеров")
+		return 0, 0, nil
+	}
+
+	check := compareOutput
+	if c, ok := checker.For(name); ok {
+		check = checkerFunc(c)
+	}
+
+	bin, buildLog, err := j.build(dir)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	passed := 0
+	for _, ex := range st.examples {
+		var res runResult
+		if bin == "" {
+			res = runResult{verdict: verdictCE, message: buildLog}
+		} else {
+			res = runSolution(bin, ex.input, tl, ml)
+			if res.verdict == verdictAC {
+				res.verdict, res.message = check(ex, res.output)
+			}
+		}
+		if res.verdict == verdictAC {
+			passed++
+		}
+		j.report(ex, res)
+	}
+	return passed, len(st.examples), nil
+}
+
+// limitsFor возвращает ограничения: флаги важнее условия, условие важнее значений по умолчанию
+func (j *judge) limitsFor(st statement) (time.Duration, int) {
+	tl, ml := st.timeLimit, st.memoryMB
+	if tl == 0 {
+		tl = defaultTimeLimit
+	}
+	if ml == 0 {
+		ml = defaultMemoryMB
+	}
+	if j.timeLimit != 0 {
+		tl = j.timeLimit
+	}
+	if j.memoryMB != 0 {
+		ml = j.memoryMB
+	}
+	return tl, ml
+}
+
+// build компилирует NN/main.go; при ошибке компиляции возвращает пустой путь и лог
+func (j *judge) build(dir string) (string, string, error) {
+	bin := filepath.Join(j.tmp, filepath.Base(dir))
+	rel, err := filepath.Rel(j.m.root, dir)
+	if err != nil {
+		return "", "", err
+	}
+	cmd := exec.Command("go", "build", "-o", bin, "./"+filepath.ToSlash(rel))
+	cmd.Dir = j.m.root
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", string(out), nil
+		}
+		return "", "", err
+	}
+	return bin, "", nil
+}
+
+func (j *judge) report(ex example, res runResult) {
+	fmt.Fprintf(j.w, "  %-12s %-4s %8v %8.2f МБ", ex.name, res.verdict,
+		res.elapsed.Round(time.Millisecond), float64(res.memory)/(1024*1024))
+	if res.message != "" && res.verdict != verdictCE {
+		fmt.Fprintf(j.w, "  %s", res.message)
+	}
+	fmt.Fprintln(j.w)
+	if res.verdict == verdictAC || !j.verbose {
+		return
+	}
+	if res.verdict == verdictCE {
+		fmt.Fprint(j.w, indent(res.message))
+		return
+	}
+	fmt.Fprintf(j.w, "    ввод:\n%s    ожидалось:\n%s    получено:\n%s",
+		indent(ex.input), indent(ex.output), indent(res.output))
+}
+
+// runSolution запускает бинарник на вводе и определяет вердикт по
+// времени, памяти и коду возврата; AC означает, что вывод ещё нужно проверить
+func runSolution(bin, input string, tl time.Duration, memoryMB int) runResult {
+	// Запас по времени, чтобы отличить TLE от зависания и всё же дождаться процесса
+	ctx, cancel := context.WithTimeout(context.Background(), 2*tl+time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, bin)
+	cmd.Stdin = strings.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.Env = envWithout(os.Environ(), limits.EnvVar)
+
+	start := time.Now()
+	err := cmd.Run()
+	res := runResult{elapsed: time.Since(start), output: stdout.String()}
+	if cmd.ProcessState != nil {
+		res.memory = maxRSS(cmd.ProcessState)
+	}
+
+	switch {
+	case ctx.Err() != nil || res.elapsed > tl:
+		res.verdict = verdictTLE
+	case err != nil:
+		res.verdict = verdictRE
+		res.message = firstLine(stderr.String())
+		if res.message == "" {
+			res.message = err.Error()
+		}
+	case res.memory > uint64(memoryMB)*1024*1024:
+		res.verdict = verdictMLE
+	default:
+		res.verdict = verdictAC
+	}
+	return res
+}
+
+// compareOutput сравнивает вывод по токенам, как Coderun: пробелы и переводы строк не важны
+func compareOutput(ex example, got string) (verdict, string) {
+	want, have := strings.Fields(ex.output), strings.Fields(got)
+	for i := 0; i < len(want) && i < len(have); i++ {
+		if want[i] != have[i] {
+			return verdictWA, fmt.Sprintf("токен %d: ожидалось %q, получено %q", i+1, want[i], have[i])
+		}
+	}
+	if len(want) != len(have) {
+		return verdictWA, fmt.Sprintf("ожидалось токенов: %d, получено: %d", len(want), len(have))
+	}
+	return verdictAC, ""
+}
+
+// checkerFunc проверяет вывод чекером задачи; вывод из условия передаётся как ответ жюри
+func checkerFunc(c checker.Checker) func(example, string) (verdict, string) {
+	return func(ex example, got string) (verdict, string) {
+		res := c.Check(ex.input, got, ex.output)
+		return checkerVerdict(res.Verdict), res.Message
+	}
+}
+
+func checkerVerdict(v checker.Verdict) verdict {
+	switch v {
+	case checker.OK:
+		return verdictAC
+	case checker.WA:
+		return verdictWA
+	case checker.PE:
+		return verdictPE
+	default:
+		return verdictFail
+	}
+}
+
+func envWithout(env []string, name string) []string {
+	out := make([]string, 0, len(env))
+	for _, kv := range env {
+		if !strings.HasPrefix(kv, name+"=") {
+			out = append(out, kv)
+		}
+	}
+	return out
+}
+
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
+
+func indent(s string) string {
+	var sb strings.Builder
+	for _, line := range strings.Split(strings.TrimRight(s, "\n"), "\n") {
+		sb.WriteString("      ")
+		sb.WriteString(line)
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
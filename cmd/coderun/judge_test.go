@@ -0,0 +1,128 @@
+package main
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// buildProgram компилирует программу из исходного текста во временный каталог
+func buildProgram(t *testing.T, src string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module prog\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	bin := filepath.Join(dir, "prog")
+	cmd := exec.Command("go", "build", "-o", bin, ".")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("go build: %v\n%s", err, out)
+	}
+	return bin
+}
+
+func TestRunSolution(t *testing.T) {
+	if testing.Short() {
+		t.Skip("компиляция программ пропускается в -short")
+	}
+
+	tests := []struct {
+		name     string
+		src      string
+		tl       time.Duration
+		memoryMB int
+		want     verdict
+	}{
+		{
+			name: "эхо",
+			src:  "package main\nimport (\"io\"; \"os\")\nfunc main() { io.Copy(os.Stdout, os.Stdin) }\n",
+			tl:   time.Second, memoryMB: 256,
+			want: verdictAC,
+		},
+		{
+			name: "паника",
+			src:  "package main\nfunc main() { panic(\"boom\") }\n",
+			tl:   time.Second, memoryMB: 256,
+			want: verdictRE,
+		},
+		{
+			name: "бесконечный цикл",
+			src:  "package main\nfunc main() { for {} }\n",
+			tl:   100 * time.Millisecond, memoryMB: 256,
+			want: verdictTLE,
+		},
+		{
+			name: "много памяти",
+			src:  "package main\nvar a [64 << 20]byte\nfunc main() { for i := range a { a[i] = 1 } }\n",
+			tl:   5 * time.Second, memoryMB: 16,
+			want: verdictMLE,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bin := buildProgram(t, tt.src)
+			res := runSolution(bin, "1 2\n", tt.tl, tt.memoryMB)
+			if res.verdict != tt.want {
+				t.Errorf("вердикт %s, ожидался %s (%+v)", res.verdict, tt.want, res)
+			}
+		})
+	}
+}
+
+func TestCompareOutput(t *testing.T) {
+	tests := []struct {
+		expected, got string
+		want          verdict
+	}{
+		{"3 3\n", "3 3\n", verdictAC},
+		{"3 3\n", "3\n3", verdictAC},
+		{"1\n2\n", "  1   2  \n\n", verdictAC},
+		{"1\n2\n", "1\n3\n", verdictWA},
+		{"1\n2\n", "1\n", verdictWA},
+		{"1\n", "1\n2\n", verdictWA},
+	}
+	for _, tt := range tests {
+		if got, msg := compareOutput(example{output: tt.expected}, tt.got); got != tt.want {
+			t.Errorf("compareOutput(%q, %q) = %s (%s), ожидалось %s", tt.expected, tt.got, got, msg, tt.want)
+		}
+	}
+}
+
+func TestJudgeProblem(t *testing.T) {
+	if testing.Short() {
+		t.Skip("компиляция решений пропускается в -short")
+	}
+	m, err := findModule()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 13/ выводит не тот ответ, что в условии, и проходит только через чекер
+	for _, tt := range []struct {
+		problem string
+		total   int
+	}{{"01", 3}, {"13", 1}} {
+		t.Run(tt.problem, func(t *testing.T) {
+			dir, err := m.problemDir(tt.problem)
+			if err != nil {
+				t.Fatal(err)
+			}
+			j := &judge{m: m, tmp: t.TempDir(), w: io.Discard}
+			passed, total, err := j.judgeProblem(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if total != tt.total || passed != total {
+				t.Errorf("пройдено %d из %d, ожидалось %d из %d", passed, total, tt.total, tt.total)
+			}
+		})
+	}
+}
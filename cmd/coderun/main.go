@@ -0,0 +1,55 @@
+// Команда coderun — локальные инструменты для задач Coderun из этого репозитория.
+//
+// Использование:
+//
+//	go run ./cmd/coderun bundle NN > submit.go
+//	go run ./cmd/coderun judge [-t 2s] [-m 256] [-v] [NN ...]
+//	go run ./NN < input.txt | go run ./cmd/coderun check NN input.txt - [answer.txt]
+//	go run ./cmd/coderun diff [-n 20] [-size 10] [-seed S] [-langs go,rust,dart] [-v] [NN ...]
+//	go run ./cmd/coderun new [-t 1s] [-m 256] [-in "n; a[n]"] [-out int] [-multi] [-n 100000] [-f] NN
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// command — подкоманда coderun
+type command struct {
+	name  string
+	usage string
+	run   func(args []string) error
+}
+
+var commands = []command{
+	{"bundle", "bundle NN — собрать NN/main.go и локальные пакеты в один файл", runBundle},
+	{"judge", "judge [-t время] [-m МБ] [-v] [NN ...] — прогнать решения на примерах из Q.md", runJudge},
+	{"check", "check NN ввод вывод [ответ] — проверить вывод чекером задачи (\"-\" — stdin)", runCheck},
+	{"diff", "diff [-n входов] [-size масштаб] [-seed S] [-langs go,rust,dart] [-v] [NN ...] — сравнить реализации на Go, Rust и Dart", runDiff},
+	{"new", "new [-t время] [-m МБ] [-in формат] [-out тип] [-multi] [-n размер] [-f] NN — создать заготовку задачи", runNew},
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "использование: coderun <команда> [аргументы]")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %s\n", c.usage)
+	}
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	for _, c := range commands {
+		if c.name == os.Args[1] {
+			if err := c.run(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "coderun %s: %v\n", c.name, err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+	usage()
+	os.Exit(2)
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// module описывает корень Go-модуля репозитория
+type module struct {
+	root string // абсолютный путь к каталогу с go.mod
+	path string // путь модуля из директивы module
+}
+
+// findModule ищет go.mod, поднимаясь от текущего каталога
+func findModule() (*module, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		gomod := filepath.Join(dir, "go.mod")
+		if _, err := os.Stat(gomod); err == nil {
+			path, err := readModulePath(gomod)
+			if err != nil {
+				return nil, err
+			}
+			return &module{root: dir, path: path}, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, fmt.Errorf("go.mod не найден")
+		}
+		dir = parent
+	}
+}
+
+func readModulePath(gomod string) (string, error) {
+	f, err := os.Open(gomod)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if rest, ok := strings.CutPrefix(line, "module "); ok {
+			return strings.Trim(strings.TrimSpace(rest), `"`), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("%s: нет директивы module", gomod)
+}
+
+// problemDir возвращает каталог задачи по номеру ("7", "07" или "07/")
+func (m *module) problemDir(arg string) (string, error) {
+	name := strings.TrimSuffix(filepath.Base(arg), "/")
+	if len(name) == 1 {
+		name = "0" + name
+	}
+	dir := filepath.Join(m.root, name)
+	if _, err := os.Stat(filepath.Join(dir, "main.go")); err != nil {
+		return "", fmt.Errorf("задача %s: %w", arg, err)
+	}
+	return dir, nil
+}
+
+// isLocal сообщает, принадлежит ли путь импорта этому модулю
+func (m *module) isLocal(importPath string) bool {
+	return strings.HasPrefix(importPath, m.path+"/")
+}
+
+// dirOf возвращает каталог локального пакета по пути импорта
+func (m *module) dirOf(importPath string) string {
+	return filepath.Join(m.root, filepath.FromSlash(strings.TrimPrefix(importPath, m.path+"/")))
+}
+
+// allProblems возвращает номера всех задач (каталоги NN/ с main.go) по порядку
+func (m *module) allProblems() ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(m.root, "[0-9][0-9]", "main.go"))
+	if err != nil {
+		return nil, err
+	}
+	problems := make([]string, 0, len(matches))
+	for _, path := range matches {
+		problems = append(problems, filepath.Base(filepath.Dir(path)))
+	}
+	return problems, nil
+}
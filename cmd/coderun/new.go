@@ -0,0 +1,785 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// valueType — тип значения во входе или выводе
+type valueType struct {
+	name      string // имя в спецификации
+	goType    string
+	read      string // метод fastio.Reader
+	write     string // вывод значения result через fastio.Writer
+	goZero    string
+	rustType  string
+	rustParse string // разбор токена t
+	rustZero  string
+	random    string // случайное значение для теста; %s — масштаб size
+}
+
+var valueTypes = map[string]valueType{
+	"int": {
+		name: "int", goType: "int", read: "Int", write: "writer.Int(result)\nwriter.WriteByte('\\n')", goZero: "0",
+		rustType: "i64", rustParse: "t.parse().unwrap()", rustZero: "0",
+		random: "1 + rng.Intn(1_000_000_000)",
+	},
+	"int64": {
+		name: "int64", goType: "int64", read: "Int64", write: "writer.Int64(result)\nwriter.WriteByte('\\n')", goZero: "0",
+		rustType: "i64", rustParse: "t.parse().unwrap()", rustZero: "0",
+		random: "1 + rng.Int63n(1_000_000_000_000_000_000)",
+	},
+	"float": {
+		name: "float", goType: "float64", read: "Float64", write: "writer.Float64(result, 10)\nwriter.WriteByte('\\n')", goZero: "0",
+		rustType: "f64", rustParse: "t.parse().unwrap()", rustZero: "0.0",
+		random: "rng.Float64()",
+	},
+	"string": {
+		name: "string", goType: "string", read: "Word", write: "writer.Line(result)", goZero: `""`,
+		rustType: "String", rustParse: "t.to_string()", rustZero: "String::new()",
+		random: "randomWord(rng, %s)",
+	},
+}
+
+// sliceOutputs — типы вывода-срезов: элементы через пробел одной строкой
+var sliceOutputs = map[string]valueType{
+	"[]int":   {name: "[]int", goType: "[]int", write: "writer.Ints(result)", goZero: "nil", rustType: "Vec<i64>", rustZero: "Vec::new()"},
+	"[]int64": {name: "[]int64", goType: "[]int64", write: "writer.Int64s(result)", goZero: "nil", rustType: "Vec<i64>", rustZero: "Vec::new()"},
+}
+
+// reservedNames заняты в сгенерированном коде
+var reservedNames = map[string]bool{
+	"main": true, "solve": true, "reader": true, "writer": true, "result": true, "tests": true,
+	"tt": true, "t": true, "b": true, "rng": true, "i": true, "j": true, "size": true, "name": true,
+	"expected": true, "next": true, "T": true, "input": true, "tokens": true, "res": true,
+}
+
+// field — переменная входа
+type field struct {
+	name  string
+	typ   valueType
+	slice bool
+}
+
+func (f field) goType() string {
+	if f.slice {
+		return "[]" + f.typ.goType
+	}
+	return f.typ.goType
+}
+
+// readItem — скаляр, массив или группа массивов, читаемых поэлементно вперемешку
+type readItem struct {
+	fields []field
+	length string // "" — скаляр; иначе имя скаляра или число
+}
+
+// inputSpec — формат входа одного теста
+type inputSpec struct {
+	items   []readItem
+	lengths map[string]bool // скаляры, задающие длины
+}
+
+func (s inputSpec) fields() []field {
+	var out []field
+	for _, it := range s.items {
+		out = append(out, it.fields...)
+	}
+	return out
+}
+
+// parseInputSpec разбирает формат входа: строки через ";", элементы через пробел.
+// Элемент — скаляр `n`, массив `a[n]` или группа `(l r)[q]` (q строк по два
+// числа); к имени можно добавить тип `:int64`, `:float`, `:string` (по умолчанию int).
+// Длина — ранее прочитанный целый скаляр или число.
+func parseInputSpec(spec string) (inputSpec, error) {
+	s := inputSpec{lengths: make(map[string]bool)}
+	scalars := make(map[string]valueType)
+	seen := make(map[string]bool)
+
+	parseField := func(text string) (field, error) {
+		name, typeName, hasType := strings.Cut(text, ":")
+		if !hasType {
+			typeName = "int"
+		}
+		typ, ok := valueTypes[typeName]
+		if !ok {
+			return field{}, fmt.Errorf("%q: неизвестный тип %q (int, int64, float, string)", text, typeName)
+		}
+		if !token.IsIdentifier(name) || name == "_" {
+			return field{}, fmt.Errorf("%q: имя должно быть идентификатором Go", name)
+		}
+		if reservedNames[name] || seen[name] {
+			return field{}, fmt.Errorf("%q: имя занято", name)
+		}
+		seen[name] = true
+		return field{name: name, typ: typ}, nil
+	}
+	checkLength := func(length string) error {
+		if n, err := strconv.Atoi(length); err == nil && n > 0 {
+			return nil
+		}
+		typ, ok := scalars[length]
+		if !ok || (typ.name != "int" && typ.name != "int64") {
+			return fmt.Errorf("длина %q: нужен ранее прочитанный целый скаляр или число", length)
+		}
+		s.lengths[length] = true
+		return nil
+	}
+
+	for _, line := range strings.Split(spec, ";") {
+		rest := strings.TrimSpace(line)
+		for rest != "" {
+			var item readItem
+			if rest[0] == '(' {
+				end := strings.Index(rest, ")")
+				if end < 0 {
+					return s, fmt.Errorf("%q: нет закрывающей скобки", rest)
+				}
+				for _, text := range strings.Fields(rest[1:end]) {
+					f, err := parseField(text)
+					if err != nil {
+						return s, err
+					}
+					item.fields = append(item.fields, f)
+				}
+				if len(item.fields) == 0 {
+					return s, fmt.Errorf("пустая группа")
+				}
+				rest = rest[end+1:]
+				if !strings.HasPrefix(rest, "[") {
+					return s, fmt.Errorf("после группы нужна длина [n]")
+				}
+			} else {
+				end := strings.IndexAny(rest, " \t[")
+				if end < 0 {
+					end = len(rest)
+				}
+				text := rest[:end]
+				rest = rest[end:]
+				if strings.HasPrefix(rest, "[") {
+					// a[n]:int64 — тип после длины
+					close := strings.Index(rest, "]")
+					if close >= 0 {
+						after := rest[close+1:]
+						typEnd := strings.IndexAny(after, " \t")
+						if typEnd < 0 {
+							typEnd = len(after)
+						}
+						if strings.HasPrefix(after, ":") {
+							text += after[:typEnd]
+							rest = rest[:close+1] + after[typEnd:]
+						}
+					}
+				}
+				f, err := parseField(text)
+				if err != nil {
+					return s, err
+				}
+				item.fields = []field{f}
+			}
+			if strings.HasPrefix(rest, "[") {
+				end := strings.Index(rest, "]")
+				if end < 0 {
+					return s, fmt.Errorf("%q: нет закрывающей скобки", rest)
+				}
+				item.length = strings.TrimSpace(rest[1:end])
+				if err := checkLength(item.length); err != nil {
+					return s, err
+				}
+				for i := range item.fields {
+					item.fields[i].slice = true
+				}
+				rest = rest[end+1:]
+			} else if len(item.fields) == 1 {
+				scalars[item.fields[0].name] = item.fields[0].typ
+			}
+			if rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+				return s, fmt.Errorf("%q: ожидался пробел между элементами", rest)
+			}
+			rest = strings.TrimSpace(rest)
+			s.items = append(s.items, item)
+		}
+	}
+	if len(s.items) == 0 {
+		return s, errors.New("пустой формат входа")
+	}
+	return s, nil
+}
+
+// scaffold — параметры заготовки задачи
+type scaffold struct {
+	module   string // путь модуля для импорта lib/
+	in       inputSpec
+	out      valueType
+	multi    bool // в первой строке число тестов T
+	maxTime  time.Duration
+	memoryMB int
+	maxN     int
+}
+
+// runNew создаёт заготовку задачи: main.go, main_test.go и main.rs
+func runNew(args []string) error {
+	fs := flag.NewFlagSet("new", flag.ContinueOnError)
+	maxTime := fs.Duration("t", time.Second, "ограничение времени")
+	memoryMB := fs.Int("m", 256, "ограничение памяти в МБ")
+	in := fs.String("in", "n; a[n]", "формат входа: строки через \";\", скаляры `n`, массивы `a[n]`, группы `(l r)[q]`, типы `:int64`, `:float`, `:string`")
+	out := fs.String("out", "int", "тип ответа: int, int64, float, string, []int, []int64")
+	multi := fs.Bool("multi", false, "в первой строке число тестов T, дальше T входов по формату -in")
+	maxN := fs.Int("n", 100000, "наибольший размер входа для тестов времени и памяти")
+	force := fs.Bool("f", false, "перезаписать существующие файлы")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("ожидается номер задачи")
+	}
+	num, err := strconv.Atoi(strings.TrimSuffix(fs.Arg(0), "/"))
+	if err != nil || num < 1 || num > 99 {
+		return fmt.Errorf("номер задачи %q: ожидается число от 1 до 99", fs.Arg(0))
+	}
+
+	m, err := findModule()
+	if err != nil {
+		return err
+	}
+	sc := scaffold{module: m.path, multi: *multi, maxTime: *maxTime, memoryMB: *memoryMB, maxN: *maxN}
+	if sc.in, err = parseInputSpec(*in); err != nil {
+		return fmt.Errorf("-in: %w", err)
+	}
+	var ok bool
+	if sc.out, ok = valueTypes[*out]; !ok {
+		if sc.out, ok = sliceOutputs[*out]; !ok {
+			return fmt.Errorf("-out: неизвестный тип %q", *out)
+		}
+	}
+
+	files, err := sc.files()
+	if err != nil {
+		return err
+	}
+	dir := filepath.Join(m.root, fmt.Sprintf("%02d", num))
+	written, err := writeScaffold(dir, files, *force)
+	for _, path := range written {
+		fmt.Printf("создан %s\n", filepath.Join(filepath.Base(dir), filepath.Base(path)))
+	}
+	return err
+}
+
+// writeScaffold записывает файлы в dir; без force отказывается перезаписывать
+// существующие. Возвращает пути записанных файлов
+func writeScaffold(dir string, files []scaffoldFile, force bool) ([]string, error) {
+	if !force {
+		for _, f := range files {
+			if _, err := os.Stat(filepath.Join(dir, f.name)); err == nil {
+				return nil, fmt.Errorf("%s уже существует (-f, чтобы перезаписать)", filepath.Join(filepath.Base(dir), f.name))
+			}
+		}
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	var written []string
+	for _, f := range files {
+		path := filepath.Join(dir, f.name)
+		if err := os.WriteFile(path, f.data, 0o644); err != nil {
+			return written, err
+		}
+		written = append(written, path)
+	}
+	return written, nil
+}
+
+// scaffoldFile — сгенерированный файл
+type scaffoldFile struct {
+	name string
+	data []byte
+}
+
+// files генерирует файлы заготовки
+func (sc scaffold) files() ([]scaffoldFile, error) {
+	mainGo, err := format.Source([]byte(sc.mainGo()))
+	if err != nil {
+		return nil, fmt.Errorf("main.go: %w", err)
+	}
+	testGo, err := format.Source([]byte(sc.testGo()))
+	if err != nil {
+		return nil, fmt.Errorf("main_test.go: %w", err)
+	}
+	return []scaffoldFile{
+		{"main.go", mainGo},
+		{"main_test.go", testGo},
+		{"main.rs", []byte(sc.mainRs())},
+	}, nil
+}
+
+// args возвращает аргументы solve через запятую с префиксом, например "tt."
+func (sc scaffold) args(prefix string) string {
+	var names []string
+	for _, f := range sc.in.fields() {
+		names = append(names, prefix+f.name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// goLength возвращает длину массива как выражение int
+func goLength(item readItem, fields []field) string {
+	for _, f := range fields {
+		if f.name == item.length && f.typ.goType != "int" {
+			return "int(" + item.length + ")"
+		}
+	}
+	return item.length
+}
+
+// goRead возвращает код чтения входа одного теста
+func (sc scaffold) goRead(indent string) string {
+	var b strings.Builder
+	fields := sc.in.fields()
+	for _, item := range sc.in.items {
+		if item.length == "" {
+			f := item.fields[0]
+			fmt.Fprintf(&b, "%s%s := reader.%s()\n", indent, f.name, f.typ.read)
+			continue
+		}
+		length := goLength(item, fields)
+		for _, f := range item.fields {
+			fmt.Fprintf(&b, "%s%s := make(%s, %s)\n", indent, f.name, f.goType(), length)
+		}
+		fmt.Fprintf(&b, "%sfor i := range %s {\n", indent, item.fields[0].name)
+		for _, f := range item.fields {
+			fmt.Fprintf(&b, "%s\t%s[i] = reader.%s()\n", indent, f.name, f.typ.read)
+		}
+		fmt.Fprintf(&b, "%s}\n", indent)
+	}
+	return b.String()
+}
+
+// goWrite возвращает код вывода result
+func (sc scaffold) goWrite(indent string) string {
+	return indent + strings.ReplaceAll(sc.out.write, "\n", "\n"+indent) + "\n"
+}
+
+func (sc scaffold) mainGo() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `package main
+
+import (
+	"os"
+	"time"
+
+	"%[1]s/lib/fastio"
+	"%[1]s/lib/limits"
+)
+
+func main() {
+	reader := fastio.NewReader(os.Stdin)
+	writer := fastio.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+`, sc.module)
+	limit := fmt.Sprintf("limits.Run(%s, %d, func() {\n", goDuration(sc.maxTime), sc.memoryMB)
+	if sc.multi {
+		b.WriteString("\t// Читаем количество тестов\n\tT := reader.Int()\n\n")
+		b.WriteString("\t" + limit)
+		b.WriteString("\t\tfor range T {\n")
+		b.WriteString("\t\t\t// Чтение входных данных\n")
+		b.WriteString(sc.goRead("\t\t\t"))
+		fmt.Fprintf(&b, "\n\t\t\tresult := solve(%s)\n", sc.args(""))
+		b.WriteString(sc.goWrite("\t\t\t"))
+		b.WriteString("\t\t}\n\t})\n}\n")
+	} else {
+		b.WriteString("\t// Чтение входных данных\n")
+		b.WriteString(sc.goRead("\t"))
+		fmt.Fprintf(&b, "\n\tvar result %s\n", sc.out.goType)
+		b.WriteString("\t" + limit)
+		fmt.Fprintf(&b, "\t\tresult = solve(%s)\n\t})\n\n", sc.args(""))
+		b.WriteString("\t// Вывод результата\n")
+		b.WriteString(sc.goWrite("\t"))
+		b.WriteString("}\n")
+	}
+
+	var params []string
+	for _, f := range sc.in.fields() {
+		params = append(params, f.name+" "+f.goType())
+	}
+	fmt.Fprintf(&b, `
+// solve — TODO: решение задачи
+func solve(%s) %s {
+	return %s
+}
+`, strings.Join(params, ", "), sc.out.goType, sc.out.goZero)
+	return b.String()
+}
+
+// goDuration записывает ограничение времени выражением Go: 2*time.Second
+func goDuration(d time.Duration) string {
+	if d%time.Second == 0 {
+		return fmt.Sprintf("%d*time.Second", d/time.Second)
+	}
+	return fmt.Sprintf("%d*time.Millisecond", d/time.Millisecond)
+}
+
+func (sc scaffold) testGo() string {
+	fields := sc.in.fields()
+	needWord := false
+	for _, f := range fields {
+		needWord = needWord || f.typ.name == "string"
+	}
+	compare := "result != tt.expected"
+	imports := []string{`"fmt"`, `"math/rand"`}
+	if sc.out.goZero == "nil" {
+		compare = "!slices.Equal(result, tt.expected)"
+		imports = append(imports, `"slices"`)
+	}
+	if needWord {
+		imports = append(imports, `"strings"`)
+	}
+	imports = append(imports, `"testing"`, `"time"`)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `package main
+
+import (
+	%s
+
+	"%s/lib/limits"
+)
+
+// Ограничения из условия
+const (
+	maxTime     = %s
+	maxMemoryMB = %d
+	maxN        = %d // TODO: наибольший размер входа из условия
+)
+
+// Тест на примеры из условия задачи
+func TestSolve(t *testing.T) {
+	tests := []struct {
+		name     string
+`, strings.Join(imports, "\n\t"), sc.module, goDuration(sc.maxTime), sc.memoryMB, sc.maxN)
+	for _, f := range fields {
+		fmt.Fprintf(&b, "\t\t%s %s\n", f.name, f.goType())
+	}
+	var verbs []string
+	for range fields {
+		verbs = append(verbs, "%v")
+	}
+	fmt.Fprintf(&b, `		expected %s
+	}{
+		// TODO: примеры из условия
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := solve(%s)
+			if %s {
+				t.Errorf("solve(%s) = %%v, ожидалось %%v", %s, result, tt.expected)
+			}
+		})
+	}
+}
+`, sc.out.goType, sc.args("tt."), compare, strings.Join(verbs, ", "), sc.args("tt."))
+
+	// Случайный вход масштаба size
+	var results []string
+	for _, f := range fields {
+		results = append(results, f.goType())
+	}
+	fmt.Fprintf(&b, `
+// randomInput строит случайный вход масштаба size: массивы длины size.
+// TODO: значения в пределах ограничений условия
+func randomInput(rng *rand.Rand, size int) (%s) {
+`, strings.Join(results, ", "))
+	for _, item := range sc.in.items {
+		if item.length == "" {
+			f := item.fields[0]
+			switch {
+			case sc.in.lengths[f.name] && f.typ.name == "int":
+				fmt.Fprintf(&b, "\t%s := size\n", f.name)
+			case sc.in.lengths[f.name]:
+				fmt.Fprintf(&b, "\t%s := int64(size)\n", f.name)
+			case f.typ.name == "int":
+				fmt.Fprintf(&b, "\t%s := 1 + rng.Intn(size)\n", f.name)
+			case f.typ.name == "int64":
+				fmt.Fprintf(&b, "\t%s := 1 + rng.Int63n(int64(size))\n", f.name)
+			default:
+				fmt.Fprintf(&b, "\t%s := %s\n", f.name, strings.ReplaceAll(f.typ.random, "%s", "size"))
+			}
+			continue
+		}
+		length := goLength(item, fields)
+		for _, f := range item.fields {
+			fmt.Fprintf(&b, "\t%s := make(%s, %s)\n", f.name, f.goType(), length)
+		}
+		fmt.Fprintf(&b, "\tfor i := range %s {\n", item.fields[0].name)
+		for _, f := range item.fields {
+			fmt.Fprintf(&b, "\t\t%s[i] = %s\n", f.name, strings.ReplaceAll(f.typ.random, "%s", "10"))
+		}
+		b.WriteString("\t}\n")
+	}
+	fmt.Fprintf(&b, "\treturn %s\n}\n", sc.args(""))
+	if needWord {
+		b.WriteString(`
+// randomWord возвращает случайное слово из строчных латинских букв длины n
+func randomWord(rng *rand.Rand, n int) string {
+	var sb strings.Builder
+	for range n {
+		sb.WriteByte(byte('a' + rng.Intn(26)))
+	}
+	return sb.String()
+}
+`)
+	}
+
+	fmt.Fprintf(&b, `
+// TestSolveTimeLimit проверяет ограничение времени на входе максимального размера
+func TestSolveTimeLimit(t *testing.T) {
+	%[1]s := randomInput(rand.New(rand.NewSource(1)), maxN)
+	res := limits.Measure(maxTime, maxMemoryMB, func() {
+		solve(%[1]s)
+	})
+	if res.Verdict&limits.TimeExceeded != 0 {
+		t.Errorf("solve() выполнилось за %%v, что превышает ограничение %%v", res.Elapsed, maxTime)
+	}
+	t.Logf("solve() выполнилось за %%v", res.Elapsed)
+}
+
+// TestSolveMemoryUsage проверяет ограничение памяти: выделения кучи за время solve
+func TestSolveMemoryUsage(t *testing.T) {
+	%[1]s := randomInput(rand.New(rand.NewSource(1)), maxN)
+	res := limits.MeasureMode(limits.ModeAlloc, maxTime, maxMemoryMB, func() {
+		solve(%[1]s)
+	})
+	if res.Verdict&limits.MemoryExceeded != 0 {
+		t.Errorf("solve() использовало %%.2f МБ памяти, что превышает ограничение %%d МБ", res.MemoryMB(), maxMemoryMB)
+	}
+	t.Logf("solve() использовало %%.2f МБ памяти", res.MemoryMB())
+}
+
+// BenchmarkSolve проверяет производительность решения для различных размеров входных данных
+func BenchmarkSolve(b *testing.B) {
+	for _, size := range []int{max(1, maxN/100), max(1, maxN/10), maxN} {
+		%[1]s := randomInput(rand.New(rand.NewSource(1)), size)
+		b.Run(fmt.Sprintf("size=%%d", size), func(b *testing.B) {
+			for b.Loop() {
+				solve(%[1]s)
+			}
+		})
+	}
+}
+`, sc.args(""))
+	return b.String()
+}
+
+// rustRead возвращает код чтения входа одного теста на Rust
+func (sc scaffold) rustRead(indent string) string {
+	var b strings.Builder
+	for _, item := range sc.in.items {
+		if item.length == "" {
+			f := item.fields[0]
+			fmt.Fprintf(&b, "%slet %s: %s = { let t = next(); %s };\n", indent, f.name, f.typ.rustType, f.typ.rustParse)
+			continue
+		}
+		length := item.length
+		if _, err := strconv.Atoi(length); err != nil {
+			length += " as usize"
+		}
+		for _, f := range item.fields {
+			fmt.Fprintf(&b, "%slet mut %s: Vec<%s> = Vec::with_capacity(%s);\n", indent, f.name, f.typ.rustType, length)
+		}
+		fmt.Fprintf(&b, "%sfor _ in 0..%s {\n", indent, length)
+		for _, f := range item.fields {
+			fmt.Fprintf(&b, "%s    %s.push({ let t = next(); %s });\n", indent, f.name, f.typ.rustParse)
+		}
+		fmt.Fprintf(&b, "%s}\n", indent)
+	}
+	return b.String()
+}
+
+// rustWrite возвращает код вывода result на Rust
+func (sc scaffold) rustWrite(indent string) string {
+	switch {
+	case strings.HasPrefix(sc.out.rustType, "Vec"):
+		return indent + "let line: Vec<String> = result.iter().map(|x| x.to_string()).collect();\n" +
+			indent + "writeln!(writer, \"{}\", line.join(\" \")).unwrap();\n"
+	case sc.out.rustType == "f64":
+		return indent + "writeln!(writer, \"{:.10}\", result).unwrap();\n"
+	default:
+		return indent + "writeln!(writer, \"{}\", result).unwrap();\n"
+	}
+}
+
+func (sc scaffold) mainRs() string {
+	fields := sc.in.fields()
+	var params, callArgs, names []string
+	for _, f := range fields {
+		typ := f.typ.rustType
+		arg := f.name
+		switch {
+		case f.slice:
+			typ = "&[" + typ + "]"
+			arg = "&" + arg
+		case typ == "String":
+			typ = "&str"
+			arg = "&" + arg
+		}
+		params = append(params, f.name+": "+typ)
+		callArgs = append(callArgs, arg)
+		names = append(names, f.name)
+	}
+
+	var b strings.Builder
+	b.WriteString("use std::env;\nuse std::io::{self, BufWriter, Read, Write};\nuse std::time::Instant;\n\n")
+	b.WriteString(rustCheckLimits)
+	b.WriteString(`
+fn main() {
+    let stdin = io::stdin();
+    let mut reader = stdin.lock();
+    let stdout = io::stdout();
+    let mut writer = BufWriter::new(stdout.lock());
+
+    let mut input = String::new();
+    reader.read_to_string(&mut input).unwrap();
+    let mut tokens = input.split_ascii_whitespace();
+    let mut next = || tokens.next().unwrap();
+
+`)
+	maxMs := sc.maxTime.Milliseconds()
+	call := fmt.Sprintf("solve(%s)", strings.Join(callArgs, ", "))
+	if sc.multi {
+		b.WriteString("    // Читаем количество тестов\n    let t: usize = next().parse().unwrap();\n\n")
+		fmt.Fprintf(&b, "    check_limits(%d, %d, || {\n        for _ in 0..t {\n", maxMs, sc.memoryMB)
+		b.WriteString("            // Чтение входных данных\n")
+		b.WriteString(sc.rustRead("            "))
+		fmt.Fprintf(&b, "\n            let result = %s;\n", call)
+		b.WriteString(sc.rustWrite("            "))
+		b.WriteString("        }\n    });\n")
+	} else {
+		b.WriteString("    // Чтение входных данных\n")
+		b.WriteString(sc.rustRead("    "))
+		fmt.Fprintf(&b, "\n    let mut result: %s = %s;\n", sc.out.rustType, sc.out.rustZero)
+		fmt.Fprintf(&b, "    check_limits(%d, %d, || {\n        result = %s;\n    });\n\n", maxMs, sc.memoryMB, call)
+		b.WriteString("    // Вывод результата\n")
+		b.WriteString(sc.rustWrite("    "))
+	}
+	b.WriteString("    writer.flush().unwrap();\n}\n")
+
+	unused := strings.Join(names, ", ")
+	if len(names) > 1 {
+		unused = "(" + unused + ")"
+	}
+	fmt.Fprintf(&b, `
+// solve — TODO: решение задачи, идентичное по логике main.go
+fn solve(%s) -> %s {
+    let _ = %s;
+    %s
+}
+`, strings.Join(params, ", "), sc.out.rustType, unused, sc.out.rustZero)
+	return b.String()
+}
+
+// rustCheckLimits — проверка ограничений из .cursor/rules.md
+const rustCheckLimits = `// get_memory_usage возвращает текущее использование памяти в байтах (приблизительно)
+// Работает на Linux (читает /proc/self/status) и macOS (использует системные вызовы)
+fn get_memory_usage() -> Option<u64> {
+    #[cfg(target_os = "linux")]
+    {
+        use std::fs;
+        if let Ok(content) = fs::read_to_string("/proc/self/status") {
+            for line in content.lines() {
+                if line.starts_with("VmRSS:") {
+                    if let Some(value) = line.split_whitespace().nth(1) {
+                        if let Ok(kb) = value.parse::<u64>() {
+                            return Some(kb * 1024); // Конвертируем KB в байты
+                        }
+                    }
+                }
+            }
+        }
+    }
+
+    #[cfg(target_os = "macos")]
+    {
+        use std::process::Command;
+        let pid = std::process::id().to_string();
+        if let Ok(output) = Command::new("ps")
+            .args(&["-o", "rss=", "-p", &pid])
+            .output()
+        {
+            if let Ok(mem_str) = String::from_utf8(output.stdout) {
+                if let Ok(kb) = mem_str.trim().parse::<u64>() {
+                    return Some(kb * 1024); // Конвертируем KB в байты
+                }
+            }
+        }
+    }
+
+    None
+}
+
+// check_limits проверяет ограничения времени и памяти (работает только если установлена переменная окружения CHECK_LIMITS)
+// Результаты выводятся в stderr, функция ничего не возвращает
+fn check_limits(max_time_ms: u64, max_memory_mb: u64, f: impl FnOnce()) {
+    // Проверяем переменную окружения
+    if env::var("CHECK_LIMITS").is_err() {
+        // Если переменная не установлена, просто выполняем функцию без проверок
+        f();
+        return;
+    }
+
+    // Измеряем память до выполнения
+    let mem_before = get_memory_usage();
+
+    // Измеряем время выполнения
+    let start = Instant::now();
+    f();
+    let elapsed = start.elapsed();
+
+    // Измеряем память после выполнения
+    let mem_after = get_memory_usage();
+
+    // Проверяем ограничения
+    let elapsed_ms = elapsed.as_millis() as u64;
+    let time_ok = elapsed_ms <= max_time_ms;
+
+    // Вычисляем использованную память
+    let memory_ok = if let (Some(before), Some(after)) = (mem_before, mem_after) {
+        let allocated = if after > before { after - before } else { 0 };
+        let max_memory_bytes = max_memory_mb * 1024 * 1024;
+        allocated <= max_memory_bytes
+    } else {
+        true // Если не удалось измерить память, считаем что всё ОК
+    };
+
+    // Логируем результаты
+    if !time_ok || !memory_ok {
+        if elapsed_ms > max_time_ms {
+            eprintln!("⚠️ Превышено время: {} мс (лимит: {} мс)", elapsed_ms, max_time_ms);
+        }
+        if let (Some(before), Some(after)) = (mem_before, mem_after) {
+            let allocated = if after > before { after - before } else { 0 };
+            let memory_mb = allocated as f64 / (1024.0 * 1024.0);
+            if memory_mb > max_memory_mb as f64 {
+                eprintln!("⚠️ Превышена память: {:.2} МБ (лимит: {} МБ)", memory_mb, max_memory_mb);
+            }
+        }
+    } else {
+        if let (Some(before), Some(after)) = (mem_before, mem_after) {
+            let allocated = if after > before { after - before } else { 0 };
+            let memory_mb = allocated as f64 / (1024.0 * 1024.0);
+            eprintln!("✓ Время: {} мс, Память: {:.2} МБ", elapsed_ms, memory_mb);
+        } else {
+            eprintln!("✓ Время: {} мс, Память: не удалось измерить (лимит: {} МБ)", elapsed_ms, max_memory_mb);
+        }
+    }
+}
+`
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/types"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseInputSpec(t *testing.T) {
+	tests := []struct {
+		spec    string
+		fields  string // имя:тип, [] у массивов
+		lengths string
+		wantErr string
+	}{
+		{spec: "n; a[n]", fields: "n:int a:[]int", lengths: "n"},
+		{spec: "n q; a[n]:int64; (l r)[q]", fields: "n:int q:int a:[]int64 l:[]int r:[]int", lengths: "n q"},
+		{spec: "k:int64 s:string; w[3]:float", fields: "k:int64 s:string w:[]float64"},
+		{spec: "n:int64; (x y:string)[n]", fields: "n:int64 x:[]int y:[]string", lengths: "n"},
+		{spec: "", wantErr: "пустой"},
+		{spec: "a[n]", wantErr: "длина"},
+		{spec: "x:float; a[x]", wantErr: "длина"},
+		{spec: "n n", wantErr: "занято"},
+		{spec: "result", wantErr: "занято"},
+		{spec: "func", wantErr: "идентификатором"},
+		{spec: "n:big", wantErr: "неизвестный тип"},
+		{spec: "n; (l r", wantErr: "скобки"},
+		{spec: "n; (l r) x", wantErr: "длина [n]"},
+		{spec: "n; a[n]b", wantErr: "пробел"},
+	}
+	for _, tt := range tests {
+		s, err := parseInputSpec(tt.spec)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("parseInputSpec(%q): ошибка %v, ожидалась с %q", tt.spec, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseInputSpec(%q): %v", tt.spec, err)
+			continue
+		}
+		var fields, lengths []string
+		for _, f := range s.fields() {
+			fields = append(fields, f.name+":"+f.goType())
+			if s.lengths[f.name] {
+				lengths = append(lengths, f.name)
+			}
+		}
+		if got := strings.Join(fields, " "); got != tt.fields {
+			t.Errorf("parseInputSpec(%q): поля %q, ожидалось %q", tt.spec, got, tt.fields)
+		}
+		if got := strings.Join(lengths, " "); got != tt.lengths {
+			t.Errorf("parseInputSpec(%q): длины %q, ожидалось %q", tt.spec, got, tt.lengths)
+		}
+	}
+}
+
+// scaffolds — заготовки для проверки генераторов
+var scaffolds = []struct {
+	name  string
+	in    string
+	out   string
+	multi bool
+}{
+	{"array", "n; a[n]", "int", false},
+	{"queries", "n q; a[n]:int64; (l r)[q]; s:string", "[]int64", false},
+	{"multi", "n k:int64; w[n]:float", "float", true},
+	{"strings", "s:string; (x y)[2]", "string", true},
+	{"slice", "n:int64; a[n]", "[]int", false},
+}
+
+func newScaffold(t *testing.T, m *module, in, out string, multi bool) scaffold {
+	t.Helper()
+	spec, err := parseInputSpec(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	typ, ok := valueTypes[out]
+	if !ok {
+		typ = sliceOutputs[out]
+	}
+	return scaffold{module: m.path, in: spec, out: typ, multi: multi, maxTime: 1500 * time.Millisecond, memoryMB: 64, maxN: 1000}
+}
+
+// TestScaffold проверяет, что сгенерированные main.go и main_test.go
+// проходят проверку типов с пакетами из lib/, а main.rs компилируется
+func TestScaffold(t *testing.T) {
+	m, err := findModule()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, rustcErr := exec.LookPath("rustc")
+	for _, tc := range scaffolds {
+		t.Run(tc.name, func(t *testing.T) {
+			files, err := newScaffold(t, m, tc.in, tc.out, tc.multi).files()
+			if err != nil {
+				t.Fatal(err)
+			}
+			b := newBundler(m)
+			var goFiles []*ast.File
+			for _, f := range files[:2] {
+				file, err := parser.ParseFile(b.fset, f.name, f.data, 0)
+				if err != nil {
+					t.Fatalf("%s не разбирается: %v", f.name, err)
+				}
+				goFiles = append(goFiles, file)
+			}
+			conf := types.Config{Importer: b}
+			if _, err := conf.Check("main", b.fset, goFiles, nil); err != nil {
+				t.Fatalf("заготовка не проходит проверку типов: %v\n%s\n%s", err, files[0].data, files[1].data)
+			}
+
+			if testing.Short() || rustcErr != nil {
+				return
+			}
+			dir := t.TempDir()
+			src := filepath.Join(dir, "main.rs")
+			if err := os.WriteFile(src, files[2].data, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			out, err := exec.Command("rustc", "--edition", "2021", "-o", filepath.Join(dir, "main"), src).CombinedOutput()
+			if err != nil || len(out) > 0 {
+				t.Errorf("main.rs: %v\n%s", err, out)
+			}
+		})
+	}
+}
+
+func TestWriteScaffold(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "07")
+	files := []scaffoldFile{{"main.go", []byte("package main\n")}}
+	if _, err := writeScaffold(dir, files, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := writeScaffold(dir, files, false); err == nil || !strings.Contains(err.Error(), "уже существует") {
+		t.Errorf("повторная запись без -f: ошибка %v", err)
+	}
+	written, err := writeScaffold(dir, files, true)
+	if err != nil || len(written) != 1 {
+		t.Errorf("запись с -f: %v, %v", written, err)
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package main
+
+import "os"
+
+// maxRSS недоступен вне unix: проверка памяти пропускается
+func maxRSS(ps *os.ProcessState) uint64 {
+	return 0
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// example — пример из условия: ввод и ожидаемый вывод
+type example struct {
+	name   string
+	input  string
+	output string
+}
+
+// statement — то, что нужно судье из Q.md: примеры и ограничения
+type statement struct {
+	examples  []example
+	timeLimit time.Duration // 0, если в условии не найдено
+	memoryMB  int           // 0, если в условии не найдено
+}
+
+var (
+	// Заголовок примера: "### Пример 1", "## Пример"
+	exampleHeadingRe = regexp.MustCompile(`^#{2,4}\s*(Пример(?:\s+\d+)?)\s*$`)
+
+	// Метка ввода или вывода во всех встречающихся в Q.md вариантах:
+	// "**Ввод:**", "**Ввод**", "### Ввод", "Ввод", "**Вход:** 2", "Ввод 8 1 Вывод 3 3",
+	// "**Входные данные:**"
+	markerRe = regexp.MustCompile(`^(?:#{1,4}\s*)?(?:\*\*)?(Входные данные|Выходные данные|Ввод|Вход|Вывод|Выход)(?::?\*\*:?|:)?(?:\s+(.*))?$`)
+
+	// Вывод в той же строке, что и ввод: "Ввод 8 1 Вывод 3 3"
+	inlineOutputRe = regexp.MustCompile(`\s(?:\*\*)?(?:Вывод|Выход)(?::?\*\*:?|:)?\s+`)
+
+	timeLimitRe   = regexp.MustCompile(`Ограничение времени[^0-9]*?(\d+(?:[.,]\d+)?)\s*(мс|ms|секунд[аы]?|сек|с|c|s)`)
+	memoryLimitRe = regexp.MustCompile(`Ограничение памяти[^0-9]*?(\d+)\s*(МБ|Мб|MB|ГБ|Гб|GB)`)
+)
+
+// readStatement разбирает NN/Q.md
+func readStatement(dir string) (statement, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "Q.md"))
+	if err != nil {
+		return statement{}, err
+	}
+	return parseStatement(string(data)), nil
+}
+
+// parseStatement извлекает примеры и ограничения из текста условия
+func parseStatement(text string) statement {
+	st := statement{
+		examples:  parseExamples(text),
+		timeLimit: parseTimeLimit(text),
+		memoryMB:  parseMemoryLimit(text),
+	}
+	return st
+}
+
+func parseTimeLimit(text string) time.Duration {
+	m := timeLimitRe.FindStringSubmatch(text)
+	if m == nil {
+		return 0
+	}
+	value, err := strconv.ParseFloat(strings.Replace(m[1], ",", ".", 1), 64)
+	if err != nil {
+		return 0
+	}
+	unit := time.Second
+	if m[2] == "мс" || m[2] == "ms" {
+		unit = time.Millisecond
+	}
+	return time.Duration(value * float64(unit))
+}
+
+func parseMemoryLimit(text string) int {
+	m := memoryLimitRe.FindStringSubmatch(text)
+	if m == nil {
+		return 0
+	}
+	value, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0
+	}
+	switch m[2] {
+	case "ГБ", "Гб", "GB":
+		return value * 1024
+	}
+	return value
+}
+
+func parseExamples(text string) []example {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+
+	var examples []example
+	var pending *example
+	heading := ""
+
+	finish := func(output string) {
+		if pending == nil {
+			return
+		}
+		pending.output = output
+		examples = append(examples, *pending)
+		pending = nil
+	}
+	start := func(input string) {
+		name := heading
+		if name == "" {
+			name = "Пример " + strconv.Itoa(len(examples)+1)
+		}
+		pending = &example{name: name, input: input}
+		heading = ""
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if m := exampleHeadingRe.FindStringSubmatch(line); m != nil {
+			heading = m[1]
+			continue
+		}
+		m := markerRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		isInput := strings.HasPrefix(m[1], "Вв") || strings.HasPrefix(m[1], "Вх")
+		rest := strings.TrimSpace(m[2])
+
+		var value string
+		if rest != "" {
+			value = rest
+			if isInput {
+				// "Ввод 8 1 Вывод 3 3": вывод в той же строке
+				if loc := inlineOutputRe.FindStringIndex(rest); loc != nil {
+					start(rest[:loc[0]])
+					finish(rest[loc[1]:])
+					continue
+				}
+			}
+		} else {
+			value, i = readBlock(lines, i+1)
+		}
+
+		if isInput {
+			start(value)
+		} else {
+			finish(value)
+		}
+	}
+
+	for k := range examples {
+		examples[k].input = normalizeBlock(examples[k].input)
+		examples[k].output = normalizeBlock(examples[k].output)
+	}
+	return examples
+}
+
+// readBlock читает содержимое после метки, начиная со строки from:
+// блок кода ``` ... ``` или строки до пустой строки либо следующей метки.
+// Возвращает текст и индекс последней прочитанной строки.
+func readBlock(lines []string, from int) (string, int) {
+	i := from
+	for i < len(lines) && strings.TrimSpace(lines[i]) == "" {
+		i++
+	}
+	if i == len(lines) {
+		return "", i - 1
+	}
+
+	var block []string
+	if strings.HasPrefix(strings.TrimSpace(lines[i]), "```") {
+		for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+			block = append(block, lines[i])
+		}
+		return strings.Join(block, "\n"), i
+	}
+
+	for ; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" || markerRe.MatchString(line) || strings.HasPrefix(line, "#") {
+			break
+		}
+		block = append(block, line)
+	}
+	return strings.Join(block, "\n"), i - 1
+}
+
+// normalizeBlock убирает пробелы в конце строк и завершает текст переводом строки
+func normalizeBlock(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
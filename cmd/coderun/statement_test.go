@@ -0,0 +1,120 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseExamples(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []example
+	}{
+		{
+			name: "блоки кода",
+			text: "### Пример 1\n\n**Ввод:**\n\n```\n4\n2 1 4 3\n```\n\n**Вывод:**\n\n```\n3 2 1 4\n```\n",
+			want: []example{{"Пример 1", "4\n2 1 4 3\n", "3 2 1 4\n"}},
+		},
+		{
+			name: "заголовки Ввод/Вывод",
+			text: "## Пример 1\n\n### Ввод\n\n```\n1\n```\n\n### Вывод\n\n```\n2\n```\n",
+			want: []example{{"Пример 1", "1\n", "2\n"}},
+		},
+		{
+			name: "в одной строке",
+			text: "### Пример 1\n\nВвод 8 1 Вывод 3 3\n\n### Пример 2\n\nВвод 10 2 Вывод 4 3\n",
+			want: []example{{"Пример 1", "8 1\n", "3 3\n"}, {"Пример 2", "10 2\n", "4 3\n"}},
+		},
+		{
+			name: "Вход/Выход с переносом markdown",
+			text: "### Пример 1\n\n**Вход:** 2  \n**Выход:** 4\n",
+			want: []example{{"Пример 1", "2\n", "4\n"}},
+		},
+		{
+			name: "без блока кода",
+			text: "### Пример 1\n\nВвод\n5 2\n1 5\n\nВывод\n8\n",
+			want: []example{{"Пример 1", "5 2\n1 5\n", "8\n"}},
+		},
+		{
+			name: "входные и выходные данные без номера",
+			text: "## Пример\n\n**Входные данные:**\n\n```\n1\n```\n\n**Выходные данные:**\n\n```\nYES\n```\n",
+			want: []example{{"Пример", "1\n", "YES\n"}},
+		},
+		{
+			name: "слово Вводится не метка",
+			text: "Вводится 10 чисел.\n\n**Ввод:**\n\n```\n1\n```\n\n**Вывод:**\n\n```\n1\n```\n",
+			want: []example{{"Пример 1", "1\n", "1\n"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseExamples(tt.text)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseExamples() = %q, ожидалось %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLimits(t *testing.T) {
+	tests := []struct {
+		text     string
+		time     time.Duration
+		memoryMB int
+	}{
+		{"- Ограничение времени: 2 секунды\n- Ограничение памяти: 512 МБ", 2 * time.Second, 512},
+		{"- Ограничение времени 300 мс\n- Ограничение памяти 512 МБ", 300 * time.Millisecond, 512},
+		{"- Ограничение времени: `1 c`\n- Ограничение памяти: `128 МБ`", time.Second, 128},
+		{"| Ограничение времени | 2 с    |\n| Ограничение памяти  | 256 МБ |", 2 * time.Second, 256},
+		{"- Ограничение времени: 2 с\n- Ограничение памяти: 1 ГБ", 2 * time.Second, 1024},
+		{"- $N \\le 10$.", 0, 0},
+	}
+	for _, tt := range tests {
+		if got := parseTimeLimit(tt.text); got != tt.time {
+			t.Errorf("parseTimeLimit(%q) = %v, ожидалось %v", tt.text, got, tt.time)
+		}
+		if got := parseMemoryLimit(tt.text); got != tt.memoryMB {
+			t.Errorf("parseMemoryLimit(%q) = %d, ожидалось %d", tt.text, got, tt.memoryMB)
+		}
+	}
+}
+
+// TestRepoStatements проверяет, что примеры находятся во всех Q.md репозитория
+func TestRepoStatements(t *testing.T) {
+	m, err := findModule()
+	if err != nil {
+		t.Fatal(err)
+	}
+	problems, err := m.allProblems()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// В условии 20/ нет примеров, в условии 21/ нет лимитов
+	noExamples := map[string]bool{"20": true}
+	noLimits := map[string]bool{"21": true}
+
+	for _, p := range problems {
+		dir, err := m.problemDir(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		st, err := readStatement(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(st.examples) == 0 && !noExamples[p] {
+			t.Errorf("%s: примеры не найдены", p)
+		}
+		if (st.timeLimit == 0 || st.memoryMB == 0) && !noLimits[p] {
+			t.Errorf("%s: ограничения не найдены (%v, %d МБ)", p, st.timeLimit, st.memoryMB)
+		}
+		for _, ex := range st.examples {
+			if ex.input == "\n" || ex.output == "\n" {
+				t.Errorf("%s, %s: пустой ввод или вывод", p, ex.name)
+			}
+		}
+	}
+}
@@ -0,0 +1,164 @@
+// Package checker — проверяющие программы (special judge) для задач,
+// в которых подходит любой верный ответ.
+//
+// Чекер получает ввод, вывод участника и, если он известен, ответ жюри.
+// Ответ жюри нужен только там, где вывод участника нельзя проверить сам
+// по себе: например, "NO" в 21/ верно, только если жюри тоже не нашло покрытия.
+package checker
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Verdict — вердикт чекера
+type Verdict int
+
+const (
+	OK   Verdict = iota // ответ верный
+	WA                  // ответ неверный
+	PE                  // вывод не соответствует формату
+	Fail                // ошибка во вводе или ответе жюри: проблема не у участника
+)
+
+// String возвращает вердикт в обозначениях Coderun
+func (v Verdict) String() string {
+	switch v {
+	case OK:
+		return "OK"
+	case WA:
+		return "WA"
+	case PE:
+		return "PE"
+	default:
+		return "FAIL"
+	}
+}
+
+// Result — вердикт и пояснение к нему
+type Result struct {
+	Verdict Verdict
+	Message string
+}
+
+// Checker проверяет вывод участника. answer — вывод жюри; пустая строка,
+// если он неизвестен.
+type Checker interface {
+	Check(input, output, answer string) Result
+}
+
+// Func позволяет использовать функцию как Checker
+type Func func(input, output, answer string) Result
+
+// Check реализует Checker
+func (f Func) Check(input, output, answer string) Result {
+	return f(input, output, answer)
+}
+
+var registry = map[string]Checker{}
+
+// Register регистрирует чекер задачи; problem — номер каталога ("13")
+func Register(problem string, c Checker) {
+	if _, ok := registry[problem]; ok {
+		panic("checker: повторная регистрация задачи " + problem)
+	}
+	registry[problem] = c
+}
+
+// For возвращает чекер задачи, если он есть
+func For(problem string) (Checker, bool) {
+	c, ok := registry[problem]
+	return c, ok
+}
+
+// Problems возвращает номера задач, для которых есть чекеры
+func Problems() []string {
+	problems := make([]string, 0, len(registry))
+	for p := range registry {
+		problems = append(problems, p)
+	}
+	sort.Strings(problems)
+	return problems
+}
+
+func ok() Result {
+	return Result{Verdict: OK}
+}
+
+func wa(format string, args ...any) Result {
+	return Result{Verdict: WA, Message: fmt.Sprintf(format, args...)}
+}
+
+func pe(format string, args ...any) Result {
+	return Result{Verdict: PE, Message: fmt.Sprintf(format, args...)}
+}
+
+func fail(format string, args ...any) Result {
+	return Result{Verdict: Fail, Message: fmt.Sprintf(format, args...)}
+}
+
+// tokens читает вывод по словам, разделённым пробельными символами
+type tokens struct {
+	fields []string
+	pos    int
+}
+
+func newTokens(s string) *tokens {
+	return &tokens{fields: strings.Fields(s)}
+}
+
+// errEOF — вывод закончился раньше, чем ожидалось
+type errEOF struct{}
+
+func (errEOF) Error() string { return "неожиданный конец вывода" }
+
+func (t *tokens) next() (string, error) {
+	if t.pos == len(t.fields) {
+		return "", errEOF{}
+	}
+	t.pos++
+	return t.fields[t.pos-1], nil
+}
+
+// peek возвращает следующий токен, не сдвигая позицию
+func (t *tokens) peek() string {
+	if t.pos == len(t.fields) {
+		return ""
+	}
+	return t.fields[t.pos]
+}
+
+func (t *tokens) int() (int, error) {
+	s, err := t.next()
+	if err != nil {
+		return 0, err
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("токен %d: ожидалось целое число, получено %q", t.pos, s)
+	}
+	return v, nil
+}
+
+func (t *tokens) float() (float64, error) {
+	s, err := t.next()
+	if err != nil {
+		return 0, err
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, fmt.Errorf("токен %d: ожидалось вещественное число, получено %q", t.pos, s)
+	}
+	return v, nil
+}
+
+// rest возвращает ошибку, если после ответа остались лишние токены
+func (t *tokens) rest() error {
+	if t.pos < len(t.fields) {
+		return fmt.Errorf("лишние данные в конце вывода: %q", t.fields[t.pos])
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package checker
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckers(t *testing.T) {
+	tests := []struct {
+		name    string
+		problem string
+		input   string
+		output  string
+		answer  string
+		want    Verdict
+	}{
+		// 13/
+		{"13: тождественная", "13", "4\n2 1 4 3\n", "1 2 3 4\n", "", OK},
+		{"13: ответ из условия, 3 инверсии", "13", "4\n2 1 4 3\n", "3 2 1 4\n", "", WA},
+		{"13: совпадение с p", "13", "4\n2 1 4 3\n", "2 3 1 4\n", "", WA},
+		{"13: не перестановка", "13", "4\n2 1 4 3\n", "1 1 3 4\n", "", WA},
+		{"13: вне диапазона", "13", "4\n2 1 4 3\n", "1 2 3 5\n", "", WA},
+		{"13: мало чисел", "13", "4\n2 1 4 3\n", "1 2 3\n", "", PE},
+		{"13: лишние числа", "13", "4\n2 1 4 3\n", "1 2 3 4 5\n", "", PE},
+		{"13: не число", "13", "4\n2 1 4 3\n", "1 2 x 4\n", "", PE},
+		{"13: ровно ⌊n/3⌋ инверсий", "13", "6\n6 5 4 3 2 1\n", "2 1 3 5 4 6\n", "", OK},
+		{"13: ⌊n/3⌋+1 инверсий", "13", "6\n6 5 4 3 2 1\n", "3 1 2 5 4 6\n", "", WA},
+
+		// 17/
+		{"17: пример", "17", "10\n", "309\n9\n", "", OK},
+		{"17: девятки", "17", "10\n", "9\n9\n", "", OK},
+		{"17: постоянная прогрессия", "17", "1\n", "5\n7\n", "", OK},
+		{"17: нарушена на k=2", "17", "3\n", "1\n5\n", "", WA},
+		{"17: -1", "17", "10\n", "-1\n", "", WA},
+		{"17: ведущий ноль", "17", "10\n", "09\n9\n", "", WA},
+		{"17: ноль", "17", "10\n", "9\n0\n", "", WA},
+		{"17: нет d", "17", "10\n", "9\n", "", PE},
+
+		// 21/
+		{
+			"21: пример", "21",
+			"2\n2\n1.1 1.2\n-1.0 -0.9\n4\n1.0 1.1\n1.1 1.0\n1.5 1.5\n0.0 -1.0\n",
+			"YES\n2\n1.1 1.2\n-1.0 -0.9\nYES\n2\n1.0 1.0\n0.0 -1.0\n", "", OK,
+		},
+		{
+			"21: касание допустимо", "21",
+			"1\n2\n0 0\n2 0\n",
+			"YES\n2\n0 0\n2 0\n", "", OK,
+		},
+		{
+			"21: пересечение", "21",
+			"1\n2\n0 0\n1.5 0\n",
+			"YES\n2\n0 0\n1.5 0\n", "", WA,
+		},
+		{
+			"21: точка не покрыта", "21",
+			"1\n2\n0 0\n5 0\n",
+			"YES\n1\n0 0\n", "", WA,
+		},
+		{
+			"21: K больше N", "21",
+			"1\n1\n0 0\n",
+			"YES\n2\n0 0\n5 5\n", "", WA,
+		},
+		{
+			"21: NO без ответа жюри", "21",
+			"1\n1\n0 0\n",
+			"NO\n", "", Fail,
+		},
+		{
+			"21: NO при ответе жюри YES", "21",
+			"1\n1\n0 0\n",
+			"NO\n", "YES\n1\n0 0\n", WA,
+		},
+		{
+			"21: NO при ответе жюри NO", "21",
+			"1\n1\n0 0\n",
+			"NO\n", "NO\n", OK,
+		},
+		{
+			"21: участник лучше жюри", "21",
+			"1\n1\n0 0\n",
+			"YES\n1\n0 0\n", "NO\n", Fail,
+		},
+		{
+			"21: мусор вместо YES", "21",
+			"1\n1\n0 0\n",
+			"MAYBE\n", "", PE,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, ok := For(tt.problem)
+			if !ok {
+				t.Fatalf("нет чекера для %s", tt.problem)
+			}
+			res := c.Check(tt.input, tt.output, tt.answer)
+			if res.Verdict != tt.want {
+				t.Errorf("вердикт %v (%s), ожидался %v", res.Verdict, res.Message, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckDigitSumProgressionLong(t *testing.T) {
+	// Перенос через все разряды: a = 10^6 - 1, d = 1 → S скачет, это WA
+	res := checkDigitSumProgression("3\n", "999999\n1\n", "")
+	if res.Verdict != WA {
+		t.Errorf("вердикт %v, ожидался WA", res.Verdict)
+	}
+
+	// a = 10^L + 99999 длиной 2·10^5, d = 99999: S(a+kd) = 1 + S((k+1)·99999) = 46
+	long := "1" + strings.Repeat("0", maxDigits17-6) + "99999"
+	res = checkDigitSumProgression("10000\n", long+"\n99999\n", "")
+	if res.Verdict != OK {
+		t.Errorf("вердикт %v (%s), ожидался OK", res.Verdict, res.Message)
+	}
+	nines := strings.Repeat("9", maxDigits17)
+	res = checkDigitSumProgression("1\n", nines+"9\n1\n", "")
+	if res.Verdict != WA {
+		t.Errorf("a длиннее 2·10^5: вердикт %v, ожидался WA", res.Verdict)
+	}
+}
+
+func TestCountInversions(t *testing.T) {
+	tests := []struct {
+		q    []int
+		want int64
+	}{
+		{[]int{1}, 0},
+		{[]int{1, 2, 3, 4}, 0},
+		{[]int{3, 2, 1, 4}, 3},
+		{[]int{4, 3, 2, 1}, 6},
+		{[]int{2, 3, 1, 5, 6, 4}, 4},
+	}
+	for _, tt := range tests {
+		if got := countInversions(tt.q); got != tt.want {
+			t.Errorf("countInversions(%v) = %d, ожидалось %d", tt.q, got, tt.want)
+		}
+	}
+}
+
+func TestProblems(t *testing.T) {
+	got := strings.Join(Problems(), ",")
+	if got != "13,17,21" {
+		t.Errorf("Problems() = %s, ожидалось 13,17,21", got)
+	}
+}
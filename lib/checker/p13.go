@@ -0,0 +1,69 @@
+package checker
+
+func init() {
+	Register("13", Func(checkEvenDerangement))
+}
+
+// checkEvenDerangement — 13/: q должна быть перестановкой 1..n, не совпадать
+// с p ни в одной позиции и иметь не более ⌊n/3⌋ инверсий
+func checkEvenDerangement(input, output, answer string) Result {
+	in := newTokens(input)
+	n, err := in.int()
+	if err != nil || n < 1 {
+		return fail("ввод: некорректное n")
+	}
+	p := make([]int, n)
+	for i := range p {
+		if p[i], err = in.int(); err != nil {
+			return fail("ввод: %v", err)
+		}
+	}
+
+	out := newTokens(output)
+	q := make([]int, n)
+	seen := make([]bool, n+1)
+	for i := range q {
+		v, err := out.int()
+		if err != nil {
+			return pe("позиция %d: %v", i+1, err)
+		}
+		if v < 1 || v > n {
+			return wa("q[%d] = %d вне диапазона [1, %d]", i+1, v, n)
+		}
+		if seen[v] {
+			return wa("q[%d] = %d повторяется", i+1, v)
+		}
+		if v == p[i] {
+			return wa("q[%d] = p[%d] = %d", i+1, i+1, v)
+		}
+		seen[v] = true
+		q[i] = v
+	}
+	if err := out.rest(); err != nil {
+		return pe("%v", err)
+	}
+
+	if inv := countInversions(q); inv > int64(n/3) {
+		return wa("инверсий %d, допустимо не более ⌊%d/3⌋ = %d", inv, n, n/3)
+	}
+	return ok()
+}
+
+// countInversions считает инверсии перестановки 1..n деревом Фенвика
+func countInversions(q []int) int64 {
+	n := len(q)
+	tree := make([]int, n+1)
+	var inv int64
+	for i, v := range q {
+		// Сколько из уже стоящих левее не больше v
+		notGreater := 0
+		for j := v; j > 0; j -= j & -j {
+			notGreater += tree[j]
+		}
+		inv += int64(i - notGreater)
+		for j := v; j <= n; j += j & -j {
+			tree[j]++
+		}
+	}
+	return inv
+}
@@ -0,0 +1,102 @@
+package checker
+
+func init() {
+	Register("17", Func(checkDigitSumProgression))
+}
+
+// maxDigits17 — ограничение на длину a и d в 17/
+const maxDigits17 = 200000
+
+// checkDigitSumProgression — 17/: S(a), S(a+d), ..., S(a+(n-1)d) должны
+// образовывать арифметическую прогрессию. Ответ существует при любом n
+// (a = d = 9...9 из m девяток, 10^m ≥ n), поэтому "-1" всегда неверно.
+//
+// Члены прогрессии получаются прибавлением d к текущему числу, сумма цифр
+// поддерживается по мере переноса: O(n·|d|) плюс амортизированные переносы.
+func checkDigitSumProgression(input, output, answer string) Result {
+	n, err := newTokens(input).int()
+	if err != nil || n < 1 {
+		return fail("ввод: некорректное n")
+	}
+
+	out := newTokens(output)
+	if out.peek() == "-1" {
+		out.next()
+		if err := out.rest(); err != nil {
+			return pe("%v", err)
+		}
+		return wa("выведено -1, но подходящие a и d существуют при любом n")
+	}
+	a, err := out.next()
+	if err != nil {
+		return pe("a: %v", err)
+	}
+	d, err := out.next()
+	if err != nil {
+		return pe("d: %v", err)
+	}
+	if err := out.rest(); err != nil {
+		return pe("%v", err)
+	}
+	if msg := validatePositive(a); msg != "" {
+		return wa("a: %s", msg)
+	}
+	if msg := validatePositive(d); msg != "" {
+		return wa("d: %s", msg)
+	}
+
+	// Цифры в обратном порядке: cur[0] — младший разряд
+	cur := reversedDigits(a, max(len(a), len(d))+1)
+	step := reversedDigits(d, len(d))
+	sum := 0
+	for _, c := range cur {
+		sum += int(c)
+	}
+
+	first, diff := sum, 0
+	for k := 1; k < n; k++ {
+		carry := byte(0)
+		for i := 0; i < len(step) || carry > 0; i++ {
+			if i == len(cur) {
+				cur = append(cur, 0)
+			}
+			v := cur[i] + carry
+			if i < len(step) {
+				v += step[i]
+			}
+			carry = v / 10
+			sum += int(v%10) - int(cur[i])
+			cur[i] = v % 10
+		}
+		if k == 1 {
+			diff = sum - first
+		} else if sum != first+k*diff {
+			return wa("S(a+%d·d) = %d, а прогрессия требует %d", k, sum, first+k*diff)
+		}
+	}
+	return ok()
+}
+
+// validatePositive проверяет запись положительного числа без ведущих нулей
+func validatePositive(s string) string {
+	if len(s) > maxDigits17 {
+		return "больше 2·10^5 цифр"
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return "не число"
+		}
+	}
+	if s[0] == '0' {
+		return "ведущий ноль или ноль"
+	}
+	return ""
+}
+
+func reversedDigits(s string, capacity int) []byte {
+	digits := make([]byte, len(s), max(capacity, len(s)))
+	for i := 0; i < len(s); i++ {
+		digits[len(s)-1-i] = s[i] - '0'
+	}
+	return digits
+}
@@ -0,0 +1,153 @@
+package checker
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+func init() {
+	Register("21", Func(checkUnitDisks))
+}
+
+// eps21 — допуск на касание кругов и покрытие точки на границе:
+// центры выводятся с 15 знаками, но считаются в float64
+const eps21 = 1e-6
+
+type point21 struct {
+	x, y float64
+}
+
+func dist2(a, b point21) float64 {
+	dx, dy := a.x-b.x, a.y-b.y
+	return dx*dx + dy*dy
+}
+
+// checkUnitDisks — 21/: для каждого набора "YES", K ≤ N центров единичных
+// кругов, которые попарно не пересекаются (касаться можно) и покрывают все
+// точки, либо "NO". "NO" проверяется только по ответу жюри.
+func checkUnitDisks(input, output, answer string) Result {
+	in := newTokens(input)
+	t, err := in.int()
+	if err != nil || t < 0 {
+		return fail("ввод: некорректное T")
+	}
+
+	out := newTokens(output)
+	var ans *tokens
+	if strings.TrimSpace(answer) != "" {
+		ans = newTokens(answer)
+	}
+
+	for tc := 1; tc <= t; tc++ {
+		n, err := in.int()
+		if err != nil || n < 1 {
+			return fail("ввод, набор %d: некорректное N", tc)
+		}
+		points := make([]point21, n)
+		for i := range points {
+			if points[i].x, err = in.float(); err != nil {
+				return fail("ввод, набор %d: %v", tc, err)
+			}
+			if points[i].y, err = in.float(); err != nil {
+				return fail("ввод, набор %d: %v", tc, err)
+			}
+		}
+
+		juryYes, juryKnown := false, ans != nil
+		if juryKnown {
+			if juryYes, err = readDisks(ans, nil); err != nil {
+				return fail("ответ жюри, набор %d: %v", tc, err)
+			}
+		}
+
+		var centers []point21
+		yes, err := readDisks(out, &centers)
+		if err != nil {
+			return pe("набор %d: %v", tc, err)
+		}
+		if !yes {
+			switch {
+			case !juryKnown:
+				return fail("набор %d: NO нельзя проверить без ответа жюри", tc)
+			case juryYes:
+				return wa("набор %d: выведено NO, но покрытие существует", tc)
+			}
+			continue
+		}
+
+		if len(centers) > n {
+			return wa("набор %d: кругов %d больше, чем точек (%d)", tc, len(centers), n)
+		}
+		if res := validateDisks(points, centers); res.Verdict != OK {
+			res.Message = fmt.Sprintf("набор %d: %s", tc, res.Message)
+			return res
+		}
+		if juryKnown && !juryYes {
+			return fail("набор %d: участник нашёл покрытие, а жюри ответило NO", tc)
+		}
+	}
+	if err := out.rest(); err != nil {
+		return pe("%v", err)
+	}
+	return ok()
+}
+
+// readDisks читает "NO" или "YES K x1 y1 ... xK yK"; центры сохраняются в centers, если он не nil
+func readDisks(t *tokens, centers *[]point21) (bool, error) {
+	word, err := t.next()
+	if err != nil {
+		return false, err
+	}
+	switch strings.ToUpper(word) {
+	case "NO":
+		return false, nil
+	case "YES":
+	default:
+		return false, fmt.Errorf("ожидалось YES или NO, получено %q", word)
+	}
+	k, err := t.int()
+	if err != nil {
+		return false, err
+	}
+	if k < 1 {
+		return false, fmt.Errorf("K = %d, ожидалось положительное число", k)
+	}
+	for i := 0; i < k; i++ {
+		var c point21
+		if c.x, err = t.float(); err != nil {
+			return false, err
+		}
+		if c.y, err = t.float(); err != nil {
+			return false, err
+		}
+		if centers != nil {
+			*centers = append(*centers, c)
+		}
+	}
+	return true, nil
+}
+
+// validateDisks проверяет, что круги не пересекаются и покрывают все точки
+func validateDisks(points, centers []point21) Result {
+	for i := range centers {
+		for j := i + 1; j < len(centers); j++ {
+			if d2 := dist2(centers[i], centers[j]); d2 < (2-eps21)*(2-eps21) {
+				return wa("круги %d и %d пересекаются: расстояние между центрами %.9f < 2", i+1, j+1, math.Sqrt(d2))
+			}
+		}
+	}
+	for i, p := range points {
+		covered := false
+		for _, c := range centers {
+			if dist2(p, c) <= (1+eps21)*(1+eps21) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			return wa("точка %d (%g, %g) не покрыта", i+1, p.x, p.y)
+		}
+	}
+	return ok()
+}
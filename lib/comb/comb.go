@@ -0,0 +1,227 @@
+// Package comb — комбинаторика по простому модулю на факториалах, которые
+// считаются по мере надобности.
+//
+// Table хранит n! и 1/n! и удваивает таблицы, когда запрос выходит за их
+// длину, поэтому решение платит только за те n, что встретились во входе:
+//
+//	c := comb.New998244353()
+//	x := c.C(n, k).Mul(c.Catalan(m))
+//
+// Таблицы ограничены модулем: n! при n ≥ M равен нулю, поэтому C, P и
+// прочие функции на таблицах требуют n < M. Для биномиальных коэффициентов
+// с n ≥ M есть Lucas. Table не безопасна для одновременного использования
+// из нескольких горутин.
+package comb
+
+import "yandex-2025-winter/lib/modint"
+
+// Table — ленивые таблицы факториалов и производных величин по модулю M;
+// нулевое значение готово к работе
+type Table[M modint.Modulus] struct {
+	fact, invFact []modint.Int[M]
+	stirling1     [][]modint.Int[M] // треугольник c(n, k), растёт по строкам
+	bell          []modint.Int[M]
+}
+
+// New возвращает пустую таблицу по модулю M
+func New[M modint.Modulus]() *Table[M] {
+	return &Table[M]{}
+}
+
+// New1e9_7 возвращает пустую таблицу по модулю 10^9+7
+func New1e9_7() *Table[modint.P1e9_7] {
+	return New[modint.P1e9_7]()
+}
+
+// New998244353 возвращает пустую таблицу по модулю 998244353
+func New998244353() *Table[modint.P998244353] {
+	return New[modint.P998244353]()
+}
+
+// Grow досчитывает факториалы до n включительно. Вызывать не обязательно:
+// это делают все функции таблицы; Grow нужен, чтобы заплатить за таблицу
+// заранее, вне замера времени. Паникует, если n ≥ M
+func (t *Table[M]) Grow(n int) {
+	old := len(t.fact)
+	if n < old {
+		return
+	}
+	if int64(n) >= modint.Mod[M]() {
+		panic("comb: факториал не меньше модуля равен нулю")
+	}
+	size := min(max(n+1, 2*old), int(modint.Mod[M]()))
+	t.fact = append(t.fact, make([]modint.Int[M], size-old)...)
+	t.invFact = append(t.invFact, make([]modint.Int[M], size-old)...)
+	t.fact[0] = modint.New[M](1)
+	for i := max(old, 1); i < size; i++ {
+		t.fact[i] = t.fact[i-1].Mul(modint.New[M](int64(i)))
+	}
+	// Одно обращение на всю новую часть: 1/(i−1)! = i · 1/i!
+	t.invFact[size-1] = t.fact[size-1].Inv()
+	for i := size - 1; i > old; i-- {
+		t.invFact[i-1] = t.invFact[i].Mul(modint.New[M](int64(i)))
+	}
+}
+
+// Fact возвращает n!
+func (t *Table[M]) Fact(n int) modint.Int[M] {
+	t.Grow(n)
+	return t.fact[n]
+}
+
+// InvFact возвращает 1/n!
+func (t *Table[M]) InvFact(n int) modint.Int[M] {
+	t.Grow(n)
+	return t.invFact[n]
+}
+
+// Inv возвращает 1/n за O(1) через факториалы; n ≥ 1
+func (t *Table[M]) Inv(n int) modint.Int[M] {
+	t.Grow(n)
+	return t.invFact[n].Mul(t.fact[n-1])
+}
+
+// C возвращает число сочетаний C(n, k); 0 вне 0 ≤ k ≤ n
+func (t *Table[M]) C(n, k int) modint.Int[M] {
+	if n < 0 || k < 0 || k > n {
+		return modint.Int[M]{}
+	}
+	t.Grow(n)
+	return t.fact[n].Mul(t.invFact[k]).Mul(t.invFact[n-k])
+}
+
+// P возвращает число размещений n!/(n−k)!; 0 вне 0 ≤ k ≤ n
+func (t *Table[M]) P(n, k int) modint.Int[M] {
+	if n < 0 || k < 0 || k > n {
+		return modint.Int[M]{}
+	}
+	t.Grow(n)
+	return t.fact[n].Mul(t.invFact[n-k])
+}
+
+// Multinomial возвращает (k₁+…+kₘ)! / (k₁!·…·kₘ!); 0, если есть ki < 0
+func (t *Table[M]) Multinomial(ks ...int) modint.Int[M] {
+	n := 0
+	for _, k := range ks {
+		if k < 0 {
+			return modint.Int[M]{}
+		}
+		n += k
+	}
+	t.Grow(n)
+	res := t.fact[n]
+	for _, k := range ks {
+		res = res.Mul(t.invFact[k])
+	}
+	return res
+}
+
+// Catalan возвращает n-е число Каталана C(2n, n)/(n+1)
+func (t *Table[M]) Catalan(n int) modint.Int[M] {
+	if n < 0 {
+		return modint.Int[M]{}
+	}
+	return t.C(2*n, n).Sub(t.C(2*n, n+1))
+}
+
+// StirlingFirst возвращает число Стирлинга первого рода без знака c(n, k) —
+// число перестановок n элементов из k циклов. Треугольник достраивается до
+// строки n за O(n²) времени и памяти; одна строка для большого n по модулю
+// 998244353 — ntt.StirlingFirst
+func (t *Table[M]) StirlingFirst(n, k int) modint.Int[M] {
+	if n < 0 || k < 0 || k > n {
+		return modint.Int[M]{}
+	}
+	if len(t.stirling1) == 0 {
+		t.stirling1 = [][]modint.Int[M]{{modint.New[M](1)}}
+	}
+	// c(i, j) = c(i−1, j−1) + (i−1)·c(i−1, j)
+	for i := len(t.stirling1); i <= n; i++ {
+		prev := t.stirling1[i-1]
+		row := make([]modint.Int[M], i+1)
+		f := modint.New[M](int64(i - 1))
+		for j := 1; j <= i; j++ {
+			row[j] = prev[j-1]
+			if j < i {
+				row[j] = row[j].Add(f.Mul(prev[j]))
+			}
+		}
+		t.stirling1 = append(t.stirling1, row)
+	}
+	return t.stirling1[n][k]
+}
+
+// StirlingSecond возвращает число Стирлинга второго рода S(n, k) — число
+// разбиений n элементов на k непустых множеств — по формуле включений-
+// исключений S(n, k) = 1/k! · Σ (−1)^j·C(k, j)·(k−j)^n за O(k log n);
+// 0^0 = 1 даёт S(0, 0) = 1
+func (t *Table[M]) StirlingSecond(n, k int) modint.Int[M] {
+	if n < 0 || k < 0 || k > n {
+		return modint.Int[M]{}
+	}
+	var sum modint.Int[M]
+	for j := 0; j <= k; j++ {
+		term := t.C(k, j).Mul(modint.New[M](int64(k - j)).Pow(uint64(n)))
+		if j%2 == 0 {
+			sum = sum.Add(term)
+		} else {
+			sum = sum.Sub(term)
+		}
+	}
+	return sum.Mul(t.invFact[k])
+}
+
+// Bell возвращает число Белла B(n) — число разбиений n элементов на
+// непустые множества. Значения досчитываются до n по формуле
+// B(i+1) = Σ C(i, j)·B(j) за O(n²)
+func (t *Table[M]) Bell(n int) modint.Int[M] {
+	if n < 0 {
+		return modint.Int[M]{}
+	}
+	if len(t.bell) == 0 {
+		t.bell = []modint.Int[M]{modint.New[M](1)}
+	}
+	for i := len(t.bell) - 1; i < n; i++ {
+		var next modint.Int[M]
+		for j := 0; j <= i; j++ {
+			next = next.Add(t.C(i, j).Mul(t.bell[j]))
+		}
+		t.bell = append(t.bell, next)
+	}
+	return t.bell[n]
+}
+
+// Lucas возвращает C(n, k) по модулю M для любых n, k ≥ 0 по теореме
+// Люка: произведение C(nᵢ, kᵢ) по цифрам n и k в системе счисления по
+// основанию M. Цифра меньше порога таблицы берётся из таблицы, большая —
+// произведением за O(min(kᵢ, nᵢ−kᵢ)), чтобы не строить таблицу размера M
+func (t *Table[M]) Lucas(n, k uint64) modint.Int[M] {
+	p := uint64(modint.Mod[M]())
+	res := modint.New[M](1)
+	for n > 0 || k > 0 {
+		ni, ki := n%p, k%p
+		if ki > ni {
+			return modint.Int[M]{}
+		}
+		res = res.Mul(t.digit(ni, ki))
+		n, k = n/p, k/p
+	}
+	return res
+}
+
+// lucasTableLimit — цифры Lucas до этого порога считаются по таблице
+const lucasTableLimit = 1 << 22
+
+// digit возвращает C(n, k) для 0 ≤ k ≤ n < M
+func (t *Table[M]) digit(n, k uint64) modint.Int[M] {
+	if n < lucasTableLimit {
+		return t.C(int(n), int(k))
+	}
+	k = min(k, n-k)
+	num, den := modint.New[M](1), modint.New[M](1)
+	for i := uint64(0); i < k; i++ {
+		num = num.Mul(modint.New[M](int64(n - i)))
+		den = den.Mul(modint.New[M](int64(i + 1)))
+	}
+	return num.Div(den)
+}
@@ -0,0 +1,187 @@
+package comb
+
+import (
+	"math/big"
+	"testing"
+
+	"yandex-2025-winter/lib/modint"
+)
+
+// bigMod возвращает x по модулю M
+func bigMod[M modint.Modulus](x *big.Int) int64 {
+	return new(big.Int).Mod(x, big.NewInt(modint.Mod[M]())).Int64()
+}
+
+func checkBinomials[M modint.Modulus](t *testing.T) {
+	c := New[M]()
+	// Запросы в разнобой: таблица растёт несколько раз
+	for _, n := range []int{0, 1, 5, 3, 64, 65, 1000, 17, 4097} {
+		for _, k := range []int{-1, 0, 1, n / 3, n / 2, n - 1, n, n + 1} {
+			want := int64(0)
+			if k >= 0 && k <= n {
+				want = bigMod[M](new(big.Int).Binomial(int64(n), int64(k)))
+			}
+			if got := c.C(n, k).Val(); got != want {
+				t.Errorf("C(%d, %d) = %d, ожидалось %d", n, k, got, want)
+			}
+			if k >= 0 && k <= n {
+				p := new(big.Int).MulRange(int64(n-k+1), int64(n))
+				if got, want := c.P(n, k).Val(), bigMod[M](p); got != want {
+					t.Errorf("P(%d, %d) = %d, ожидалось %d", n, k, got, want)
+				}
+			}
+		}
+		if n > 0 && c.Inv(n).Mul(modint.New[M](int64(n))).Val() != 1 {
+			t.Errorf("Inv(%d)·%d != 1", n, n)
+		}
+	}
+}
+
+func TestBinomials(t *testing.T) {
+	checkBinomials[modint.P1e9_7](t)
+	checkBinomials[modint.P998244353](t)
+}
+
+func TestZeroValue(t *testing.T) {
+	var c Table[modint.P998244353]
+	if got := c.C(10, 3).Val(); got != 120 {
+		t.Errorf("C(10, 3) = %d у нулевой таблицы, ожидалось 120", got)
+	}
+}
+
+func TestMultinomial(t *testing.T) {
+	c := New1e9_7()
+	// 10!/(2!·3!·5!) = 2520
+	if got := c.Multinomial(2, 3, 5).Val(); got != 2520 {
+		t.Errorf("Multinomial(2, 3, 5) = %d, ожидалось 2520", got)
+	}
+	if got := c.Multinomial().Val(); got != 1 {
+		t.Errorf("Multinomial() = %d, ожидалось 1", got)
+	}
+	if got := c.Multinomial(1, -1).Val(); got != 0 {
+		t.Errorf("Multinomial(1, -1) = %d, ожидалось 0", got)
+	}
+}
+
+func TestCatalan(t *testing.T) {
+	c := New998244353()
+	want := []int64{1, 1, 2, 5, 14, 42, 132, 429, 1430, 4862}
+	for n, w := range want {
+		if got := c.Catalan(n).Val(); got != w {
+			t.Errorf("Catalan(%d) = %d, ожидалось %d", n, got, w)
+		}
+	}
+}
+
+func TestStirling(t *testing.T) {
+	c := New998244353()
+	// Строки треугольников при n = 5
+	first := []int64{0, 24, 50, 35, 10, 1}
+	second := []int64{0, 1, 15, 25, 10, 1}
+	for k := range 6 {
+		if got := c.StirlingFirst(5, k).Val(); got != first[k] {
+			t.Errorf("c(5, %d) = %d, ожидалось %d", k, got, first[k])
+		}
+		if got := c.StirlingSecond(5, k).Val(); got != second[k] {
+			t.Errorf("S(5, %d) = %d, ожидалось %d", k, got, second[k])
+		}
+	}
+	if c.StirlingFirst(0, 0).Val() != 1 || c.StirlingSecond(0, 0).Val() != 1 {
+		t.Error("c(0, 0) и S(0, 0) должны быть 1")
+	}
+
+	// Σ_k c(n, k) = n!, Σ_k S(n, k) = B(n)
+	for n := range 40 {
+		var sum1, sum2 modint.Mod998244353
+		for k := 0; k <= n; k++ {
+			sum1 = sum1.Add(c.StirlingFirst(n, k))
+			sum2 = sum2.Add(c.StirlingSecond(n, k))
+		}
+		if sum1 != c.Fact(n) {
+			t.Errorf("Σ c(%d, k) = %v, ожидалось %d! = %v", n, sum1, n, c.Fact(n))
+		}
+		if sum2 != c.Bell(n) {
+			t.Errorf("Σ S(%d, k) = %v, ожидалось B(%d) = %v", n, sum2, n, c.Bell(n))
+		}
+	}
+}
+
+func TestBell(t *testing.T) {
+	c := New1e9_7()
+	want := []int64{1, 1, 2, 5, 15, 52, 203, 877, 4140, 21147, 115975}
+	for n := len(want) - 1; n >= 0; n-- {
+		if got := c.Bell(n).Val(); got != want[n] {
+			t.Errorf("B(%d) = %d, ожидалось %d", n, got, want[n])
+		}
+	}
+}
+
+func TestLucas(t *testing.T) {
+	c := New1e9_7()
+	p := uint64(modint.Mod[modint.P1e9_7]())
+	tests := []struct {
+		n, k uint64
+	}{
+		{10, 3},
+		{p + 5, 3},
+		{p + 5, p + 3},
+		{p + 2, p - 1},
+		{2*p + 7, p + 3},
+		{p*p + 3*p + 4, 2*p + 1},
+		{p + 100, 50}, // цифры меньше порога таблицы
+		{p - 1, 30},   // цифра больше порога: произведение
+		{1 << 62, 1 << 40},
+	}
+	for _, tt := range tests {
+		n, k := new(big.Int).SetUint64(tt.n), new(big.Int).SetUint64(tt.k)
+		want := lucasBig(n, k, p)
+		if got := c.Lucas(tt.n, tt.k).Val(); got != want {
+			t.Errorf("Lucas(%d, %d) = %d, ожидалось %d", tt.n, tt.k, got, want)
+		}
+	}
+}
+
+// lucasBig — C(n, k) mod p по цифрам через math/big
+func lucasBig(n, k *big.Int, p uint64) int64 {
+	bp := new(big.Int).SetUint64(p)
+	res := big.NewInt(1)
+	n, k = new(big.Int).Set(n), new(big.Int).Set(k)
+	ni, ki := new(big.Int), new(big.Int)
+	for n.Sign() > 0 || k.Sign() > 0 {
+		n.DivMod(n, bp, ni)
+		k.DivMod(k, bp, ki)
+		if ki.Cmp(ni) > 0 {
+			return 0
+		}
+		// C(ni, ki) = C(ni, ni−ki): берём меньший нижний индекс
+		kk := min(ki.Int64(), ni.Int64()-ki.Int64())
+		d := new(big.Int).Binomial(ni.Int64(), kk)
+		res.Mul(res, d).Mod(res, bp)
+	}
+	return res.Int64()
+}
+
+func TestGrowPanicsAtModulus(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Grow(M) не запаниковал")
+		}
+	}()
+	New998244353().Grow(int(modint.Mod[modint.P998244353]()))
+}
+
+func BenchmarkC(b *testing.B) {
+	c := New998244353()
+	c.Grow(400000)
+	for b.Loop() {
+		for n := 1; n <= 1000; n++ {
+			c.C(400000, 400*n)
+		}
+	}
+}
+
+func BenchmarkGrow(b *testing.B) {
+	for b.Loop() {
+		New998244353().Grow(400000)
+	}
+}
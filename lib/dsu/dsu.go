@@ -0,0 +1,272 @@
+// Package dsu — система непересекающихся множеств (disjoint set union) на
+// элементах 0..n−1.
+//
+// DSU — основной вариант: итеративное сжатие путей и объединение по размеру,
+// Find работает за почти O(1) амортизированно и не уходит в глубокую
+// рекурсию на длинных цепочках. Rollback отменяет объединения в обратном
+// порядке — для обхода дерева отрезков по времени в офлайн-задачах о
+// динамической связности. Weighted хранит разность потенциалов между
+// элементами одного множества, Parity — чётность пути (двудольность).
+package dsu
+
+// DSU — система непересекающихся множеств
+type DSU struct {
+	parent []int
+	size   []int // размер множества; верен только для корней
+	count  int
+}
+
+// New возвращает систему из n одноэлементных множеств
+func New(n int) *DSU {
+	d := &DSU{parent: make([]int, n), size: make([]int, n), count: n}
+	for i := range d.parent {
+		d.parent[i] = i
+		d.size[i] = 1
+	}
+	return d
+}
+
+// Find возвращает корень множества x и подвешивает путь от x прямо к нему
+func (d *DSU) Find(x int) int {
+	root := x
+	for d.parent[root] != root {
+		root = d.parent[root]
+	}
+	for d.parent[x] != root {
+		d.parent[x], x = root, d.parent[x]
+	}
+	return root
+}
+
+// Union объединяет множества x и y; false, если они уже совпадают.
+// Меньшее множество подвешивается к большему
+func (d *DSU) Union(x, y int) bool {
+	x, y = d.Find(x), d.Find(y)
+	if x == y {
+		return false
+	}
+	if d.size[x] < d.size[y] {
+		x, y = y, x
+	}
+	d.parent[y] = x
+	d.size[x] += d.size[y]
+	d.count--
+	return true
+}
+
+// Same сообщает, лежат ли x и y в одном множестве
+func (d *DSU) Same(x, y int) bool {
+	return d.Find(x) == d.Find(y)
+}
+
+// Size возвращает размер множества x
+func (d *DSU) Size(x int) int {
+	return d.size[d.Find(x)]
+}
+
+// Count возвращает число множеств
+func (d *DSU) Count() int {
+	return d.count
+}
+
+// Rollback — система множеств с отменой объединений. Пути не сжимаются,
+// поэтому Find — O(log n) за счёт объединения по размеру
+type Rollback struct {
+	parent  []int
+	size    []int
+	count   int
+	history []int // подвешенные корни, по одному на объединение
+}
+
+// NewRollback возвращает систему из n одноэлементных множеств с отменой
+func NewRollback(n int) *Rollback {
+	d := &Rollback{parent: make([]int, n), size: make([]int, n), count: n}
+	for i := range d.parent {
+		d.parent[i] = i
+		d.size[i] = 1
+	}
+	return d
+}
+
+// Find возвращает корень множества x
+func (d *Rollback) Find(x int) int {
+	for d.parent[x] != x {
+		x = d.parent[x]
+	}
+	return x
+}
+
+// Union объединяет множества x и y; false, если они уже совпадают.
+// Неудачное объединение не попадает в историю
+func (d *Rollback) Union(x, y int) bool {
+	x, y = d.Find(x), d.Find(y)
+	if x == y {
+		return false
+	}
+	if d.size[x] < d.size[y] {
+		x, y = y, x
+	}
+	d.parent[y] = x
+	d.size[x] += d.size[y]
+	d.count--
+	d.history = append(d.history, y)
+	return true
+}
+
+// Same сообщает, лежат ли x и y в одном множестве
+func (d *Rollback) Same(x, y int) bool {
+	return d.Find(x) == d.Find(y)
+}
+
+// Size возвращает размер множества x
+func (d *Rollback) Size(x int) int {
+	return d.size[d.Find(x)]
+}
+
+// Count возвращает число множеств
+func (d *Rollback) Count() int {
+	return d.count
+}
+
+// Snapshot возвращает метку текущего состояния для RollbackTo
+func (d *Rollback) Snapshot() int {
+	return len(d.history)
+}
+
+// Undo отменяет последнее удачное объединение; false, если отменять нечего
+func (d *Rollback) Undo() bool {
+	if len(d.history) == 0 {
+		return false
+	}
+	y := d.history[len(d.history)-1]
+	d.history = d.history[:len(d.history)-1]
+	x := d.parent[y]
+	d.size[x] -= d.size[y]
+	d.parent[y] = y
+	d.count++
+	return true
+}
+
+// RollbackTo отменяет объединения, сделанные после Snapshot
+func (d *Rollback) RollbackTo(snapshot int) {
+	for len(d.history) > snapshot {
+		d.Undo()
+	}
+}
+
+// Weighted — система множеств с потенциалами: внутри множества известна
+// разность w(y) − w(x) для любых x и y
+type Weighted struct {
+	parent []int
+	size   []int
+	diff   []int64 // w(x) − w(parent[x])
+}
+
+// NewWeighted возвращает систему из n одноэлементных множеств с потенциалами
+func NewWeighted(n int) *Weighted {
+	d := &Weighted{parent: make([]int, n), size: make([]int, n), diff: make([]int64, n)}
+	for i := range d.parent {
+		d.parent[i] = i
+		d.size[i] = 1
+	}
+	return d
+}
+
+// Find возвращает корень множества x и потенциал w(x) − w(корня); путь
+// подвешивается к корню с пересчётом потенциалов
+func (d *Weighted) Find(x int) (root int, pot int64) {
+	root = x
+	for d.parent[root] != root {
+		pot += d.diff[root]
+		root = d.parent[root]
+	}
+	// Второй проход: потенциал x относительно корня известен, вычитаем по пути
+	rest := pot
+	for x != root {
+		next, dx := d.parent[x], d.diff[x]
+		d.parent[x], d.diff[x] = root, rest
+		rest -= dx
+		x = next
+	}
+	return root, pot
+}
+
+// Union добавляет условие w(y) − w(x) = delta. Если x и y уже в одном
+// множестве, ничего не меняет и возвращает, согласуется ли условие с
+// известной разностью
+func (d *Weighted) Union(x, y int, delta int64) bool {
+	rx, px := d.Find(x)
+	ry, py := d.Find(y)
+	if rx == ry {
+		return py-px == delta
+	}
+	// w(ry) − w(rx) = (w(y) − py) − (w(x) − px) = delta − py + px
+	delta += px - py
+	if d.size[rx] < d.size[ry] {
+		rx, ry, delta = ry, rx, -delta
+	}
+	d.parent[ry] = rx
+	d.diff[ry] = delta
+	d.size[rx] += d.size[ry]
+	return true
+}
+
+// Diff возвращает w(y) − w(x); ok = false, если x и y в разных множествах
+func (d *Weighted) Diff(x, y int) (delta int64, ok bool) {
+	rx, px := d.Find(x)
+	ry, py := d.Find(y)
+	if rx != ry {
+		return 0, false
+	}
+	return py - px, true
+}
+
+// Same сообщает, лежат ли x и y в одном множестве
+func (d *Weighted) Same(x, y int) bool {
+	rx, _ := d.Find(x)
+	ry, _ := d.Find(y)
+	return rx == ry
+}
+
+// Size возвращает размер множества x
+func (d *Weighted) Size(x int) int {
+	r, _ := d.Find(x)
+	return d.size[r]
+}
+
+// Parity — система множеств с чётностью: внутри множества известно, в одной
+// ли доле лежат любые два элемента. Потенциалы Weighted по модулю 2
+type Parity struct {
+	w *Weighted
+}
+
+// NewParity возвращает систему из n одноэлементных множеств с чётностью
+func NewParity(n int) *Parity {
+	return &Parity{NewWeighted(n)}
+}
+
+// Union добавляет условие «x и y в разных долях» (differ = true) или «в
+// одной доле»; false, если условие противоречит известным — граф с такими
+// рёбрами не двудольный
+func (p *Parity) Union(x, y int, differ bool) bool {
+	var delta int64
+	if differ {
+		delta = 1
+	}
+	if d, ok := p.w.Diff(x, y); ok {
+		return d&1 == delta
+	}
+	return p.w.Union(x, y, delta)
+}
+
+// Differ сообщает, в разных ли долях x и y; ok = false, если x и y в
+// разных множествах и их доли не связаны
+func (p *Parity) Differ(x, y int) (differ, ok bool) {
+	d, ok := p.w.Diff(x, y)
+	return d&1 == 1, ok
+}
+
+// Same сообщает, лежат ли x и y в одном множестве
+func (p *Parity) Same(x, y int) bool {
+	return p.w.Same(x, y)
+}
@@ -0,0 +1,262 @@
+package dsu
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// naive — множества как метки компонент; объединение перекрашивает всё
+type naive struct {
+	label []int
+	pot   []int64 // потенциал относительно произвольного начала компоненты
+}
+
+func newNaive(n int) *naive {
+	s := &naive{label: make([]int, n), pot: make([]int64, n)}
+	for i := range s.label {
+		s.label[i] = i
+	}
+	return s
+}
+
+// union сливает компоненты x и y так, что pot[y] − pot[x] = delta
+func (s *naive) union(x, y int, delta int64) bool {
+	lx, ly := s.label[x], s.label[y]
+	if lx == ly {
+		return s.pot[y]-s.pot[x] == delta
+	}
+	shift := s.pot[x] + delta - s.pot[y]
+	for i := range s.label {
+		if s.label[i] == ly {
+			s.label[i] = lx
+			s.pot[i] += shift
+		}
+	}
+	return true
+}
+
+func (s *naive) size(x int) int {
+	cnt := 0
+	for _, l := range s.label {
+		if l == s.label[x] {
+			cnt++
+		}
+	}
+	return cnt
+}
+
+func (s *naive) count() int {
+	seen := make(map[int]bool)
+	for _, l := range s.label {
+		seen[l] = true
+	}
+	return len(seen)
+}
+
+func TestDSU(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for range 200 {
+		n := 1 + r.Intn(30)
+		d, s := New(n), newNaive(n)
+		for range 3 * n {
+			x, y := r.Intn(n), r.Intn(n)
+			want := s.label[x] != s.label[y]
+			s.union(x, y, 0)
+			if got := d.Union(x, y); got != want {
+				t.Fatalf("Union(%d, %d) = %v, ожидалось %v", x, y, got, want)
+			}
+			x, y = r.Intn(n), r.Intn(n)
+			if d.Same(x, y) != (s.label[x] == s.label[y]) {
+				t.Fatalf("Same(%d, %d) = %v", x, y, d.Same(x, y))
+			}
+			if d.Size(x) != s.size(x) {
+				t.Fatalf("Size(%d) = %d, ожидалось %d", x, d.Size(x), s.size(x))
+			}
+			if d.Count() != s.count() {
+				t.Fatalf("Count() = %d, ожидалось %d", d.Count(), s.count())
+			}
+		}
+	}
+}
+
+func TestDeepChain(t *testing.T) {
+	// Цепочка без объединения по размеру: parent[i] = i−1. Рекурсивный find
+	// на ней уходил бы на глубину n
+	const n = 1 << 20
+	d := New(n)
+	for i := 1; i < n; i++ {
+		d.parent[i] = i - 1
+	}
+	d.size[0] = n
+	if root := d.Find(n - 1); root != 0 {
+		t.Fatalf("Find(n−1) = %d, ожидалось 0", root)
+	}
+	if d.parent[n-1] != 0 || d.parent[n/2] != 0 {
+		t.Error("путь не сжат к корню")
+	}
+}
+
+func TestRollbackUndo(t *testing.T) {
+	d := NewRollback(4)
+	s := d.Snapshot()
+	d.Union(0, 1)
+	d.Union(1, 0) // неудачное: не в истории
+	d.Union(2, 3)
+	d.Union(0, 3)
+	if d.Count() != 1 || d.Size(2) != 4 {
+		t.Fatalf("после объединений Count = %d, Size = %d", d.Count(), d.Size(2))
+	}
+	d.Undo()
+	if d.Same(0, 3) || d.Size(0) != 2 || d.Count() != 2 {
+		t.Fatal("Undo не отменил последнее объединение")
+	}
+	d.RollbackTo(s)
+	if d.Count() != 4 || d.Undo() {
+		t.Fatal("RollbackTo не вернул начальное состояние")
+	}
+}
+
+// TestRollbackDynamicConnectivity решает офлайн динамическую связность:
+// каждое ребро живёт на отрезке запросов и добавляется в вершины дерева
+// отрезков по времени; обход дерева добавляет рёбра при входе и
+// откатывает при выходе. Ответы сравниваются с пересчётом с нуля
+func TestRollbackDynamicConnectivity(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for range 100 {
+		n, q := 1+r.Intn(12), 1+r.Intn(60)
+		type edge struct{ u, v, from, to int } // живёт на [from, to)
+		var edges []edge
+		alive := map[[2]int]int{} // ребро → момент добавления
+		want := make([]int, q)    // число компонент после каждого запроса
+		for i := range q {
+			u, v := r.Intn(n), r.Intn(n)
+			if u > v {
+				u, v = v, u
+			}
+			key := [2]int{u, v}
+			if from, ok := alive[key]; ok {
+				edges = append(edges, edge{u, v, from, i})
+				delete(alive, key)
+			} else {
+				alive[key] = i
+			}
+			s := newNaive(n)
+			for e := range alive {
+				s.union(e[0], e[1], 0)
+			}
+			want[i] = s.count()
+		}
+		for e, from := range alive {
+			edges = append(edges, edge{e[0], e[1], from, q})
+		}
+
+		tree := make([][][2]int, 4*q)
+		var add func(v, l, r int, e edge)
+		add = func(v, l, r int, e edge) {
+			if e.to <= l || r <= e.from {
+				return
+			}
+			if e.from <= l && r <= e.to {
+				tree[v] = append(tree[v], [2]int{e.u, e.v})
+				return
+			}
+			m := (l + r) / 2
+			add(2*v, l, m, e)
+			add(2*v+1, m, r, e)
+		}
+		for _, e := range edges {
+			add(1, 0, q, e)
+		}
+
+		d := NewRollback(n)
+		got := make([]int, q)
+		var walk func(v, l, r int)
+		walk = func(v, l, r int) {
+			snap := d.Snapshot()
+			for _, e := range tree[v] {
+				d.Union(e[0], e[1])
+			}
+			if r-l == 1 {
+				got[l] = d.Count()
+			} else {
+				m := (l + r) / 2
+				walk(2*v, l, m)
+				walk(2*v+1, m, r)
+			}
+			d.RollbackTo(snap)
+		}
+		walk(1, 0, q)
+		for i := range q {
+			if got[i] != want[i] {
+				t.Fatalf("n=%d: после запроса %d компонент %d, ожидалось %d", n, i, got[i], want[i])
+			}
+		}
+		if d.Count() != n {
+			t.Fatal("после обхода остались объединения")
+		}
+	}
+}
+
+func TestWeighted(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	for range 200 {
+		n := 1 + r.Intn(20)
+		d, s := NewWeighted(n), newNaive(n)
+		for range 3 * n {
+			x, y := r.Intn(n), r.Intn(n)
+			delta := int64(r.Intn(21) - 10)
+			if s.label[x] == s.label[y] && r.Intn(2) == 0 {
+				delta = s.pot[y] - s.pot[x] // согласованное условие
+			}
+			want := s.union(x, y, delta)
+			if got := d.Union(x, y, delta); got != want {
+				t.Fatalf("Union(%d, %d, %d) = %v, ожидалось %v", x, y, delta, got, want)
+			}
+			x, y = r.Intn(n), r.Intn(n)
+			got, ok := d.Diff(x, y)
+			if ok != (s.label[x] == s.label[y]) || ok && got != s.pot[y]-s.pot[x] {
+				t.Fatalf("Diff(%d, %d) = %d, %v", x, y, got, ok)
+			}
+			if d.Size(x) != s.size(x) {
+				t.Fatalf("Size(%d) = %d, ожидалось %d", x, d.Size(x), s.size(x))
+			}
+		}
+	}
+}
+
+func TestParity(t *testing.T) {
+	// Нечётный цикл 0−1−2−0 не двудольный, чётный 3−4−5−6−3 — двудольный
+	p := NewParity(7)
+	if !p.Union(0, 1, true) || !p.Union(1, 2, true) || p.Union(2, 0, true) {
+		t.Error("нечётный цикл должен дать противоречие на последнем ребре")
+	}
+	for _, e := range [][2]int{{3, 4}, {4, 5}, {5, 6}, {6, 3}} {
+		if !p.Union(e[0], e[1], true) {
+			t.Errorf("чётный цикл: противоречие на ребре %v", e)
+		}
+	}
+	if differ, ok := p.Differ(3, 5); !ok || differ {
+		t.Errorf("Differ(3, 5) = %v, %v; ожидалось false, true", differ, ok)
+	}
+	if differ, ok := p.Differ(3, 6); !ok || !differ {
+		t.Errorf("Differ(3, 6) = %v, %v; ожидалось true, true", differ, ok)
+	}
+	if _, ok := p.Differ(0, 3); ok || p.Same(0, 3) {
+		t.Error("0 и 3 в разных множествах")
+	}
+}
+
+func BenchmarkUnionFind(b *testing.B) {
+	const n = 1 << 20
+	r := rand.New(rand.NewSource(1))
+	pairs := make([][2]int, n)
+	for i := range pairs {
+		pairs[i] = [2]int{r.Intn(n), r.Intn(n)}
+	}
+	for b.Loop() {
+		d := New(n)
+		for _, p := range pairs {
+			d.Union(p[0], p[1])
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package fastio
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestReader(t *testing.T) {
+	in := "3 -17 +4\n\t18446744073709551615  -9223372036854775808\r\n2.5e3 слово -0.125\nостаток строки \r\nпоследняя"
+	for _, size := range []int{DefaultSize, 16} {
+		t.Run(strconv.Itoa(size), func(t *testing.T) {
+			// Побайтовое чтение и маленький буфер проверяют токены на границе буфера
+			r := NewReaderSize(iotest.OneByteReader(strings.NewReader(in)), size)
+			if got := r.Int(); got != 3 {
+				t.Errorf("Int() = %d, ожидалось 3", got)
+			}
+			if got := r.Int(); got != -17 {
+				t.Errorf("Int() = %d, ожидалось -17", got)
+			}
+			if got := r.Uint(); got != 4 {
+				t.Errorf("Uint() = %d, ожидалось 4", got)
+			}
+			if got := r.Uint64(); got != math.MaxUint64 {
+				t.Errorf("Uint64() = %d, ожидалось %d", got, uint64(math.MaxUint64))
+			}
+			if got := r.Int64(); got != math.MinInt64 {
+				t.Errorf("Int64() = %d, ожидалось %d", got, int64(math.MinInt64))
+			}
+			if got := r.Float64(); got != 2500 {
+				t.Errorf("Float64() = %v, ожидалось 2500", got)
+			}
+			if got := r.Word(); got != "слово" {
+				t.Errorf("Word() = %q, ожидалось %q", got, "слово")
+			}
+			if got := r.Float64(); got != -0.125 {
+				t.Errorf("Float64() = %v, ожидалось -0.125", got)
+			}
+			if got := r.Line(); got != "" {
+				t.Errorf("Line() после числа = %q, ожидался пустой остаток", got)
+			}
+			if got := r.Line(); got != "остаток строки " {
+				t.Errorf("Line() = %q, ожидалось %q", got, "остаток строки ")
+			}
+			if got := r.Line(); got != "последняя" {
+				t.Errorf("Line() = %q, ожидалось %q", got, "последняя")
+			}
+			if r.More() {
+				t.Errorf("More() = true в конце входа")
+			}
+			if err := r.Err(); err != nil {
+				t.Errorf("Err() = %v до чтения за концом", err)
+			}
+			if got := r.Int(); got != 0 || !errors.Is(r.Err(), io.EOF) {
+				t.Errorf("Int() за концом = %d, Err() = %v, ожидалось 0, io.EOF", got, r.Err())
+			}
+		})
+	}
+}
+
+func TestReaderLongToken(t *testing.T) {
+	word := strings.Repeat("x", 1000)
+	r := NewReaderSize(strings.NewReader(" "+word+" 7"), 16)
+	if got := r.Word(); got != word {
+		t.Errorf("Word() вернул %d байт, ожидалось %d", len(got), len(word))
+	}
+	if got := r.Int(); got != 7 {
+		t.Errorf("Int() = %d, ожидалось 7", got)
+	}
+}
+
+func TestReaderErrors(t *testing.T) {
+	tests := []struct {
+		in   string
+		read func(r *Reader)
+		want error
+	}{
+		{"", func(r *Reader) { r.Int() }, io.EOF},
+		{"  \n", func(r *Reader) { r.Word() }, io.EOF},
+		{"", func(r *Reader) { r.Line() }, io.EOF},
+		{"12a", func(r *Reader) { r.Int() }, strconv.ErrSyntax},
+		{"-", func(r *Reader) { r.Int() }, strconv.ErrSyntax},
+		{"-5", func(r *Reader) { r.Uint64() }, strconv.ErrSyntax},
+		{"9223372036854775808", func(r *Reader) { r.Int64() }, strconv.ErrRange},
+		{"-9223372036854775809", func(r *Reader) { r.Int64() }, strconv.ErrRange},
+		{"18446744073709551616", func(r *Reader) { r.Uint64() }, strconv.ErrRange},
+		{"99999999999999999999x", func(r *Reader) { r.Int64() }, strconv.ErrSyntax},
+		{"1.5.2", func(r *Reader) { r.Float64() }, strconv.ErrSyntax},
+	}
+	for _, tt := range tests {
+		r := NewReader(strings.NewReader(tt.in))
+		tt.read(r)
+		if !errors.Is(r.Err(), tt.want) {
+			t.Errorf("%q: Err() = %v, ожидалось %v", tt.in, r.Err(), tt.want)
+		}
+	}
+
+	// Ошибка запоминается: дальше читаются нули
+	r := NewReader(strings.NewReader("x 5"))
+	r.Int()
+	if got := r.Int(); got != 0 || !errors.Is(r.Err(), strconv.ErrSyntax) {
+		t.Errorf("после ошибки Int() = %d, Err() = %v", got, r.Err())
+	}
+
+	// Ошибка нижележащего io.Reader
+	boom := errors.New("boom")
+	r = NewReader(iotest.ErrReader(boom))
+	if r.Int(); !errors.Is(r.Err(), boom) {
+		t.Errorf("Err() = %v, ожидалось %v", r.Err(), boom)
+	}
+}
+
+func TestReaderAllocs(t *testing.T) {
+	in := strings.Repeat("123456789 -987654321 2.5 ", 1000)
+	r := NewReader(strings.NewReader(in))
+	allocs := testing.AllocsPerRun(500, func() {
+		r.Int()
+		r.Int64()
+		r.Float64()
+	})
+	if allocs != 0 {
+		t.Errorf("чтение чисел выделяет память: %v раз за вызов", allocs)
+	}
+}
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	w.Int(-5)
+	w.WriteByte(' ')
+	w.Int64(math.MaxInt64)
+	w.WriteByte(' ')
+	w.Uint64(math.MaxUint64)
+	w.WriteByte(' ')
+	w.Float64(2.0/3, 4)
+	w.WriteByte('\n')
+	w.Ints([]int{1, 2, 3})
+	w.Int64s(nil)
+	w.Line("ok")
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	want := "-5 9223372036854775807 18446744073709551615 0.6667\n1 2 3\n\nok\n"
+	if buf.String() != want {
+		t.Errorf("вывод %q, ожидалось %q", buf.String(), want)
+	}
+}
+
+// input19 — вход максимального размера для 19/: 2·10^6 чисел
+func input19() []byte {
+	r := rand.New(rand.NewSource(1))
+	var b bytes.Buffer
+	const n = 2000000
+	b.WriteString(strconv.Itoa(n) + "\n")
+	for i := range n {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(strconv.Itoa(r.Intn(1000000)))
+	}
+	b.WriteByte('\n')
+	return b.Bytes()
+}
+
+// BenchmarkRead сравнивает Reader с fmt.Fscan и bufio.Scanner на входе 19/
+func BenchmarkRead(b *testing.B) {
+	data := input19()
+	readers := []struct {
+		name string
+		read func(in io.Reader) int
+	}{
+		{"fastio", func(in io.Reader) int {
+			r := NewReader(in)
+			n, sum := r.Int(), 0
+			for range n {
+				sum += r.Int()
+			}
+			return sum
+		}},
+		{"scanner", func(in io.Reader) int {
+			sc := bufio.NewScanner(in)
+			sc.Split(bufio.ScanWords)
+			sc.Scan()
+			n, _ := strconv.Atoi(sc.Text())
+			sum := 0
+			for range n {
+				sc.Scan()
+				v, _ := strconv.Atoi(sc.Text())
+				sum += v
+			}
+			return sum
+		}},
+		{"fscan", func(in io.Reader) int {
+			br := bufio.NewReader(in)
+			var n, v, sum int
+			fmt.Fscan(br, &n)
+			for range n {
+				fmt.Fscan(br, &v)
+				sum += v
+			}
+			return sum
+		}},
+	}
+	want := readers[0].read(bytes.NewReader(data))
+	for _, rd := range readers {
+		b.Run(rd.name, func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			for b.Loop() {
+				if got := rd.read(bytes.NewReader(data)); got != want {
+					b.Fatalf("сумма %d, ожидалось %d", got, want)
+				}
+			}
+		})
+	}
+}
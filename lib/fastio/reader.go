@@ -0,0 +1,275 @@
+// Package fastio — быстрый ввод-вывод для решений.
+//
+// Reader читает числа и слова, разделённые пробельными символами, без
+// выделений памяти: токен разбирается прямо в буфере. Ошибки не
+// возвращаются из каждого вызова, а запоминаются, как в bufio.Scanner:
+// после первой ошибки методы возвращают нулевые значения, а Err сообщает
+// причину — io.EOF, если вход закончился, *strconv.NumError, если токен
+// не число, или ошибку нижележащего io.Reader.
+//
+// Writer — bufio.Writer с методами для чисел, которые пишут прямо в буфер.
+package fastio
+
+import (
+	"io"
+	"math"
+	"strconv"
+)
+
+// DefaultSize — размер буфера по умолчанию
+const DefaultSize = 1 << 16
+
+// Reader читает токены из io.Reader
+type Reader struct {
+	r        io.Reader
+	buf      []byte
+	pos, end int   // непрочитанные байты — buf[pos:end]
+	rerr     error // ошибка нижележащего чтения; данные в буфере ещё можно читать
+	err      error // первая ошибка разбора, которую видит вызывающий
+}
+
+// NewReader возвращает Reader с буфером DefaultSize
+func NewReader(r io.Reader) *Reader {
+	return NewReaderSize(r, DefaultSize)
+}
+
+// NewReaderSize возвращает Reader с буфером size байт; длинные токены
+// увеличивают буфер
+func NewReaderSize(r io.Reader, size int) *Reader {
+	return &Reader{r: r, buf: make([]byte, max(size, 16))}
+}
+
+// Err возвращает первую ошибку чтения или nil
+func (r *Reader) Err() error {
+	return r.err
+}
+
+// fill сдвигает непрочитанные байты в начало буфера и дочитывает вход;
+// возвращает false, если новых данных нет
+func (r *Reader) fill() bool {
+	if r.rerr != nil {
+		return false
+	}
+	if r.pos > 0 {
+		r.end = copy(r.buf, r.buf[r.pos:r.end])
+		r.pos = 0
+	}
+	if r.end == len(r.buf) {
+		r.buf = append(r.buf, make([]byte, len(r.buf))...)
+	}
+	for range 100 {
+		n, err := r.r.Read(r.buf[r.end:])
+		r.end += n
+		if err != nil {
+			r.rerr = err
+			return n > 0
+		}
+		if n > 0 {
+			return true
+		}
+	}
+	r.rerr = io.ErrNoProgress
+	return false
+}
+
+// fail запоминает ошибку, если её ещё нет
+func (r *Reader) fail(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+// skipSpace пропускает пробельные символы; возвращает false, если вход закончился
+func (r *Reader) skipSpace() bool {
+	for {
+		for r.pos < r.end {
+			if r.buf[r.pos] > ' ' {
+				return true
+			}
+			r.pos++
+		}
+		if !r.fill() {
+			return false
+		}
+	}
+}
+
+// More сообщает, остался ли во входе хотя бы один токен
+func (r *Reader) More() bool {
+	return r.err == nil && r.skipSpace()
+}
+
+// Bytes возвращает следующий токен; срез указывает в буфер Reader
+// и действителен до следующего вызова
+func (r *Reader) Bytes() []byte {
+	if r.err != nil {
+		return nil
+	}
+	if !r.skipSpace() {
+		r.fail(r.rerr)
+		return nil
+	}
+	i := r.pos
+	for {
+		for i < r.end && r.buf[i] > ' ' {
+			i++
+		}
+		if i < r.end {
+			break
+		}
+		// Токен упирается в конец буфера: fill сдвигает его в начало
+		off := i - r.pos
+		more := r.fill()
+		i = r.pos + off
+		if !more {
+			break
+		}
+	}
+	tok := r.buf[r.pos:i]
+	r.pos = i
+	return tok
+}
+
+// Word возвращает следующий токен строкой
+func (r *Reader) Word() string {
+	return string(r.Bytes())
+}
+
+// Line возвращает остаток текущей строки без '\n' и '\r'. После чтения
+// чисел остаток строки обычно пуст: Line переходит на следующую строку
+func (r *Reader) Line() string {
+	if r.err != nil {
+		return ""
+	}
+	if r.pos == r.end && !r.fill() {
+		r.fail(r.rerr)
+		return ""
+	}
+	i := r.pos
+	for {
+		for i < r.end && r.buf[i] != '\n' {
+			i++
+		}
+		if i < r.end {
+			break
+		}
+		off := i - r.pos
+		more := r.fill()
+		i = r.pos + off
+		if !more {
+			break
+		}
+	}
+	line := r.buf[r.pos:i]
+	r.pos = i
+	if i < r.end {
+		r.pos++ // '\n'
+	}
+	if n := len(line); n > 0 && line[n-1] == '\r' {
+		line = line[:n-1]
+	}
+	return string(line)
+}
+
+// Int читает целое число со знаком
+func (r *Reader) Int() int {
+	return int(r.signed("Int", strconv.IntSize))
+}
+
+// Int64 читает 64-битное целое число со знаком
+func (r *Reader) Int64() int64 {
+	return r.signed("Int64", 64)
+}
+
+// Uint читает целое число без знака
+func (r *Reader) Uint() uint {
+	return uint(r.unsigned("Uint", strconv.IntSize))
+}
+
+// Uint64 читает 64-битное целое число без знака
+func (r *Reader) Uint64() uint64 {
+	return r.unsigned("Uint64", 64)
+}
+
+// Float64 читает число с плавающей точкой в формате strconv.ParseFloat
+func (r *Reader) Float64() float64 {
+	tok := r.Bytes()
+	if tok == nil {
+		return 0
+	}
+	v, err := strconv.ParseFloat(string(tok), 64)
+	if err != nil {
+		r.fail(err)
+		return 0
+	}
+	return v
+}
+
+// signed разбирает токен как целое со знаком из bits бит
+func (r *Reader) signed(fn string, bits int) int64 {
+	tok := r.Bytes()
+	if tok == nil {
+		return 0
+	}
+	neg := tok[0] == '-'
+	digits := tok
+	if tok[0] == '-' || tok[0] == '+' {
+		digits = tok[1:]
+	}
+	limit := uint64(1) << (bits - 1) // |min|; max на единицу меньше
+	u, err := parseDigits(digits, limit)
+	if err == nil && !neg && u == limit {
+		err = strconv.ErrRange
+	}
+	if err != nil {
+		r.fail(&strconv.NumError{Func: "fastio." + fn, Num: string(tok), Err: err})
+		return 0
+	}
+	if neg {
+		return -int64(u)
+	}
+	return int64(u)
+}
+
+// unsigned разбирает токен как целое без знака из bits бит
+func (r *Reader) unsigned(fn string, bits int) uint64 {
+	tok := r.Bytes()
+	if tok == nil {
+		return 0
+	}
+	digits := tok
+	if tok[0] == '+' {
+		digits = tok[1:]
+	}
+	u, err := parseDigits(digits, math.MaxUint64>>(64-bits))
+	if err != nil {
+		r.fail(&strconv.NumError{Func: "fastio." + fn, Num: string(tok), Err: err})
+		return 0
+	}
+	return u
+}
+
+// parseDigits разбирает непустую строку десятичных цифр, не большую limit
+func parseDigits(digits []byte, limit uint64) (uint64, error) {
+	if len(digits) == 0 {
+		return 0, strconv.ErrSyntax
+	}
+	u := uint64(0)
+	for _, c := range digits {
+		d := uint64(c - '0')
+		if d > 9 {
+			return 0, strconv.ErrSyntax
+		}
+		if u > (limit-d)/10 {
+			// Переполнение; остаток токена всё равно проверяется на синтаксис
+			for _, c := range digits {
+				if c-'0' > 9 {
+					return 0, strconv.ErrSyntax
+				}
+			}
+			return 0, strconv.ErrRange
+		}
+		u = u*10 + d
+	}
+	return u, nil
+}
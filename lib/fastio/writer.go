@@ -0,0 +1,71 @@
+package fastio
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+)
+
+// Writer — буферизованный вывод с методами для чисел. Ошибки записи, как
+// у bufio.Writer, запоминаются и возвращаются из Flush
+type Writer struct {
+	*bufio.Writer
+}
+
+// NewWriter возвращает Writer с буфером DefaultSize
+func NewWriter(w io.Writer) *Writer {
+	return NewWriterSize(w, DefaultSize)
+}
+
+// NewWriterSize возвращает Writer с буфером size байт
+func NewWriterSize(w io.Writer, size int) *Writer {
+	return &Writer{bufio.NewWriterSize(w, size)}
+}
+
+// Int записывает целое число
+func (w *Writer) Int(v int) {
+	w.Write(strconv.AppendInt(w.AvailableBuffer(), int64(v), 10))
+}
+
+// Int64 записывает 64-битное целое число
+func (w *Writer) Int64(v int64) {
+	w.Write(strconv.AppendInt(w.AvailableBuffer(), v, 10))
+}
+
+// Uint64 записывает целое число без знака
+func (w *Writer) Uint64(v uint64) {
+	w.Write(strconv.AppendUint(w.AvailableBuffer(), v, 10))
+}
+
+// Float64 записывает число с prec знаками после точки
+func (w *Writer) Float64(v float64, prec int) {
+	w.Write(strconv.AppendFloat(w.AvailableBuffer(), v, 'f', prec, 64))
+}
+
+// Ints записывает числа через пробел и перевод строки
+func (w *Writer) Ints(vs []int) {
+	for i, v := range vs {
+		if i > 0 {
+			w.WriteByte(' ')
+		}
+		w.Int(v)
+	}
+	w.WriteByte('\n')
+}
+
+// Int64s записывает числа через пробел и перевод строки
+func (w *Writer) Int64s(vs []int64) {
+	for i, v := range vs {
+		if i > 0 {
+			w.WriteByte(' ')
+		}
+		w.Int64(v)
+	}
+	w.WriteByte('\n')
+}
+
+// Line записывает строку и перевод строки
+func (w *Writer) Line(s string) {
+	w.WriteString(s)
+	w.WriteByte('\n')
+}
@@ -0,0 +1,403 @@
+// Package flow — потоки минимальной стоимости с нижними границами на рёбрах.
+//
+// Ребро задаётся границами lower ≤ f ≤ upper и стоимостью единицы потока.
+// Нижние границы сводятся к запасам в вершинах (lower единиц уже «течёт» по
+// ребру), поэтому обязательные рёбра не нужно заставлять заполняться
+// огромной отрицательной стоимостью. Задача решается одним из двух способов:
+//
+//   - MinCostFlow — последовательные кратчайшие пути: Беллман — Форд для
+//     начальных потенциалов, затем Дейкстра на индексированной куче с
+//     остановкой на стоке. O(F · m log n) для потока F; граф не должен
+//     содержать циклов отрицательной стоимости. Быстрее при небольшом F.
+//   - MinCostFlowScaling — допустимый поток алгоритмом Диница, затем
+//     масштабирование стоимостей (push-relabel Голдберга — Тарьяна),
+//     O(n² m log(nC)) для стоимостей до C. Время не зависит от величины
+//     потока, отрицательные циклы допустимы.
+//
+// После решения поток по ребру — Flow, разложение на пути — Paths. Граф
+// решается один раз: повторный вызов паникует.
+package flow
+
+import "math"
+
+// inf — «бесконечное» расстояние
+const inf = math.MaxInt64
+
+// arc — дуга остаточной сети; дуги 2·id и 2·id+1 — ребро id и обратная к нему
+type arc struct {
+	to   int
+	cap  int // остаточная пропускная способность
+	cost int64
+}
+
+// Edge — ребро графа с потоком после решения
+type Edge struct {
+	From, To     int
+	Lower, Upper int
+	Cost         int64
+	Flow         int
+}
+
+// Graph — сеть с нижними и верхними границами потока на рёбрах
+type Graph struct {
+	n      int
+	arcs   []arc
+	adj    [][]int // номера дуг, выходящих из вершины
+	lower  []int   // нижняя граница ребра id
+	excess []int   // запас вершины из нижних границ и требуемого потока
+	solved bool
+	s, t   int // источник и сток решённой задачи
+	k      int // величина потока из s в t
+}
+
+// New возвращает сеть из n вершин без рёбер
+func New(n int) *Graph {
+	return &Graph{n: n, adj: make([][]int, n), excess: make([]int, n)}
+}
+
+// AddEdge добавляет ребро from → to с потоком от lower до upper и
+// стоимостью cost за единицу и возвращает его номер: 0, 1, 2, ...
+func (g *Graph) AddEdge(from, to, lower, upper int, cost int64) int {
+	if lower < 0 || lower > upper {
+		panic("flow: нужно 0 ≤ lower ≤ upper")
+	}
+	id := len(g.lower)
+	g.adj[from] = append(g.adj[from], len(g.arcs))
+	g.arcs = append(g.arcs, arc{to, upper - lower, cost})
+	g.adj[to] = append(g.adj[to], len(g.arcs))
+	g.arcs = append(g.arcs, arc{from, 0, -cost})
+	g.lower = append(g.lower, lower)
+	g.excess[from] -= lower
+	g.excess[to] += lower
+	return id
+}
+
+// Flow возвращает поток по ребру id
+func (g *Graph) Flow(id int) int {
+	return g.lower[id] + g.arcs[2*id+1].cap
+}
+
+// Edge возвращает ребро id вместе с потоком по нему
+func (g *Graph) Edge(id int) Edge {
+	fwd, back := g.arcs[2*id], g.arcs[2*id+1]
+	lower := g.lower[id]
+	return Edge{
+		From: back.to, To: fwd.to,
+		Lower: lower, Upper: lower + fwd.cap + back.cap,
+		Cost: fwd.cost, Flow: lower + back.cap,
+	}
+}
+
+// EdgeCount возвращает число рёбер
+func (g *Graph) EdgeCount() int {
+	return len(g.lower)
+}
+
+// cost возвращает стоимость текущего потока по рёбрам
+func (g *Graph) cost() int64 {
+	var res int64
+	for id := range g.lower {
+		res += int64(g.Flow(id)) * g.arcs[2*id].cost
+	}
+	return res
+}
+
+// prepare сводит задачу «k единиц из s в t» с нижними границами к потоку
+// из новой вершины src в новую вершину snk величины need: вершины с
+// избытком получают дугу из src, с недостатком — дугу в snk
+func (g *Graph) prepare(s, t, k int) (src, snk, need int) {
+	if g.solved {
+		panic("flow: граф уже решён")
+	}
+	g.solved = true
+	g.s, g.t, g.k = s, t, k
+	g.excess[s] += k
+	g.excess[t] -= k
+	src, snk = g.n, g.n+1
+	g.adj = append(g.adj, nil, nil)
+	for v, e := range g.excess[:g.n] {
+		if e > 0 {
+			g.addArc(src, v, e)
+			need += e
+		} else if e < 0 {
+			g.addArc(v, snk, -e)
+		}
+	}
+	return src, snk, need
+}
+
+// addArc добавляет служебную дугу нулевой стоимости без номера ребра
+func (g *Graph) addArc(from, to, cap int) {
+	g.adj[from] = append(g.adj[from], len(g.arcs))
+	g.arcs = append(g.arcs, arc{to, cap, 0})
+	g.adj[to] = append(g.adj[to], len(g.arcs))
+	g.arcs = append(g.arcs, arc{from, 0, 0})
+}
+
+// push пропускает d единиц по дуге i
+func (g *Graph) push(i, d int) {
+	g.arcs[i].cap -= d
+	g.arcs[i^1].cap += d
+}
+
+// MinCostFlow пропускает ровно k единиц из s в t с соблюдением границ на
+// всех рёбрах и возвращает наименьшую стоимость; ok = false, если такого
+// потока нет. Паникует, если в сети есть цикл отрицательной стоимости
+func (g *Graph) MinCostFlow(s, t, k int) (cost int64, ok bool) {
+	src, snk, need := g.prepare(s, t, k)
+	n := len(g.adj)
+	pot := g.bellmanFord()
+	dist := make([]int64, n)
+	prev := make([]int, n) // дуга, по которой пришли в вершину
+	h := newIndexedHeap(n)
+	flow := 0
+	for flow < need {
+		// Дейкстра по приведённым стоимостям cost + pot[u] − pot[v] ≥ 0
+		for v := range dist {
+			dist[v] = inf
+		}
+		dist[src] = 0
+		h.update(src, 0)
+		for h.len() > 0 {
+			u := h.pop()
+			if u == snk {
+				break // дальние вершины не нужны: их потенциал сдвинется на dist[snk]
+			}
+			for _, i := range g.adj[u] {
+				a := g.arcs[i]
+				if a.cap == 0 {
+					continue
+				}
+				if d := dist[u] + a.cost + pot[u] - pot[a.to]; d < dist[a.to] {
+					dist[a.to] = d
+					prev[a.to] = i
+					h.update(a.to, d)
+				}
+			}
+		}
+		h.clear()
+		if dist[snk] == inf {
+			break
+		}
+		// Приведённые стоимости остаются неотрицательными, если сдвинуть
+		// каждую вершину на min(dist, dist[snk])
+		for v, d := range dist {
+			pot[v] += min(d, dist[snk])
+		}
+
+		push := need - flow
+		for v := snk; v != src; v = g.arcs[prev[v]^1].to {
+			push = min(push, g.arcs[prev[v]].cap)
+		}
+		for v := snk; v != src; v = g.arcs[prev[v]^1].to {
+			g.push(prev[v], push)
+		}
+		flow += push
+	}
+	if flow < need {
+		return 0, false
+	}
+	return g.cost(), true
+}
+
+// bellmanFord возвращает потенциалы, при которых приведённые стоимости
+// всех дуг с положительной остаточной способностью неотрицательны:
+// расстояния от воображаемой вершины с дугами нулевой стоимости во все
+// вершины (очередь вместо полных проходов)
+func (g *Graph) bellmanFord() []int64 {
+	n := len(g.adj)
+	dist := make([]int64, n)
+	inQueue := make([]bool, n)
+	relaxed := make([]int, n)
+	queue := make([]int, n)
+	for v := range queue {
+		queue[v] = v
+		inQueue[v] = true
+	}
+	for len(queue) > 0 {
+		u := queue[0]
+		queue = queue[1:]
+		inQueue[u] = false
+		for _, i := range g.adj[u] {
+			a := g.arcs[i]
+			if a.cap > 0 && dist[u]+a.cost < dist[a.to] {
+				dist[a.to] = dist[u] + a.cost
+				if !inQueue[a.to] {
+					if relaxed[a.to]++; relaxed[a.to] > n {
+						panic("flow: цикл отрицательной стоимости, нужен MinCostFlowScaling")
+					}
+					queue = append(queue, a.to)
+					inQueue[a.to] = true
+				}
+			}
+		}
+	}
+	return dist
+}
+
+// indexedHeap — двоичная куча вершин по ключу с уменьшением ключа на месте:
+// каждая вершина лежит в куче не больше одного раза
+type indexedHeap struct {
+	heap []int
+	pos  []int // позиция вершины в heap или −1
+	key  []int64
+}
+
+func newIndexedHeap(n int) *indexedHeap {
+	h := &indexedHeap{pos: make([]int, n), key: make([]int64, n)}
+	for v := range h.pos {
+		h.pos[v] = -1
+	}
+	return h
+}
+
+func (h *indexedHeap) len() int {
+	return len(h.heap)
+}
+
+// update добавляет v с ключом key или уменьшает её ключ до key
+func (h *indexedHeap) update(v int, key int64) {
+	h.key[v] = key
+	if h.pos[v] < 0 {
+		h.pos[v] = len(h.heap)
+		h.heap = append(h.heap, v)
+	}
+	h.up(h.pos[v])
+}
+
+// clear опустошает кучу
+func (h *indexedHeap) clear() {
+	for _, v := range h.heap {
+		h.pos[v] = -1
+	}
+	h.heap = h.heap[:0]
+}
+
+// pop извлекает вершину с наименьшим ключом
+func (h *indexedHeap) pop() int {
+	v := h.heap[0]
+	last := len(h.heap) - 1
+	h.swap(0, last)
+	h.heap = h.heap[:last]
+	h.pos[v] = -1
+	if last > 0 {
+		h.down(0)
+	}
+	return v
+}
+
+func (h *indexedHeap) up(i int) {
+	for i > 0 {
+		p := (i - 1) / 2
+		if h.key[h.heap[p]] <= h.key[h.heap[i]] {
+			return
+		}
+		h.swap(i, p)
+		i = p
+	}
+}
+
+func (h *indexedHeap) down(i int) {
+	for {
+		c := 2*i + 1
+		if c >= len(h.heap) {
+			return
+		}
+		if c+1 < len(h.heap) && h.key[h.heap[c+1]] < h.key[h.heap[c]] {
+			c++
+		}
+		if h.key[h.heap[i]] <= h.key[h.heap[c]] {
+			return
+		}
+		h.swap(i, c)
+		i = c
+	}
+}
+
+func (h *indexedHeap) swap(i, j int) {
+	h.heap[i], h.heap[j] = h.heap[j], h.heap[i]
+	h.pos[h.heap[i]] = i
+	h.pos[h.heap[j]] = j
+}
+
+// Path — путь из источника в сток: номера рёбер по порядку и поток по нему
+type Path struct {
+	Edges  []int
+	Amount int
+}
+
+// Paths раскладывает найденный поток на пути из s в t с суммой Amount,
+// равной k. Циклы, которые встречаются по дороге, отбрасываются: они не
+// переносят поток из s в t. Каждый путь простой, путей не больше числа рёбер
+func (g *Graph) Paths() []Path {
+	if !g.solved {
+		panic("flow: граф ещё не решён")
+	}
+	left := make([]int, len(g.lower)) // неразложенный поток по ребру
+	out := make([][]int, g.n)         // рёбра, выходящие из вершины
+	for id := range left {
+		left[id] = g.Flow(id)
+		from := g.arcs[2*id+1].to
+		out[from] = append(out[from], id)
+	}
+	next := make([]int, g.n) // первое ребро out[v], по которому ещё есть поток
+	onPath := make([]int, g.n)
+	for v := range onPath {
+		onPath[v] = -1
+	}
+	// step возвращает ребро из v с остатком потока или −1
+	step := func(v int) int {
+		for ; next[v] < len(out[v]); next[v]++ {
+			if id := out[v][next[v]]; left[id] > 0 {
+				return id
+			}
+		}
+		return -1
+	}
+
+	var res []Path
+	for sum := 0; g.s != g.t && sum < g.k; {
+		var edges []int
+		onPath[g.s] = 0
+		v := g.s
+		for v != g.t {
+			id := step(v)
+			if id < 0 {
+				break // потока нет: MinCostFlow вернул ok = false
+			}
+			edges = append(edges, id)
+			v = g.arcs[2*id].to
+			if i := onPath[v]; i >= 0 {
+				// Цикл edges[i:] — вычитаем его поток и возвращаемся в v
+				amount := math.MaxInt
+				for _, e := range edges[i:] {
+					amount = min(amount, left[e])
+				}
+				for _, e := range edges[i:] {
+					left[e] -= amount
+					onPath[g.arcs[2*e].to] = -1
+				}
+				edges = edges[:i]
+			}
+			onPath[v] = len(edges)
+		}
+		for _, e := range edges {
+			onPath[g.arcs[2*e+1].to] = -1
+		}
+		onPath[v] = -1
+		if v != g.t {
+			break
+		}
+		// Поток по пути не больше недостающего до k: остальное — циклы через s и t
+		amount := g.k - sum
+		for _, e := range edges {
+			amount = min(amount, left[e])
+		}
+		for _, e := range edges {
+			left[e] -= amount
+		}
+		sum += amount
+		res = append(res, Path{Edges: edges, Amount: amount})
+	}
+	return res
+}
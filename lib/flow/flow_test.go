@@ -0,0 +1,293 @@
+package flow
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// edgeSpec — ребро случайной сети для тестов
+type edgeSpec struct {
+	from, to, lower, upper int
+	cost                   int64
+}
+
+// randomEdges строит m случайных рёбер на n вершинах; при dag рёбра идут
+// только от меньшей вершины к большей, и циклов нет
+func randomEdges(r *rand.Rand, n, m int, dag bool) []edgeSpec {
+	edges := make([]edgeSpec, m)
+	for i := range edges {
+		u, v := r.Intn(n), r.Intn(n)
+		if dag {
+			for u == v {
+				u, v = r.Intn(n), r.Intn(n)
+			}
+			u, v = min(u, v), max(u, v)
+		}
+		lower := 0
+		if r.Intn(3) == 0 {
+			lower = r.Intn(2)
+		}
+		edges[i] = edgeSpec{u, v, lower, lower + r.Intn(3), int64(r.Intn(21) - 10)}
+	}
+	return edges
+}
+
+// bruteMinCost перебирает поток по каждому ребру от lower до upper и
+// возвращает наименьшую стоимость потока величины k из s в t
+func bruteMinCost(n int, edges []edgeSpec, s, t, k int) (int64, bool) {
+	flow := make([]int, len(edges))
+	balance := make([]int, n)
+	best, found := int64(0), false
+	var rec func(i int, cost int64)
+	rec = func(i int, cost int64) {
+		if i == len(edges) {
+			for v, b := range balance {
+				want := 0
+				if v == s {
+					want -= k
+				}
+				if v == t {
+					want += k
+				}
+				if b != want {
+					return
+				}
+			}
+			if !found || cost < best {
+				best, found = cost, true
+			}
+			return
+		}
+		e := edges[i]
+		for f := e.lower; f <= e.upper; f++ {
+			flow[i] = f
+			balance[e.from] -= f
+			balance[e.to] += f
+			rec(i+1, cost+int64(f)*e.cost)
+			balance[e.from] += f
+			balance[e.to] -= f
+		}
+	}
+	rec(0, 0)
+	return best, found
+}
+
+func build(n int, edges []edgeSpec) *Graph {
+	g := New(n)
+	for _, e := range edges {
+		g.AddEdge(e.from, e.to, e.lower, e.upper, e.cost)
+	}
+	return g
+}
+
+// checkSolution проверяет границы, сохранение потока, стоимость и
+// разложение на пути
+func checkSolution(t *testing.T, g *Graph, n, s, tt, k int, cost int64) {
+	t.Helper()
+	balance := make([]int, n)
+	var total int64
+	for id := range g.EdgeCount() {
+		e := g.Edge(id)
+		if e.Flow < e.Lower || e.Flow > e.Upper || e.Flow != g.Flow(id) {
+			t.Fatalf("ребро %d: поток %d вне [%d, %d]", id, e.Flow, e.Lower, e.Upper)
+		}
+		balance[e.From] -= e.Flow
+		balance[e.To] += e.Flow
+		total += int64(e.Flow) * e.Cost
+	}
+	if s != tt {
+		balance[s] += k
+		balance[tt] -= k
+	}
+	for v, b := range balance {
+		if b != 0 {
+			t.Fatalf("в вершине %d не сохраняется поток: %d", v, b)
+		}
+	}
+	if total != cost {
+		t.Fatalf("стоимость по рёбрам %d, возвращено %d", total, cost)
+	}
+
+	used := make([]int, g.EdgeCount())
+	sum := 0
+	for _, p := range g.Paths() {
+		v := s
+		for _, id := range p.Edges {
+			e := g.Edge(id)
+			if e.From != v {
+				t.Fatalf("путь %v разорван на ребре %d", p.Edges, id)
+			}
+			v = e.To
+			used[id] += p.Amount
+		}
+		if v != tt || p.Amount <= 0 {
+			t.Fatalf("путь %v с потоком %d не доходит до стока", p.Edges, p.Amount)
+		}
+		sum += p.Amount
+	}
+	if s != tt && sum != k {
+		t.Fatalf("пути переносят %d единиц, ожидалось %d", sum, k)
+	}
+	for id, u := range used {
+		if u > g.Flow(id) {
+			t.Fatalf("пути пропускают по ребру %d больше потока: %d > %d", id, u, g.Flow(id))
+		}
+	}
+}
+
+func TestMinCostFlow(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for range 500 {
+		n := 2 + r.Intn(4)
+		edges := randomEdges(r, n, 1+r.Intn(6), true)
+		s, tt, k := 0, n-1, r.Intn(3)
+		want, wantOK := bruteMinCost(n, edges, s, tt, k)
+		g := build(n, edges)
+		got, ok := g.MinCostFlow(s, tt, k)
+		if ok != wantOK || ok && got != want {
+			t.Fatalf("n=%d рёбра %v k=%d: MinCostFlow = %d, %v; ожидалось %d, %v", n, edges, k, got, ok, want, wantOK)
+		}
+		if ok {
+			checkSolution(t, g, n, s, tt, k, got)
+		}
+	}
+}
+
+func TestMinCostFlowScaling(t *testing.T) {
+	// Произвольные рёбра: петли, встречные рёбра и циклы отрицательной стоимости
+	r := rand.New(rand.NewSource(2))
+	for range 500 {
+		n := 2 + r.Intn(4)
+		edges := randomEdges(r, n, 1+r.Intn(6), false)
+		s, tt, k := r.Intn(n), r.Intn(n), r.Intn(3)
+		want, wantOK := bruteMinCost(n, edges, s, tt, k)
+		g := build(n, edges)
+		got, ok := g.MinCostFlowScaling(s, tt, k)
+		if ok != wantOK || ok && got != want {
+			t.Fatalf("n=%d рёбра %v s=%d t=%d k=%d: MinCostFlowScaling = %d, %v; ожидалось %d, %v", n, edges, s, tt, k, got, ok, want, wantOK)
+		}
+		if ok {
+			checkSolution(t, g, n, s, tt, k, got)
+		}
+	}
+}
+
+func TestBackendsAgree(t *testing.T) {
+	// Сети крупнее перебора: оба способа должны дать одну стоимость
+	r := rand.New(rand.NewSource(3))
+	for range 100 {
+		n := 5 + r.Intn(30)
+		edges := randomEdges(r, n, n+r.Intn(5*n), true)
+		for i := range edges {
+			edges[i].upper += r.Intn(5)
+			edges[i].cost *= int64(1 + r.Intn(1000))
+		}
+		k := r.Intn(10)
+		g1, g2 := build(n, edges), build(n, edges)
+		c1, ok1 := g1.MinCostFlow(0, n-1, k)
+		c2, ok2 := g2.MinCostFlowScaling(0, n-1, k)
+		if ok1 != ok2 || c1 != c2 {
+			t.Fatalf("n=%d k=%d: MinCostFlow = %d, %v; MinCostFlowScaling = %d, %v", n, k, c1, ok1, c2, ok2)
+		}
+		if ok1 {
+			checkSolution(t, g1, n, 0, n-1, k, c1)
+			checkSolution(t, g2, n, 0, n-1, k, c2)
+		}
+	}
+}
+
+func TestLowerBounds(t *testing.T) {
+	// Обязательное ребро дороже обходного: без нижней границы поток пошёл бы
+	// по дешёвому пути, с ней — обязан пройти по дорогому
+	g := New(3)
+	cheap := g.AddEdge(0, 2, 0, 1, 1)
+	g.AddEdge(0, 1, 1, 1, 5)
+	g.AddEdge(1, 2, 0, 1, 5)
+	if cost, ok := g.MinCostFlow(0, 2, 1); !ok || cost != 10 || g.Flow(cheap) != 0 {
+		t.Errorf("MinCostFlow = %d, %v, по дешёвому ребру %d", cost, ok, g.Flow(cheap))
+	}
+
+	// Нижняя граница, которую нельзя выполнить
+	g = New(3)
+	g.AddEdge(0, 1, 2, 2, 0)
+	g.AddEdge(1, 2, 0, 1, 0)
+	if _, ok := g.MinCostFlow(0, 2, 2); ok {
+		t.Error("MinCostFlow: ожидалось отсутствие потока")
+	}
+}
+
+func TestNegativeCycle(t *testing.T) {
+	// Цикл 1 → 2 → 1 выгодно загрузить целиком, даже без потока из s в t
+	g := New(3)
+	g.AddEdge(0, 2, 0, 1, 0)
+	g.AddEdge(1, 2, 0, 4, -3)
+	g.AddEdge(2, 1, 0, 3, 1)
+	if cost, ok := g.MinCostFlowScaling(0, 2, 1); !ok || cost != -6 {
+		t.Errorf("MinCostFlowScaling = %d, %v; ожидалось -6, true", cost, ok)
+	}
+	if paths := g.Paths(); len(paths) != 1 || len(paths[0].Edges) != 1 {
+		t.Errorf("Paths = %v, ожидался один путь по ребру 0", paths)
+	}
+
+	g = New(3)
+	g.AddEdge(1, 2, 0, 4, -3)
+	g.AddEdge(2, 1, 0, 3, 1)
+	defer func() {
+		if recover() == nil {
+			t.Error("MinCostFlow на сети с отрицательным циклом не паникует")
+		}
+	}()
+	g.MinCostFlow(0, 2, 0)
+}
+
+func TestPanics(t *testing.T) {
+	for name, f := range map[string]func(){
+		"lower > upper": func() { New(2).AddEdge(0, 1, 2, 1, 0) },
+		"lower < 0":     func() { New(2).AddEdge(0, 1, -1, 1, 0) },
+		"повторно": func() {
+			g := New(2)
+			g.MinCostFlow(0, 1, 0)
+			g.MinCostFlowScaling(0, 1, 0)
+		},
+		"Paths до решения": func() { New(2).Paths() },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: ожидалась паника", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+// assignment строит сеть назначения n элементов в m групп с плотными
+// рёбрами: каждый элемент ровно в одну группу, в группе от q до q+1
+func assignment(n, m int) *Graph {
+	r := rand.New(rand.NewSource(1))
+	g := New(n + m + 2)
+	s, t := n+m, n+m+1
+	for i := range n {
+		g.AddEdge(s, i, 1, 1, 0)
+		for j := range m {
+			g.AddEdge(i, n+j, 0, 1, int64(r.Intn(1_000_000)))
+		}
+	}
+	for j := range m {
+		g.AddEdge(n+j, t, n/m, n/m+1, 0)
+	}
+	return g
+}
+
+func BenchmarkMinCostFlow(b *testing.B) {
+	for b.Loop() {
+		assignment(400, 100).MinCostFlow(400+100, 400+100+1, 400)
+	}
+}
+
+func BenchmarkMinCostFlowScaling(b *testing.B) {
+	for b.Loop() {
+		assignment(400, 100).MinCostFlowScaling(400+100, 400+100+1, 400)
+	}
+}
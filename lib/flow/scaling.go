@@ -0,0 +1,154 @@
+package flow
+
+import "math"
+
+// alpha — во сколько раз уменьшается ε между фазами масштабирования
+const alpha = 8
+
+// MinCostFlowScaling решает ту же задачу, что MinCostFlow: ровно k единиц
+// из s в t с соблюдением границ, наименьшая стоимость; ok = false, если
+// такого потока нет. Сначала алгоритм Диница ищет допустимый поток, затем
+// масштабирование стоимостей делает его оптимальным. Циклы отрицательной
+// стоимости допустимы
+func (g *Graph) MinCostFlowScaling(s, t, k int) (cost int64, ok bool) {
+	src, snk, need := g.prepare(s, t, k)
+	if g.maxFlow(src, snk) < need {
+		return 0, false
+	}
+	g.costScaling()
+	return g.cost(), true
+}
+
+// maxFlow пропускает из s в t наибольший поток алгоритмом Диница
+func (g *Graph) maxFlow(s, t int) int {
+	n := len(g.adj)
+	level := make([]int, n)
+	iter := make([]int, n)
+	queue := make([]int, 0, n)
+	var dfs func(v, limit int) int
+	dfs = func(v, limit int) int {
+		if v == t {
+			return limit
+		}
+		for ; iter[v] < len(g.adj[v]); iter[v]++ {
+			i := g.adj[v][iter[v]]
+			a := g.arcs[i]
+			if a.cap == 0 || level[a.to] != level[v]+1 {
+				continue
+			}
+			if d := dfs(a.to, min(limit, a.cap)); d > 0 {
+				g.push(i, d)
+				return d
+			}
+		}
+		return 0
+	}
+
+	total := 0
+	for {
+		for v := range level {
+			level[v] = -1
+		}
+		level[s] = 0
+		queue = append(queue[:0], s)
+		for i := 0; i < len(queue); i++ {
+			v := queue[i]
+			for _, j := range g.adj[v] {
+				if a := g.arcs[j]; a.cap > 0 && level[a.to] < 0 {
+					level[a.to] = level[v] + 1
+					queue = append(queue, a.to)
+				}
+			}
+		}
+		if level[t] < 0 {
+			return total
+		}
+		clear(iter)
+		for {
+			d := dfs(s, math.MaxInt)
+			if d == 0 {
+				break
+			}
+			total += d
+		}
+	}
+}
+
+// costScaling делает допустимую циркуляцию оптимальной. Стоимости
+// умножаются на n+1: тогда поток, ε-оптимальный при ε = 1, оптимален для
+// исходных стоимостей. Каждая фаза (refine) насыщает дуги с отрицательной
+// приведённой стоимостью и проталкивает избытки push-relabel'ом, пока их
+// не останется; ε уменьшается в alpha раз
+func (g *Graph) costScaling() {
+	n := len(g.adj)
+	scale := int64(n + 1)
+	var maxCost int64
+	for _, a := range g.arcs {
+		maxCost = max(maxCost, a.cost*scale, -a.cost*scale)
+	}
+	pot := make([]int64, n)
+	excess := make([]int, n)
+	cur := make([]int, n)
+	inQueue := make([]bool, n)
+	var queue []int
+
+	for eps := maxCost; eps > 1; {
+		eps = max(1, eps/alpha)
+
+		// Насыщаем дуги с отрицательной приведённой стоимостью: поток
+		// становится 0-оптимальным, но появляются избытки и недостатки
+		for v := range n {
+			for _, i := range g.adj[v] {
+				a := g.arcs[i]
+				if a.cap > 0 && a.cost*scale+pot[v]-pot[a.to] < 0 {
+					excess[v] -= a.cap
+					excess[a.to] += a.cap
+					g.push(i, a.cap)
+				}
+			}
+		}
+		for v := range n {
+			if excess[v] > 0 {
+				queue = append(queue, v)
+				inQueue[v] = true
+			}
+		}
+		clear(cur)
+
+		for len(queue) > 0 {
+			v := queue[0]
+			queue = queue[1:]
+			inQueue[v] = false
+			for excess[v] > 0 {
+				if cur[v] == len(g.adj[v]) {
+					// relabel: наибольшая цена, при которой появится допустимая
+					// дуга, а остальные останутся ε-оптимальными; обход
+					// продолжается с этой дуги
+					best, bestIdx := int64(-inf), 0
+					for idx, i := range g.adj[v] {
+						if a := g.arcs[i]; a.cap > 0 && pot[a.to]-a.cost*scale > best {
+							best, bestIdx = pot[a.to]-a.cost*scale, idx
+						}
+					}
+					pot[v] = best - eps
+					cur[v] = bestIdx
+					continue
+				}
+				i := g.adj[v][cur[v]]
+				a := g.arcs[i]
+				if a.cap == 0 || a.cost*scale+pot[v]-pot[a.to] >= 0 {
+					cur[v]++
+					continue
+				}
+				d := min(excess[v], a.cap)
+				g.push(i, d)
+				excess[v] -= d
+				excess[a.to] += d
+				if excess[a.to] > 0 && !inQueue[a.to] {
+					queue = append(queue, a.to)
+					inQueue[a.to] = true
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,103 @@
+// Package gen — генераторы случайных входов задач в формате условия.
+//
+// Генератор получает источник случайности и масштаб size: основной размер
+// входа (длина массива, число запросов) не превосходит size и ограничений
+// условия. Входы нужны там, где решение запускается как программа:
+// `coderun diff` сравнивает на них реализации на Go, Rust и Dart.
+package gen
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// Generator строит случайный вход задачи масштаба size ≥ 1
+type Generator func(r *rand.Rand, size int) string
+
+var registry = map[string]Generator{}
+
+// Register регистрирует генератор задачи; problem — номер каталога ("13")
+func Register(problem string, g Generator) {
+	if _, ok := registry[problem]; ok {
+		panic("gen: повторная регистрация задачи " + problem)
+	}
+	registry[problem] = g
+}
+
+// For возвращает генератор задачи, если он есть
+func For(problem string) (Generator, bool) {
+	g, ok := registry[problem]
+	return g, ok
+}
+
+// Problems возвращает номера задач, для которых есть генераторы
+func Problems() []string {
+	problems := make([]string, 0, len(registry))
+	for p := range registry {
+		problems = append(problems, p)
+	}
+	sort.Strings(problems)
+	return problems
+}
+
+// between возвращает случайное число из [lo, hi]
+func between(r *rand.Rand, lo, hi int64) int64 {
+	return lo + r.Int63n(hi-lo+1)
+}
+
+// upTo возвращает случайный размер из [lo, min(size, hi)], но не меньше lo
+func upTo(r *rand.Rand, lo, size, hi int) int {
+	return int(between(r, int64(lo), int64(max(lo, min(size, hi)))))
+}
+
+// writer собирает вход построчно
+type writer struct {
+	strings.Builder
+}
+
+// line записывает значения через пробел и перевод строки
+func (w *writer) line(values ...any) {
+	for i, v := range values {
+		if i > 0 {
+			w.WriteByte(' ')
+		}
+		fmt.Fprint(w, v)
+	}
+	w.WriteByte('\n')
+}
+
+// ints записывает срез чисел одной строкой
+func (w *writer) ints(values []int64) {
+	for i, v := range values {
+		if i > 0 {
+			w.WriteByte(' ')
+		}
+		fmt.Fprint(w, v)
+	}
+	w.WriteByte('\n')
+}
+
+// randInts возвращает n случайных чисел из [lo, hi]
+func randInts(r *rand.Rand, n int, lo, hi int64) []int64 {
+	s := make([]int64, n)
+	for i := range s {
+		s[i] = between(r, lo, hi)
+	}
+	return s
+}
+
+// distinct возвращает n различных случайных чисел из [lo, hi]; n ≤ hi-lo+1
+func distinct(r *rand.Rand, n int, lo, hi int64) []int64 {
+	seen := make(map[int64]bool, n)
+	s := make([]int64, 0, n)
+	for len(s) < n {
+		v := between(r, lo, hi)
+		if !seen[v] {
+			seen[v] = true
+			s = append(s, v)
+		}
+	}
+	return s
+}
@@ -0,0 +1,49 @@
+package gen
+
+import (
+	"math/rand"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEveryProblemHasGenerator(t *testing.T) {
+	dirs, err := filepath.Glob("../../[0-9][0-9]/main.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range dirs {
+		name := filepath.Base(filepath.Dir(path))
+		if _, ok := For(name); !ok {
+			t.Errorf("нет генератора для %s", name)
+		}
+	}
+}
+
+func TestGeneratorsDeterministic(t *testing.T) {
+	for _, name := range Problems() {
+		g, _ := For(name)
+		for _, size := range []int{1, 10} {
+			a := g(rand.New(rand.NewSource(1)), size)
+			b := g(rand.New(rand.NewSource(1)), size)
+			if a != b {
+				t.Errorf("%s, size=%d: разные входы при одном сиде", name, size)
+			}
+			if strings.TrimSpace(a) == "" || !strings.HasSuffix(a, "\n") {
+				t.Errorf("%s, size=%d: вход %q пуст или без перевода строки в конце", name, size, a)
+			}
+		}
+	}
+}
+
+func TestDistinct(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	s := distinct(r, 28, 0, 27)
+	seen := map[int64]bool{}
+	for _, v := range s {
+		if v < 0 || v > 27 || seen[v] {
+			t.Fatalf("distinct(28, 0, 27) = %v", s)
+		}
+		seen[v] = true
+	}
+}
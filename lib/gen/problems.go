@@ -0,0 +1,286 @@
+package gen
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Генераторы задач; ограничения взяты из Q.md, масштаб урезан до size
+func init() {
+	Register("01", func(r *rand.Rand, size int) string {
+		hi := int64(max(8, size*size))
+		var w writer
+		w.line(between(r, 8, hi), between(r, 1, hi))
+		return w.String()
+	})
+
+	Register("02", func(r *rand.Rand, size int) string {
+		var w writer
+		for _, v := range randInts(r, 10, 1, 100) {
+			w.line(v)
+		}
+		return w.String()
+	})
+
+	Register("03", func(r *rand.Rand, size int) string {
+		n := upTo(r, 1, size, 100000)
+		ws := randInts(r, n, 1, int64(max(2, size*size)))
+		total := int64(0)
+		for _, v := range ws {
+			total += v
+		}
+		if total < 2 {
+			ws[0]++
+			total++
+		}
+		// Суммарная потребность строго больше M
+		var w writer
+		w.line(between(r, 1, total-1), n)
+		w.ints(ws)
+		return w.String()
+	})
+
+	Register("04", func(r *rand.Rand, size int) string {
+		q := upTo(r, 1, size, 50000)
+		var w writer
+		w.line(q)
+		for range q {
+			// Половина запросов — у верхней границы 10^18
+			hi := int64(1e18)
+			if r.Intn(2) == 0 {
+				hi = int64(max(1, size*size*size))
+			}
+			l := between(r, 1, hi)
+			w.line(l, between(r, l, hi))
+		}
+		return w.String()
+	})
+
+	Register("05", func(r *rand.Rand, size int) string {
+		n := upTo(r, 1, size, 100000)
+		c := int64(max(1, size))
+		if r.Intn(4) == 0 {
+			c = 1e9
+		}
+		var w writer
+		w.line(n)
+		for range n {
+			w.line(between(r, -c, c), between(r, -c, c), between(r, -c, c))
+		}
+		return w.String()
+	})
+
+	Register("06", func(r *rand.Rand, size int) string {
+		n := upTo(r, 1, size, 100000)
+		m := upTo(r, 1, 3*size, 300000)
+		c := int64(max(1, size))
+		var w writer
+		w.line(n, m)
+		for range n {
+			w.line(between(r, -c, c), between(r, -c, c))
+		}
+		cmd := make([]byte, m)
+		for i := range cmd {
+			cmd[i] = "NSWE"[r.Intn(4)]
+		}
+		w.line(string(cmd))
+		return w.String()
+	})
+
+	Register("07", func(r *rand.Rand, size int) string {
+		t := upTo(r, 1, size, 50000)
+		var w writer
+		w.line(t)
+		for range t {
+			w.line(upTo(r, 1, size, 200000), upTo(r, 1, size, 200000))
+		}
+		return w.String()
+	})
+
+	Register("08", func(r *rand.Rand, size int) string {
+		q := upTo(r, 1, size, 50000)
+		hi := int64(min(700000, max(1, size*size)))
+		var w writer
+		w.line(q)
+		for range q {
+			l := between(r, 1, hi)
+			w.line(between(r, 1, 100), l, between(r, l, hi))
+		}
+		return w.String()
+	})
+
+	Register("09", func(r *rand.Rand, size int) string {
+		n := between(r, 1, int64(max(1, size*size)))
+		if r.Intn(4) == 0 {
+			n = between(r, 1, 1e9)
+		}
+		lim := min(n, 1e6)
+		k := upTo(r, 1, size, int(min(lim, 100000)))
+		var w writer
+		w.line(n, k)
+		w.ints(distinct(r, k, 1, lim))
+		return w.String()
+	})
+
+	Register("10", func(r *rand.Rand, size int) string {
+		t := upTo(r, 1, max(1, size/4), 30000)
+		var w writer
+		w.line(t)
+		for range t {
+			n := upTo(r, 1, size, 200000)
+			m := upTo(r, 1, size, 200000)
+			w.line(n, m)
+			w.ints(randInts(r, m, 1, int64(n)))
+			w.ints(randInts(r, m, 1, int64(n)))
+			w.ints(randInts(r, m, 1, int64(max(1, size))))
+		}
+		return w.String()
+	})
+
+	Register("11", func(r *rand.Rand, size int) string {
+		var w writer
+		w.line(upTo(r, 1, size*size, 2000000))
+		return w.String()
+	})
+
+	Register("12", func(r *rand.Rand, size int) string {
+		n := between(r, 3, int64(max(3, size)))
+		if r.Intn(4) == 0 {
+			n = between(r, 3, 1e12)
+		}
+		m := upTo(r, 1, size, 28)
+		var w writer
+		w.line(n, m)
+		w.ints(distinct(r, m, 0, 27))
+		return w.String()
+	})
+
+	Register("13", func(r *rand.Rand, size int) string {
+		// При n ≤ 3 ровной перестановки может не быть; условие гарантирует её наличие
+		n := upTo(r, 4, size, 100000)
+		p := make([]int64, n)
+		for i, v := range r.Perm(n) {
+			p[i] = int64(v + 1)
+		}
+		var w writer
+		w.line(n)
+		w.ints(p)
+		return w.String()
+	})
+
+	Register("14", func(r *rand.Rand, size int) string {
+		l := between(r, -int64(size), int64(size))
+		var w writer
+		w.line(between(r, -1e9, 1e9), between(r, 0, int64(max(2, size))), l, l+between(r, 0, int64(size*size)))
+		return w.String()
+	})
+
+	Register("15", func(r *rand.Rand, size int) string {
+		t := upTo(r, 1, size, 500)
+		letters := 1 + r.Intn(3)
+		word := func() string {
+			b := make([]byte, upTo(r, 1, size, 1000/(2*t)))
+			for i := range b {
+				b[i] = byte('a' + r.Intn(letters))
+			}
+			return string(b)
+		}
+		var w writer
+		w.line(t)
+		for range t {
+			w.line(word())
+			w.line(word())
+		}
+		return w.String()
+	})
+
+	Register("16", func(r *rand.Rand, size int) string {
+		t := upTo(r, 1, max(1, size/4), 5000)
+		var w writer
+		w.line(t)
+		for range t {
+			n := upTo(r, 1, size, 3000)
+			q := upTo(r, 0, n, n)
+			l := between(r, 0, int64(n-1))
+			w.line(n, q, l, between(r, l, int64(n-1)))
+			w.ints(randInts(r, q, 1, int64(n)))
+			w.ints(randInts(r, q, 1, int64(n)))
+		}
+		return w.String()
+	})
+
+	Register("17", func(r *rand.Rand, size int) string {
+		var w writer
+		w.line(upTo(r, 1, size*size, 10000))
+		return w.String()
+	})
+
+	Register("18", func(r *rand.Rand, size int) string {
+		t := upTo(r, 1, max(1, size/8), 100)
+		var w writer
+		w.line(t)
+		for range t {
+			n := upTo(r, 1, size, 400/t)
+			m := upTo(r, 1, size, 100/t)
+			w.line(n, m)
+			w.ints(randInts(r, n, 0, int64(max(1, size*size))))
+			w.ints(randInts(r, m, 1, int64(max(1, size))))
+		}
+		return w.String()
+	})
+
+	Register("19", func(r *rand.Rand, size int) string {
+		m := upTo(r, 1, size, 200000)
+		a := randInts(r, m, 0, int64(max(1, size)))
+		a[r.Intn(m)]++ // N ≥ 1
+		var w writer
+		w.line(m)
+		w.ints(a)
+		return w.String()
+	})
+
+	Register("20", func(r *rand.Rand, size int) string {
+		t := upTo(r, 1, max(1, size/4), 10000)
+		var w writer
+		w.line(t)
+		for range t {
+			n := upTo(r, 1, size, 200000/t)
+			q := upTo(r, 1, size, 200000/t)
+			hi := int64(1<<30 - 1)
+			if r.Intn(2) == 0 {
+				hi = 15
+			}
+			w.line(n, q)
+			w.ints(randInts(r, n, 0, hi))
+			for range q {
+				w.line(between(r, 1, int64(n)), between(r, 0, hi))
+			}
+		}
+		return w.String()
+	})
+
+	Register("21", func(r *rand.Rand, size int) string {
+		t := upTo(r, 1, max(1, size/4), 100)
+		var w writer
+		w.line(t)
+		for range t {
+			n := upTo(r, 1, size, 10)
+			w.line(n)
+			for range n {
+				w.line(formatCoord(r), formatCoord(r))
+			}
+		}
+		return w.String()
+	})
+}
+
+// formatCoord возвращает координату с одним знаком после запятой, как в примерах 21/;
+// точки лежат в квадрате со стороной около 4, чтобы компоненты были связными
+func formatCoord(r *rand.Rand) string {
+	v := between(r, -20, 20)
+	sign := ""
+	if v < 0 {
+		sign, v = "-", -v
+	}
+	return fmt.Sprintf("%s%d.%d", sign, v/10, v%10)
+}
@@ -0,0 +1,140 @@
+package geom
+
+import (
+	"math"
+	"math/big"
+)
+
+// Границы погрешности вычислений в float64 (Shewchuk, «Adaptive Precision
+// Floating-Point Arithmetic and Fast Robust Geometric Predicates»): если
+// |значение| больше границы, знак совпадает с точным
+var (
+	epsilon      = math.Ldexp(1, -53)
+	orientBound  = (3 + 16*epsilon) * epsilon
+	incircleBase = (10 + 96*epsilon) * epsilon
+)
+
+// filterBound — относительная погрешность сумм из двух-трёх произведений
+// в CmpDist и dotSign с запасом: реальная не больше 6ε ≈ 6.7·10⁻¹⁶
+const filterBound = 1e-15
+
+// rat возвращает x как точное рациональное число
+func rat(x float64) *big.Rat {
+	return new(big.Rat).SetFloat64(x)
+}
+
+// sub, mul, add — операции big.Rat, возвращающие новое число
+func sub(a, b *big.Rat) *big.Rat { return new(big.Rat).Sub(a, b) }
+func mul(a, b *big.Rat) *big.Rat { return new(big.Rat).Mul(a, b) }
+func add(a, b *big.Rat) *big.Rat { return new(big.Rat).Add(a, b) }
+
+// Orient возвращает знак векторного произведения (b − a) × (c − a): +1,
+// если a, b, c идут против часовой стрелки, −1 — по часовой, 0 — на одной
+// прямой
+func Orient(a, b, c Point) int {
+	l := (a.X - c.X) * (b.Y - c.Y)
+	r := (a.Y - c.Y) * (b.X - c.X)
+	det := l - r
+	if math.Abs(det) > orientBound*(math.Abs(l)+math.Abs(r)) {
+		return sign(det)
+	}
+	ax, ay, cx, cy := rat(a.X), rat(a.Y), rat(c.X), rat(c.Y)
+	lr := mul(sub(ax, cx), sub(rat(b.Y), cy))
+	rr := mul(sub(ay, cy), sub(rat(b.X), cx))
+	return lr.Cmp(rr)
+}
+
+// InCircle возвращает знак определителя «d в окружности abc»: при a, b, c
+// против часовой стрелки +1 — d внутри, −1 — снаружи, 0 — на окружности;
+// при обходе по часовой знаки меняются местами
+func InCircle(a, b, c, d Point) int {
+	adx, ady := a.X-d.X, a.Y-d.Y
+	bdx, bdy := b.X-d.X, b.Y-d.Y
+	cdx, cdy := c.X-d.X, c.Y-d.Y
+	bc, cb := bdx*cdy, cdx*bdy
+	ca, ac := cdx*ady, adx*cdy
+	ab, ba := adx*bdy, bdx*ady
+	alift, blift, clift := adx*adx+ady*ady, bdx*bdx+bdy*bdy, cdx*cdx+cdy*cdy
+	det := alift*(bc-cb) + blift*(ca-ac) + clift*(ab-ba)
+	permanent := (math.Abs(bc)+math.Abs(cb))*alift +
+		(math.Abs(ca)+math.Abs(ac))*blift +
+		(math.Abs(ab)+math.Abs(ba))*clift
+	if math.Abs(det) > incircleBase*permanent {
+		return sign(det)
+	}
+
+	dx, dy := rat(d.X), rat(d.Y)
+	lift := func(p Point) (x, y, l *big.Rat) {
+		x, y = sub(rat(p.X), dx), sub(rat(p.Y), dy)
+		return x, y, add(mul(x, x), mul(y, y))
+	}
+	ax, ay, al := lift(a)
+	bx, by, bl := lift(b)
+	cx, cy, cl := lift(c)
+	res := mul(al, sub(mul(bx, cy), mul(cx, by)))
+	res = add(res, mul(bl, sub(mul(cx, ay), mul(ax, cy))))
+	res = add(res, mul(cl, sub(mul(ax, by), mul(bx, ay))))
+	return res.Sign()
+}
+
+// cmpDistRadii сравнивает |pq| с |r1 + s·r2| (s = ±1) точно: знак
+// |pq|² − (r1 + s·r2)²
+func cmpDistRadii(p, q Point, r1, r2 float64, s int) int {
+	d2 := p.Dist2(q)
+	r := r1 + float64(s)*r2
+	diff := d2 - r*r
+	scale := math.Abs(r1) + math.Abs(r2)
+	if math.Abs(diff) > filterBound*(d2+scale*scale) {
+		return sign(diff)
+	}
+	dx, dy := sub(rat(p.X), rat(q.X)), sub(rat(p.Y), rat(q.Y))
+	dist2 := add(mul(dx, dx), mul(dy, dy))
+	rr := rat(r1)
+	if s > 0 {
+		rr = add(rr, rat(r2))
+	} else {
+		rr = sub(rr, rat(r2))
+	}
+	return dist2.Cmp(mul(rr, rr))
+}
+
+// dotSign возвращает знак (a − q)·(b − q)
+func dotSign(a, b, q Point) int {
+	t1 := (a.X - q.X) * (b.X - q.X)
+	t2 := (a.Y - q.Y) * (b.Y - q.Y)
+	if v := t1 + t2; math.Abs(v) > filterBound*(math.Abs(t1)+math.Abs(t2)) {
+		return sign(v)
+	}
+	qx, qy := rat(q.X), rat(q.Y)
+	r1 := mul(sub(rat(a.X), qx), sub(rat(b.X), qx))
+	r2 := mul(sub(rat(a.Y), qy), sub(rat(b.Y), qy))
+	return add(r1, r2).Sign()
+}
+
+// circumradiusAtMost сообщает, что радиус окружности через a, b, c не
+// больше r: R = |ab|·|bc|·|ca| / (2·|(b − a) × (c − a)|), то есть
+// |ab|²·|bc|²·|ca|² ≤ 4r²·((b − a) × (c − a))². Точки не на одной прямой
+func circumradiusAtMost(a, b, c Point, r float64) bool {
+	ax, ay := rat(a.X), rat(a.Y)
+	bx, by := rat(b.X), rat(b.Y)
+	cx, cy := rat(c.X), rat(c.Y)
+	dist2 := func(px, py, qx, qy *big.Rat) *big.Rat {
+		dx, dy := sub(px, qx), sub(py, qy)
+		return add(mul(dx, dx), mul(dy, dy))
+	}
+	lhs := mul(mul(dist2(ax, ay, bx, by), dist2(bx, by, cx, cy)), dist2(cx, cy, ax, ay))
+	cross := sub(mul(sub(bx, ax), sub(cy, ay)), mul(sub(by, ay), sub(cx, ax)))
+	rr := rat(r)
+	rhs := mul(mul(big.NewRat(4, 1), mul(rr, rr)), mul(cross, cross))
+	return lhs.Cmp(rhs) <= 0
+}
+
+func sign(x float64) int {
+	switch {
+	case x > 0:
+		return 1
+	case x < 0:
+		return -1
+	}
+	return 0
+}
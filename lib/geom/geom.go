@@ -0,0 +1,213 @@
+// Package geom — геометрия на плоскости с точными предикатами.
+//
+// Координаты — float64, но решения «по какую сторону», «внутри или снаружи»
+// и «касаются ли» принимаются точно, как если бы вычисления шли в
+// рациональных числах: сначала считается значение в float64 с оценкой
+// погрешности, и только если знак не гарантирован, выражение
+// пересчитывается в big.Rat. Поэтому вырожденные случаи — три точки на
+// одной прямой, четыре на одной окружности, касание окружностей — видны
+// как нули, а не как «почти ноль» с подобранным эпсилоном:
+//
+//	geom.Orient(a, b, c)        // +1 — против часовой, 0 — на одной прямой
+//	geom.CmpDist(p, q, 2)       // −1, 0, +1: |pq| меньше, равно, больше 2
+//	geom.Intersect(c1, c2)      // касание — ровно одна точка
+//	geom.FitsInCircle(pts, 1)   // помещаются ли точки в круг радиуса 1
+//
+// Построенные точки (пересечения, центры) — float64 и точными не
+// являются; точны только решения о входных данных.
+package geom
+
+import (
+	"math"
+	"math/rand/v2"
+)
+
+// Point — точка или вектор на плоскости
+type Point struct {
+	X, Y float64
+}
+
+// Add возвращает p + q
+func (p Point) Add(q Point) Point {
+	return Point{p.X + q.X, p.Y + q.Y}
+}
+
+// Sub возвращает p − q
+func (p Point) Sub(q Point) Point {
+	return Point{p.X - q.X, p.Y - q.Y}
+}
+
+// Scale возвращает k·p
+func (p Point) Scale(k float64) Point {
+	return Point{k * p.X, k * p.Y}
+}
+
+// Dot возвращает скалярное произведение
+func (p Point) Dot(q Point) float64 {
+	return p.X*q.X + p.Y*q.Y
+}
+
+// Cross возвращает векторное произведение p × q
+func (p Point) Cross(q Point) float64 {
+	return p.X*q.Y - p.Y*q.X
+}
+
+// Dist2 возвращает квадрат расстояния до q
+func (p Point) Dist2(q Point) float64 {
+	dx, dy := p.X-q.X, p.Y-q.Y
+	return dx*dx + dy*dy
+}
+
+// Dist возвращает расстояние до q
+func (p Point) Dist(q Point) float64 {
+	return math.Sqrt(p.Dist2(q))
+}
+
+// Circle — окружность (или круг) с центром C и радиусом R ≥ 0
+type Circle struct {
+	C Point
+	R float64
+}
+
+// Contains сообщает, лежит ли p в замкнутом круге; граница решается точно
+func (c Circle) Contains(p Point) bool {
+	return CmpDist(p, c.C, c.R) <= 0
+}
+
+// CmpDist сравнивает расстояние |pq| с r ≥ 0 точно: −1, если меньше, 0,
+// если равно, и +1, если больше
+func CmpDist(p, q Point, r float64) int {
+	return cmpDistRadii(p, q, r, 0, 1)
+}
+
+// Intersect возвращает точки пересечения окружностей a и b: ни одной,
+// одну при касании (внешнем или внутреннем) или две. Число точек решается
+// точно, сами точки считаются в float64. Совпадающие окружности дают nil
+func Intersect(a, b Circle) []Point {
+	if a.C == b.C {
+		return nil // концентрические: нет общих точек или все общие
+	}
+	outer := cmpDistRadii(a.C, b.C, a.R, b.R, 1)  // |AB| против R1 + R2
+	inner := cmpDistRadii(a.C, b.C, a.R, b.R, -1) // |AB| против |R1 − R2|
+	if outer > 0 || inner < 0 {
+		return nil
+	}
+	d2 := a.C.Dist2(b.C)
+	d := math.Sqrt(d2)
+	dir := b.C.Sub(a.C).Scale(1 / d)
+	// Проекция точек пересечения на AB и половина хорды
+	along := (a.R*a.R - b.R*b.R + d2) / (2 * d)
+	mid := a.C.Add(dir.Scale(along))
+	if outer == 0 || inner == 0 {
+		return []Point{mid}
+	}
+	h := math.Sqrt(max(0, a.R*a.R-along*along))
+	normal := Point{dir.Y, -dir.X}.Scale(h)
+	return []Point{mid.Add(normal), mid.Sub(normal)}
+}
+
+// Circumcenter возвращает центр окружности через a, b, c; ok = false,
+// если точки на одной прямой (решается точно)
+func Circumcenter(a, b, c Point) (center Point, ok bool) {
+	if Orient(a, b, c) == 0 {
+		return Point{}, false
+	}
+	// Относительно a: меньше потеря точности при далёких от нуля координатах
+	b, c = b.Sub(a), c.Sub(a)
+	d := 2 * b.Cross(c)
+	b2, c2 := b.Dot(b), c.Dot(c)
+	return Point{a.X + (c.Y*b2-b.Y*c2)/d, a.Y + (b.X*c2-c.X*b2)/d}, true
+}
+
+// support — опорные точки минимального круга: 0–3 точки на его границе,
+// которые его задают
+type support struct {
+	p [3]Point
+	n int
+}
+
+// outside сообщает, лежит ли q строго вне круга, заданного опорными точками
+func (s *support) outside(q Point) bool {
+	switch s.n {
+	case 0:
+		return true
+	case 1:
+		return q != s.p[0]
+	case 2:
+		// Вне круга на диаметре ab ⟺ угол aqb острый: (a − q)·(b − q) > 0
+		return dotSign(s.p[0], s.p[1], q) > 0
+	default:
+		return InCircle(s.p[0], s.p[1], s.p[2], q)*Orient(s.p[0], s.p[1], s.p[2]) < 0
+	}
+}
+
+// circle возвращает круг по опорным точкам; радиус — наибольшее
+// расстояние до них, чтобы опорные точки после округления были внутри
+func (s *support) circle() Circle {
+	var c Point
+	switch s.n {
+	case 0:
+		return Circle{}
+	case 1:
+		c = s.p[0]
+	case 2:
+		c = s.p[0].Add(s.p[1]).Scale(0.5)
+	default:
+		c, _ = Circumcenter(s.p[0], s.p[1], s.p[2])
+	}
+	var r float64
+	for _, p := range s.p[:s.n] {
+		r = max(r, c.Dist(p))
+	}
+	return Circle{c, r}
+}
+
+// welzl возвращает опорные точки минимального покрывающего круга:
+// итеративный алгоритм Вельцля на перемешанных точках, ожидаемо O(n).
+// Порядок перемешивания фиксирован, ответ детерминирован
+func welzl(pts []Point) support {
+	p := append([]Point(nil), pts...)
+	r := rand.New(rand.NewPCG(1, 2))
+	r.Shuffle(len(p), func(i, j int) { p[i], p[j] = p[j], p[i] })
+	var s support
+	for i := range p {
+		if !s.outside(p[i]) {
+			continue
+		}
+		s = support{p: [3]Point{p[i]}, n: 1}
+		for j := range i {
+			if !s.outside(p[j]) {
+				continue
+			}
+			s = support{p: [3]Point{p[i], p[j]}, n: 2}
+			for k := range j {
+				if s.outside(p[k]) {
+					s = support{p: [3]Point{p[i], p[j], p[k]}, n: 3}
+				}
+			}
+		}
+	}
+	return s
+}
+
+// MinEnclosingCircle возвращает наименьший круг, содержащий все точки,
+// за ожидаемое O(n). Какие точки задают круг, решается точно; центр и
+// радиус — float64. Для пустого среза — нулевой круг
+func MinEnclosingCircle(pts []Point) Circle {
+	s := welzl(pts)
+	return s.circle()
+}
+
+// FitsInCircle сообщает, помещаются ли точки в круг радиуса r ≥ 0, —
+// точно, без округления радиуса минимального круга
+func FitsInCircle(pts []Point, r float64) bool {
+	s := welzl(pts)
+	switch s.n {
+	case 0, 1:
+		return r >= 0
+	case 2:
+		return CmpDist(s.p[0], s.p[1], 2*r) <= 0 // 2r точно: умножение на 2
+	default:
+		return circumradiusAtMost(s.p[0], s.p[1], s.p[2], r)
+	}
+}
@@ -0,0 +1,259 @@
+package geom
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+// orientRat и inCircleRat — те же определители целиком в big.Rat
+func orientRat(a, b, c Point) int {
+	l := mul(sub(rat(b.X), rat(a.X)), sub(rat(c.Y), rat(a.Y)))
+	r := mul(sub(rat(b.Y), rat(a.Y)), sub(rat(c.X), rat(a.X)))
+	return l.Cmp(r)
+}
+
+func inCircleRat(a, b, c, d Point) int {
+	row := func(p Point) [3]*big.Rat {
+		x, y := sub(rat(p.X), rat(d.X)), sub(rat(p.Y), rat(d.Y))
+		return [3]*big.Rat{x, y, add(mul(x, x), mul(y, y))}
+	}
+	m := [3][3]*big.Rat{row(a), row(b), row(c)}
+	det := new(big.Rat)
+	for i := range 3 {
+		j, k := (i+1)%3, (i+2)%3
+		minor := sub(mul(m[1][j], m[2][k]), mul(m[1][k], m[2][j]))
+		det = add(det, mul(m[0][i], minor))
+	}
+	return det.Sign()
+}
+
+// nearDegenerate возвращает точку у прямой y = x с шагом в несколько ulp:
+// здесь float64-определитель без проверки ошибается в знаке
+func nearDegenerate(r *rand.Rand) Point {
+	const ulp = 1.0 / (1 << 53)
+	return Point{0.5 + float64(r.Intn(256))*ulp, 0.5 + float64(r.Intn(256))*ulp}
+}
+
+func TestOrient(t *testing.T) {
+	b, c := Point{12, 12}, Point{24, 24}
+	r := rand.New(rand.NewSource(1))
+	zeros := 0
+	for range 20000 {
+		a := nearDegenerate(r)
+		got, want := Orient(a, b, c), orientRat(a, b, c)
+		if got != want {
+			t.Fatalf("Orient(%v, %v, %v) = %d, ожидалось %d", a, b, c, got, want)
+		}
+		if got == 0 {
+			zeros++
+		}
+	}
+	if zeros == 0 {
+		t.Error("ни одной точки точно на прямой: вырожденный случай не проверен")
+	}
+	for range 20000 {
+		p := [3]Point{}
+		for i := range p {
+			p[i] = Point{r.NormFloat64(), r.NormFloat64()}
+		}
+		if got, want := Orient(p[0], p[1], p[2]), orientRat(p[0], p[1], p[2]); got != want {
+			t.Fatalf("Orient%v = %d, ожидалось %d", p, got, want)
+		}
+	}
+}
+
+func TestInCircle(t *testing.T) {
+	a, b, c := Point{1, 0}, Point{0, 1}, Point{-1, 0}
+	for _, tt := range []struct {
+		d    Point
+		want int
+	}{
+		{Point{0, -1}, 0},
+		{Point{0, 0}, 1},
+		{Point{2, 2}, -1},
+		{Point{math.Nextafter(0, 1), -1}, -1}, // на ulp правее точки окружности
+	} {
+		if got := InCircle(a, b, c, tt.d); got != tt.want {
+			t.Errorf("InCircle(..., %v) = %d, ожидалось %d", tt.d, got, tt.want)
+		}
+		if got := InCircle(c, b, a, tt.d); got != -tt.want {
+			t.Errorf("InCircle по часовой (..., %v) = %d, ожидалось %d", tt.d, got, -tt.want)
+		}
+	}
+	r := rand.New(rand.NewSource(2))
+	for range 20000 {
+		p := [4]Point{}
+		for i := range p {
+			p[i] = nearDegenerate(r)
+			if i < 2 {
+				p[i] = Point{float64(r.Intn(5)), float64(r.Intn(5))}
+			}
+		}
+		if got, want := InCircle(p[0], p[1], p[2], p[3]), inCircleRat(p[0], p[1], p[2], p[3]); got != want {
+			t.Fatalf("InCircle%v = %d, ожидалось %d", p, got, want)
+		}
+	}
+}
+
+func TestCmpDist(t *testing.T) {
+	for _, tt := range []struct {
+		p, q Point
+		r    float64
+		want int
+	}{
+		{Point{0, 0}, Point{3, 4}, 5, 0},
+		{Point{0, 0}, Point{3, 4}, math.Nextafter(5, 6), -1},
+		{Point{0, 0}, Point{3, 4}, math.Nextafter(5, 4), 1},
+		{Point{1e-300, 0}, Point{0, 0}, 0, 1},
+		{Point{0.1, 0}, Point{0.3, 0}, 0.2, -1}, // точная разность чисел 0.3 и 0.1 меньше числа 0.2
+	} {
+		if got := CmpDist(tt.p, tt.q, tt.r); got != tt.want {
+			t.Errorf("CmpDist(%v, %v, %v) = %d, ожидалось %d", tt.p, tt.q, tt.r, got, tt.want)
+		}
+	}
+	r := rand.New(rand.NewSource(3))
+	for range 20000 {
+		p, q := nearDegenerate(r), nearDegenerate(r)
+		rad := p.Dist(q) * (1 + float64(r.Intn(5)-2)*epsilon)
+		if got, want := CmpDist(p, q, rad), orientSignDist(p, q, rad); got != want {
+			t.Fatalf("CmpDist(%v, %v, %v) = %d, ожидалось %d", p, q, rad, got, want)
+		}
+	}
+}
+
+// orientSignDist сравнивает |pq| с r в big.Rat
+func orientSignDist(p, q Point, r float64) int {
+	dx, dy := sub(rat(p.X), rat(q.X)), sub(rat(p.Y), rat(q.Y))
+	return add(mul(dx, dx), mul(dy, dy)).Cmp(mul(rat(r), rat(r)))
+}
+
+func TestIntersect(t *testing.T) {
+	for _, tt := range []struct {
+		a, b Circle
+		want int
+	}{
+		{Circle{Point{0, 0}, 1}, Circle{Point{3, 0}, 2}, 1},                       // внешнее касание
+		{Circle{Point{0, 0}, 3}, Circle{Point{1, 0}, 2}, 1},                       // внутреннее касание
+		{Circle{Point{0, 0}, 1}, Circle{Point{2, 0}, 2}, 2},                       // пересечение
+		{Circle{Point{0, 0}, 1}, Circle{Point{3.5, 0}, 2}, 0},                     // далеко
+		{Circle{Point{0, 0}, 3}, Circle{Point{0.5, 0}, 1}, 0},                     // внутри
+		{Circle{Point{0, 0}, 1}, Circle{Point{0, 0}, 1}, 0},                       // совпадают
+		{Circle{Point{0.1, 0.2}, 1}, Circle{Point{2.1, 0.2}, 1}, 0},               // 2.1 − 0.1 в float64 чуть больше 2
+		{Circle{Point{0.5, 0.25}, 0.3125}, Circle{Point{0.875, 0.75}, 0.3125}, 1}, // 3-4-5 с двоичными дробями
+	} {
+		pts := Intersect(tt.a, tt.b)
+		if len(pts) != tt.want {
+			t.Errorf("Intersect(%v, %v): %d точек, ожидалось %d", tt.a, tt.b, len(pts), tt.want)
+		}
+		for _, p := range pts {
+			if math.Abs(p.Dist(tt.a.C)-tt.a.R) > 1e-12 || math.Abs(p.Dist(tt.b.C)-tt.b.R) > 1e-12 {
+				t.Errorf("Intersect(%v, %v): точка %v не на окружностях", tt.a, tt.b, p)
+			}
+		}
+	}
+}
+
+func TestCircumcenter(t *testing.T) {
+	if c, ok := Circumcenter(Point{1, 0}, Point{0, 1}, Point{-1, 0}); !ok || c.Dist(Point{}) > 1e-15 {
+		t.Errorf("Circumcenter = %v, %v; ожидалось (0, 0)", c, ok)
+	}
+	if _, ok := Circumcenter(Point{0.1, 0.1}, Point{0.2, 0.2}, Point{0.3, 0.3}); ok == (Orient(Point{0.1, 0.1}, Point{0.2, 0.2}, Point{0.3, 0.3}) == 0) {
+		t.Error("Circumcenter не согласован с Orient")
+	}
+	if _, ok := Circumcenter(Point{0, 0}, Point{1, 1}, Point{2, 2}); ok {
+		t.Error("Circumcenter точек на прямой")
+	}
+}
+
+// bruteMEC перебирает круги на парах и тройках точек
+func bruteMEC(pts []Point) float64 {
+	best := math.Inf(1)
+	try := func(c Point) {
+		r := 0.0
+		for _, p := range pts {
+			r = max(r, c.Dist(p))
+		}
+		best = min(best, r)
+	}
+	for i := range pts {
+		try(pts[i])
+		for j := range i {
+			try(pts[i].Add(pts[j]).Scale(0.5))
+			for k := range j {
+				if c, ok := Circumcenter(pts[i], pts[j], pts[k]); ok {
+					try(c)
+				}
+			}
+		}
+	}
+	return best
+}
+
+func TestMinEnclosingCircle(t *testing.T) {
+	r := rand.New(rand.NewSource(4))
+	for range 500 {
+		pts := make([]Point, 1+r.Intn(12))
+		for i := range pts {
+			pts[i] = Point{float64(r.Intn(7)), float64(r.Intn(7))} // совпадения и прямые
+			if r.Intn(2) == 0 {
+				pts[i] = Point{r.Float64() * 6, r.Float64() * 6}
+			}
+		}
+		c := MinEnclosingCircle(pts)
+		if want := bruteMEC(pts); math.Abs(c.R-want) > 1e-9 {
+			t.Fatalf("%v: радиус %v, ожидалось %v", pts, c.R, want)
+		}
+		for _, p := range pts {
+			if p.Dist(c.C) > c.R*(1+1e-12) {
+				t.Fatalf("%v: точка %v вне круга %v", pts, p, c)
+			}
+		}
+		if !FitsInCircle(pts, c.R*(1+1e-9)) || c.R > 1e-6 && FitsInCircle(pts, c.R*(1-1e-9)) {
+			t.Fatalf("%v: FitsInCircle не согласован с радиусом %v", pts, c.R)
+		}
+	}
+	if c := MinEnclosingCircle(nil); c != (Circle{}) {
+		t.Errorf("MinEnclosingCircle(nil) = %v", c)
+	}
+}
+
+func TestFitsInCircle(t *testing.T) {
+	// Диаметр ровно 2 и треугольник с радиусом описанной окружности ровно 5
+	if !FitsInCircle([]Point{{-1, 0}, {1, 0}, {0, 0.5}}, 1) {
+		t.Error("отрезок длины 2 не поместился в круг радиуса 1")
+	}
+	tri5 := []Point{{0, 5}, {3, -4}, {-3, -4}}
+	if !FitsInCircle(tri5, 5) || FitsInCircle(tri5, math.Nextafter(5, 0)) {
+		t.Error("треугольник, вписанный в окружность радиуса 5: граница решена неточно")
+	}
+	// Правильный треугольник с радиусом 1 + 10⁻¹⁰: float64-радиус с
+	// эпсилоном 10⁻⁹ принял бы его
+	l := (1 + 1e-10) * math.Sqrt(3)
+	tri := []Point{{0, 0}, {l, 0}, {l / 2, l * math.Sqrt(3) / 2}}
+	if FitsInCircle(tri, 1) {
+		t.Error("треугольник с радиусом 1 + 10⁻¹⁰ поместился в круг радиуса 1")
+	}
+	if !FitsInCircle(tri, 1+2e-10) {
+		t.Error("треугольник не поместился в круг радиуса 1 + 2·10⁻¹⁰")
+	}
+}
+
+func BenchmarkMinEnclosingCircle(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	pts := make([]Point, 100000)
+	for i := range pts {
+		pts[i] = Point{r.NormFloat64(), r.NormFloat64()}
+	}
+	for b.Loop() {
+		MinEnclosingCircle(pts)
+	}
+}
+
+func BenchmarkOrient(b *testing.B) {
+	a, c, d := Point{0.1, 0.2}, Point{12, 12.5}, Point{24, 23}
+	for b.Loop() {
+		Orient(a, c, d)
+	}
+}
@@ -0,0 +1,276 @@
+// Package limits — встроенная проверка ограничений времени и памяти для решений.
+//
+// Проверка включается переменной окружения CHECK_LIMITS; без неё Run просто
+// вызывает функцию, поэтому код проверки можно оставлять в отправляемом решении.
+// Значение переменной выбирает способ измерения памяти: "rss" — пиковый RSS
+// процесса (VmHWM), "alloc" — выделения кучи за время fn (TotalAlloc), любое
+// другое — RSS, если доступен /proc/self/status, иначе alloc.
+// Для отправки в Coderun main.go собирается в один файл командой
+// `go run ./cmd/coderun bundle NN`.
+package limits
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"runtime/metrics"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EnvVar — переменная окружения, включающая проверку ограничений
+const EnvVar = "CHECK_LIMITS"
+
+// sampleInterval — период опроса размера кучи во время выполнения
+const sampleInterval = time.Millisecond
+
+// Mode — способ измерения памяти, которая сравнивается с лимитом
+type Mode int
+
+const (
+	// ModeAuto выбирает ModeRSS, если доступен /proc/self/status, иначе ModeAlloc
+	ModeAuto Mode = iota
+	// ModeRSS — пиковый RSS всего процесса (VmHWM): статические данные,
+	// стеки горутин, удерживаемая рантаймом куча — то же, что видит судья
+	ModeRSS
+	// ModeAlloc — суммарные выделения кучи за время fn (TotalAlloc);
+	// не видит глобальные массивы и завышает память на короткоживущем мусоре
+	ModeAlloc
+)
+
+// String возвращает значение CHECK_LIMITS, выбирающее режим
+func (m Mode) String() string {
+	switch m {
+	case ModeRSS:
+		return "rss"
+	case ModeAlloc:
+		return "alloc"
+	default:
+		return "auto"
+	}
+}
+
+// ModeFromEnv возвращает режим, заданный значением CHECK_LIMITS
+func ModeFromEnv() Mode {
+	switch strings.ToLower(os.Getenv(EnvVar)) {
+	case "rss":
+		return ModeRSS
+	case "alloc":
+		return ModeAlloc
+	default:
+		return ModeAuto
+	}
+}
+
+// Verdict — итог проверки ограничений (битовая маска превышений)
+type Verdict int
+
+const (
+	OK             Verdict = 0
+	TimeExceeded   Verdict = 1 << 0
+	MemoryExceeded Verdict = 1 << 1
+)
+
+// String возвращает вердикт в обозначениях Coderun
+func (v Verdict) String() string {
+	switch v {
+	case OK:
+		return "OK"
+	case TimeExceeded:
+		return "TL"
+	case MemoryExceeded:
+		return "ML"
+	default:
+		return "TL+ML"
+	}
+}
+
+// Result — результат измерения одного запуска
+type Result struct {
+	Elapsed     time.Duration // время выполнения
+	Allocated   uint64        // суммарно выделено байт (TotalAlloc)
+	PeakHeap    uint64        // пиковый объём живых объектов кучи в байтах
+	PeakRSS     uint64        // пиковый RSS процесса в байтах (0, если недоступен)
+	Mode        Mode          // чем измерялась память: ModeRSS или ModeAlloc
+	MaxTime     time.Duration // лимит времени
+	MaxMemoryMB int           // лимит памяти в МБ
+	Verdict     Verdict
+}
+
+// OK сообщает, уложился ли запуск в оба ограничения
+func (r Result) OK() bool {
+	return r.Verdict == OK
+}
+
+// Memory возвращает память в байтах, которая сравнивается с лимитом
+func (r Result) Memory() uint64 {
+	if r.Mode == ModeRSS {
+		return r.PeakRSS
+	}
+	return r.Allocated
+}
+
+// MemoryMB возвращает Memory в МБ
+func (r Result) MemoryMB() float64 {
+	return float64(r.Memory()) / (1024 * 1024)
+}
+
+// Report печатает результат в формате прежних checkLimits
+func (r Result) Report(w io.Writer) {
+	if r.OK() {
+		fmt.Fprintf(w, "✓ Время: %v, Память: %.2f МБ (%s; выделено: %.2f МБ, пик кучи: %.2f МБ)\n",
+			r.Elapsed, r.MemoryMB(), r.Mode, float64(r.Allocated)/(1024*1024), float64(r.PeakHeap)/(1024*1024))
+		return
+	}
+	if r.Verdict&TimeExceeded != 0 {
+		fmt.Fprintf(w, "⚠️ Превышено время: %v (лимит: %v)\n", r.Elapsed, r.MaxTime)
+	}
+	if r.Verdict&MemoryExceeded != 0 {
+		fmt.Fprintf(w, "⚠️ Превышена память: %.2f МБ (%s, лимит: %d МБ)\n", r.MemoryMB(), r.Mode, r.MaxMemoryMB)
+	}
+}
+
+// Enabled сообщает, установлена ли переменная окружения CHECK_LIMITS
+func Enabled() bool {
+	return os.Getenv(EnvVar) != ""
+}
+
+// Run выполняет fn и, если установлена CHECK_LIMITS, печатает результат проверки в stderr
+func Run(maxTime time.Duration, maxMemoryMB int, fn func()) {
+	if !Enabled() {
+		fn()
+		return
+	}
+	MeasureMode(ModeFromEnv(), maxTime, maxMemoryMB, fn).Report(os.Stderr)
+}
+
+// Measure выполняет fn и возвращает результат проверки в режиме ModeAuto
+// независимо от CHECK_LIMITS
+func Measure(maxTime time.Duration, maxMemoryMB int, fn func()) Result {
+	return MeasureMode(ModeAuto, maxTime, maxMemoryMB, fn)
+}
+
+// MeasureMode выполняет fn и возвращает результат проверки в заданном режиме.
+// В ModeRSS память — пик RSS процесса с момента запуска, а не только fn:
+// судья тоже считает чтение ввода и статические данные.
+func MeasureMode(mode Mode, maxTime time.Duration, maxMemoryMB int, fn func()) Result {
+	var m1, m2 runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&m1)
+
+	sampler := startHeapSampler()
+	start := time.Now()
+	fn()
+	elapsed := time.Since(start)
+	peak := sampler.stop()
+
+	runtime.GC()
+	runtime.ReadMemStats(&m2)
+
+	r := Result{
+		Elapsed:     elapsed,
+		Allocated:   m2.TotalAlloc - m1.TotalAlloc,
+		PeakHeap:    peak,
+		MaxTime:     maxTime,
+		MaxMemoryMB: maxMemoryMB,
+		Mode:        ModeAlloc,
+	}
+	if rss, ok := PeakRSS(); ok {
+		r.PeakRSS = rss
+		if mode != ModeAlloc {
+			r.Mode = ModeRSS
+		}
+	}
+	if r.Elapsed > maxTime {
+		r.Verdict |= TimeExceeded
+	}
+	if r.Memory() > uint64(maxMemoryMB)*1024*1024 {
+		r.Verdict |= MemoryExceeded
+	}
+	return r
+}
+
+// heapSampler в фоне опрашивает размер живых объектов кучи и запоминает максимум
+type heapSampler struct {
+	done chan struct{}
+	wg   sync.WaitGroup
+	peak uint64
+}
+
+const heapObjectsMetric = "/memory/classes/heap/objects:bytes"
+
+func readHeapObjects(sample []metrics.Sample) uint64 {
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return sample[0].Value.Uint64()
+}
+
+func startHeapSampler() *heapSampler {
+	s := &heapSampler{done: make(chan struct{})}
+	sample := []metrics.Sample{{Name: heapObjectsMetric}}
+	s.peak = readHeapObjects(sample)
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(sampleInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.done:
+				return
+			case <-ticker.C:
+				s.peak = max(s.peak, readHeapObjects(sample))
+			}
+		}
+	}()
+	return s
+}
+
+// stop останавливает опрос и возвращает пик с учётом последнего замера
+func (s *heapSampler) stop() uint64 {
+	close(s.done)
+	s.wg.Wait()
+	return max(s.peak, readHeapObjects([]metrics.Sample{{Name: heapObjectsMetric}}))
+}
+
+// PeakRSS возвращает пиковый RSS процесса (VmHWM из /proc/self/status) в байтах.
+// ok == false, если /proc недоступен (не Linux).
+func PeakRSS() (uint64, bool) {
+	return readStatus("/proc/self/status", "VmHWM")
+}
+
+// readStatus читает поле вида "VmHWM:   1234 kB" и возвращает его в байтах
+func readStatus(path, field string) (uint64, bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, false
+	}
+	defer f.Close()
+	return parseStatus(f, field)
+}
+
+func parseStatus(r io.Reader, field string) (uint64, bool) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		rest, ok := strings.CutPrefix(scanner.Text(), field+":")
+		if !ok {
+			continue
+		}
+		parts := strings.Fields(rest)
+		if len(parts) != 2 || parts[1] != "kB" {
+			return 0, false
+		}
+		kb, err := strconv.ParseUint(parts[0], 10, 64)
+		if err != nil {
+			return 0, false
+		}
+		return kb * 1024, true
+	}
+	return 0, false
+}
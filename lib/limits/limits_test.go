@@ -0,0 +1,199 @@
+package limits
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+var sink []byte
+
+func TestMeasure(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxTime     time.Duration
+		maxMemoryMB int
+		fn          func()
+		verdict     Verdict
+	}{
+		{
+			name:        "укладывается в лимиты",
+			maxTime:     time.Second,
+			maxMemoryMB: 64,
+			fn:          func() { sink = make([]byte, 1<<20) },
+			verdict:     OK,
+		},
+		{
+			name:        "превышено время",
+			maxTime:     time.Millisecond,
+			maxMemoryMB: 64,
+			fn:          func() { time.Sleep(20 * time.Millisecond) },
+			verdict:     TimeExceeded,
+		},
+		{
+			name:        "превышена память",
+			maxTime:     time.Second,
+			maxMemoryMB: 1,
+			fn:          func() { sink = make([]byte, 4<<20) },
+			verdict:     MemoryExceeded,
+		},
+		{
+			name:        "превышено всё",
+			maxTime:     time.Millisecond,
+			maxMemoryMB: 1,
+			fn: func() {
+				sink = make([]byte, 4<<20)
+				time.Sleep(20 * time.Millisecond)
+			},
+			verdict: TimeExceeded | MemoryExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := MeasureMode(ModeAlloc, tt.maxTime, tt.maxMemoryMB, tt.fn)
+			if r.Verdict != tt.verdict {
+				t.Errorf("Verdict = %v, ожидалось %v (результат %+v)", r.Verdict, tt.verdict, r)
+			}
+			if r.OK() != (tt.verdict == OK) {
+				t.Errorf("OK() = %v при вердикте %v", r.OK(), r.Verdict)
+			}
+			sink = nil
+		})
+	}
+}
+
+func TestMeasurePeakHeap(t *testing.T) {
+	const size = 32 << 20
+	r := Measure(time.Second, 256, func() {
+		buf := make([]byte, size)
+		buf[len(buf)-1] = 1
+		// Держим буфер живым, пока его не увидит фоновый опрос
+		time.Sleep(10 * sampleInterval)
+		sink = buf
+	})
+	sink = nil
+
+	if r.PeakHeap < size {
+		t.Errorf("PeakHeap = %d, ожидалось не меньше %d", r.PeakHeap, size)
+	}
+	if r.Allocated < size {
+		t.Errorf("Allocated = %d, ожидалось не меньше %d", r.Allocated, size)
+	}
+}
+
+// static — глобальный массив, как таблицы в 16/ и 20/: TotalAlloc его не видит
+var static [48 << 20]byte
+
+func TestMeasureRSS(t *testing.T) {
+	before, ok := PeakRSS()
+	if !ok {
+		t.Skip("/proc/self/status недоступен")
+	}
+
+	touch := func() {
+		for i := 0; i < len(static); i += 4096 {
+			static[i] = 1
+		}
+	}
+	limitMB := int(before>>20) + 16
+
+	r := MeasureMode(ModeRSS, time.Second, limitMB, touch)
+	if r.Mode != ModeRSS {
+		t.Fatalf("Mode = %v, ожидался rss", r.Mode)
+	}
+	if r.PeakRSS < before+uint64(len(static))*3/4 {
+		t.Errorf("PeakRSS = %d, ожидалось не меньше %d + размер массива", r.PeakRSS, before)
+	}
+	if r.Verdict != MemoryExceeded {
+		t.Errorf("Verdict = %v, ожидался ML: пик %.2f МБ при лимите %d МБ", r.Verdict, r.MemoryMB(), limitMB)
+	}
+
+	// Тот же запуск в режиме alloc не замечает глобальный массив
+	r = MeasureMode(ModeAlloc, time.Second, limitMB, touch)
+	if r.Mode != ModeAlloc || r.Verdict != OK {
+		t.Errorf("alloc: Mode = %v, Verdict = %v, ожидалось alloc и OK", r.Mode, r.Verdict)
+	}
+}
+
+func TestParseStatus(t *testing.T) {
+	status := "Name:\tlimits.test\nVmPeak:\t  123456 kB\nVmHWM:\t   20480 kB\nVmRSS:\t   10240 kB\n"
+	tests := []struct {
+		field string
+		want  uint64
+		ok    bool
+	}{
+		{"VmHWM", 20480 * 1024, true},
+		{"VmRSS", 10240 * 1024, true},
+		{"VmSwap", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseStatus(strings.NewReader(status), tt.field)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseStatus(%s) = %d, %v, ожидалось %d, %v", tt.field, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestModeFromEnv(t *testing.T) {
+	tests := []struct {
+		env  string
+		want Mode
+	}{
+		{"1", ModeAuto},
+		{"rss", ModeRSS},
+		{"RSS", ModeRSS},
+		{"alloc", ModeAlloc},
+	}
+	for _, tt := range tests {
+		t.Setenv(EnvVar, tt.env)
+		if got := ModeFromEnv(); got != tt.want {
+			t.Errorf("CHECK_LIMITS=%s: ModeFromEnv() = %v, ожидалось %v", tt.env, got, tt.want)
+		}
+	}
+}
+
+func TestReport(t *testing.T) {
+	tests := []struct {
+		name    string
+		verdict Verdict
+		want    []string
+		notWant []string
+	}{
+		{"OK", OK, []string{"✓ Время"}, []string{"⚠️"}},
+		{"TL", TimeExceeded, []string{"Превышено время"}, []string{"✓", "Превышена память"}},
+		{"ML", MemoryExceeded, []string{"Превышена память"}, []string{"✓", "Превышено время"}},
+		{"TL+ML", TimeExceeded | MemoryExceeded, []string{"Превышено время", "Превышена память"}, []string{"✓"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			Result{Elapsed: time.Second, MaxTime: time.Second, MaxMemoryMB: 256, Verdict: tt.verdict}.Report(&buf)
+			out := buf.String()
+			for _, s := range tt.want {
+				if !strings.Contains(out, s) {
+					t.Errorf("отчёт %q не содержит %q", out, s)
+				}
+			}
+			for _, s := range tt.notWant {
+				if strings.Contains(out, s) {
+					t.Errorf("отчёт %q содержит %q", out, s)
+				}
+			}
+			if tt.verdict.String() != tt.name {
+				t.Errorf("Verdict.String() = %q, ожидалось %q", tt.verdict.String(), tt.name)
+			}
+		})
+	}
+}
+
+func TestRunWithoutEnv(t *testing.T) {
+	t.Setenv(EnvVar, "")
+	called := false
+	Run(time.Second, 256, func() { called = true })
+	if !called {
+		t.Error("Run не вызвал функцию без CHECK_LIMITS")
+	}
+}
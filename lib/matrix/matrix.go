@@ -0,0 +1,250 @@
+// Package matrix — матрицы над вычетами по модулю и быстрое возведение в
+// степень.
+//
+// Matrix хранит элементы одним срезом по строкам. Умножение копит сумму
+// произведений в uint64 и берёт остаток раз в reduceEvery слагаемых, а не
+// на каждом шаге, и пишет результат в уже выделенную матрицу:
+//
+//	z.Mul(x, y) // z = x·y, как в math/big; z может совпадать с x или y
+//
+// Когда нужна не сама степень, а строка v·A^k, VecPow возводит в квадрат
+// только A, а на вектор умножает за O(n²). Для многих k с одной матрицей
+// Powers запоминает A, A², A⁴, ... и отвечает на запрос за O(n² log k).
+package matrix
+
+import (
+	"math/bits"
+
+	"yandex-2025-winter/lib/modint"
+)
+
+// reduceEvery — сколько произведений помещается в uint64 без остатка:
+// вычеты меньше 2^30, произведение меньше 2^60, шестнадцать — меньше 2^64
+const reduceEvery = 16
+
+// Matrix — матрица rows×cols над вычетами по модулю M
+type Matrix[M modint.Modulus] struct {
+	rows, cols int
+	a          []modint.Int[M] // элемент (i, j) — a[i·cols+j]
+	spare      []modint.Int[M] // буфер Mul, когда z совпадает с множителем
+	acc        []uint64        // строка сумм Mul
+}
+
+// New возвращает нулевую матрицу rows×cols
+func New[M modint.Modulus](rows, cols int) *Matrix[M] {
+	return &Matrix[M]{rows: rows, cols: cols, a: make([]modint.Int[M], rows*cols)}
+}
+
+// Identity возвращает единичную матрицу n×n
+func Identity[M modint.Modulus](n int) *Matrix[M] {
+	z := New[M](n, n)
+	z.setIdentity()
+	return z
+}
+
+func (z *Matrix[M]) setIdentity() {
+	clear(z.a)
+	for i := range z.rows {
+		z.a[i*z.cols+i] = modint.New[M](1)
+	}
+}
+
+// Rows возвращает число строк
+func (z *Matrix[M]) Rows() int {
+	return z.rows
+}
+
+// Cols возвращает число столбцов
+func (z *Matrix[M]) Cols() int {
+	return z.cols
+}
+
+// At возвращает элемент (i, j)
+func (z *Matrix[M]) At(i, j int) modint.Int[M] {
+	return z.a[i*z.cols+j]
+}
+
+// Set записывает v в элемент (i, j)
+func (z *Matrix[M]) Set(i, j int, v modint.Int[M]) {
+	z.a[i*z.cols+j] = v
+}
+
+// Row возвращает строку i; это часть матрицы, а не копия
+func (z *Matrix[M]) Row(i int) []modint.Int[M] {
+	return z.a[i*z.cols : (i+1)*z.cols]
+}
+
+// Clone возвращает копию матрицы
+func (z *Matrix[M]) Clone() *Matrix[M] {
+	c := New[M](z.rows, z.cols)
+	copy(c.a, z.a)
+	return c
+}
+
+// Mul записывает в z произведение x·y и возвращает z. Размеры z должны
+// быть x.rows×y.cols; z может совпадать с x или y — тогда произведение
+// считается в запасной буфер z, который потом меняется местами с z
+func (z *Matrix[M]) Mul(x, y *Matrix[M]) *Matrix[M] {
+	if x.cols != y.rows || z.rows != x.rows || z.cols != y.cols {
+		panic("matrix: размеры не согласованы")
+	}
+	out := z.a
+	aliased := z == x || z == y
+	if aliased {
+		if len(z.spare) != len(z.a) {
+			z.spare = make([]modint.Int[M], len(z.a))
+		}
+		out = z.spare
+	}
+	if len(z.acc) != y.cols {
+		z.acc = make([]uint64, y.cols)
+	}
+	acc := z.acc
+	mod := uint64(modint.Mod[M]())
+	// Порядок i-k-j: строки y и acc читаются подряд
+	for i := range x.rows {
+		clear(acc)
+		// terms — слагаемые в acc после последнего остатка; нулевые
+		// множители пропускаются и не считаются
+		terms := 0
+		for k, xik := range x.a[i*x.cols : (i+1)*x.cols] {
+			if xik.IsZero() {
+				continue
+			}
+			v := uint64(xik.Val())
+			for j, ykj := range y.a[k*y.cols : (k+1)*y.cols] {
+				acc[j] += v * uint64(ykj.Val())
+			}
+			if terms++; terms == reduceEvery {
+				terms = 0
+				for j := range acc {
+					acc[j] %= mod
+				}
+			}
+		}
+		row := out[i*z.cols : (i+1)*z.cols]
+		for j, s := range acc {
+			row[j] = modint.New[M](int64(s % mod))
+		}
+	}
+	if aliased {
+		z.a, z.spare = z.spare, z.a
+	}
+	return z
+}
+
+// Pow записывает в z матрицу x^e и возвращает z; x квадратная, z того же
+// размера и может совпадать с x. O(n³ log e)
+func (z *Matrix[M]) Pow(x *Matrix[M], e uint64) *Matrix[M] {
+	if x.rows != x.cols || z.rows != x.rows || z.cols != x.cols {
+		panic("matrix: степень неквадратной матрицы")
+	}
+	base := x.Clone()
+	z.setIdentity()
+	for ; e > 0; e >>= 1 {
+		if e&1 == 1 {
+			z.Mul(z, base)
+		}
+		if e > 1 {
+			base.Mul(base, base)
+		}
+	}
+	return z
+}
+
+// VecMul возвращает строку v·x; len(v) = x.rows
+func VecMul[M modint.Modulus](v []modint.Int[M], x *Matrix[M]) []modint.Int[M] {
+	return vecMul(make([]uint64, x.cols), v, x)
+}
+
+// vecMul считает v·x в acc с отложенным остатком
+func vecMul[M modint.Modulus](acc []uint64, v []modint.Int[M], x *Matrix[M]) []modint.Int[M] {
+	if len(v) != x.rows {
+		panic("matrix: длина вектора не равна числу строк")
+	}
+	clear(acc)
+	mod := uint64(modint.Mod[M]())
+	terms := 0 // как в Mul
+	for k, vk := range v {
+		if vk.IsZero() {
+			continue
+		}
+		c := uint64(vk.Val())
+		for j, xkj := range x.a[k*x.cols : (k+1)*x.cols] {
+			acc[j] += c * uint64(xkj.Val())
+		}
+		if terms++; terms == reduceEvery {
+			terms = 0
+			for j := range acc {
+				acc[j] %= mod
+			}
+		}
+	}
+	res := make([]modint.Int[M], x.cols)
+	for j, s := range acc {
+		res[j] = modint.New[M](int64(s % mod))
+	}
+	return res
+}
+
+// VecPow возвращает строку v·x^k: x возводится в квадрат, а на вектор
+// умножается только для единичных битов k. O(n³ log k) на квадраты и
+// O(n² log k) на вектор — вдвое меньше умножений матриц, чем в Pow
+func VecPow[M modint.Modulus](v []modint.Int[M], x *Matrix[M], k uint64) []modint.Int[M] {
+	if x.rows != x.cols {
+		panic("matrix: степень неквадратной матрицы")
+	}
+	acc := make([]uint64, x.cols)
+	res := append([]modint.Int[M](nil), v...)
+	base := x
+	for ; k > 0; k >>= 1 {
+		if k&1 == 1 {
+			res = vecMul(acc, res, base)
+		}
+		if k > 1 {
+			if base == x {
+				base = x.Clone()
+			}
+			base.Mul(base, base)
+		}
+	}
+	return res
+}
+
+// Powers — степени матрицы A^(2^i), которые считаются по мере надобности
+type Powers[M modint.Modulus] struct {
+	pow []*Matrix[M] // pow[i] = A^(2^i)
+	acc []uint64
+}
+
+// NewPowers возвращает кэш степеней квадратной матрицы x. Матрица
+// копируется: её изменения после вызова на ответы не влияют
+func NewPowers[M modint.Modulus](x *Matrix[M]) *Powers[M] {
+	if x.rows != x.cols {
+		panic("matrix: степень неквадратной матрицы")
+	}
+	return &Powers[M]{pow: []*Matrix[M]{x.Clone()}, acc: make([]uint64, x.cols)}
+}
+
+// Grow досчитывает квадраты для показателей до k включительно. Вызывать
+// не обязательно: это делает Apply; Grow нужен, чтобы заплатить за
+// квадраты заранее
+func (p *Powers[M]) Grow(k uint64) {
+	for len(p.pow) < bits.Len64(k) {
+		last := p.pow[len(p.pow)-1]
+		p.pow = append(p.pow, New[M](last.rows, last.cols).Mul(last, last))
+	}
+}
+
+// Apply возвращает строку v·A^k за O(n² log k), если квадраты уже
+// посчитаны, и досчитывает недостающие за O(n³) каждый
+func (p *Powers[M]) Apply(v []modint.Int[M], k uint64) []modint.Int[M] {
+	p.Grow(k)
+	res := append([]modint.Int[M](nil), v...)
+	for i := 0; k > 0; i, k = i+1, k>>1 {
+		if k&1 == 1 {
+			res = vecMul(p.acc, res, p.pow[i])
+		}
+	}
+	return res
+}
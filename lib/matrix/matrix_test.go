@@ -0,0 +1,212 @@
+package matrix
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"yandex-2025-winter/lib/modint"
+)
+
+type mint = modint.Mod998244353
+
+// random возвращает матрицу rows×cols со случайными вычетами; часть
+// элементов — нули и M−1, чтобы проверить пропуск нулей и переполнение
+func random(r *rand.Rand, rows, cols int) *Matrix[modint.P998244353] {
+	z := New[modint.P998244353](rows, cols)
+	for i := range rows {
+		for j := range cols {
+			switch r.Intn(4) {
+			case 0:
+			case 1:
+				z.Set(i, j, modint.New998244353(-1))
+			default:
+				z.Set(i, j, modint.New998244353(r.Int63()))
+			}
+		}
+	}
+	return z
+}
+
+// naiveMul умножает по определению с остатком на каждом шаге
+func naiveMul(x, y *Matrix[modint.P998244353]) *Matrix[modint.P998244353] {
+	z := New[modint.P998244353](x.Rows(), y.Cols())
+	for i := range x.Rows() {
+		for j := range y.Cols() {
+			var s mint
+			for k := range x.Cols() {
+				s = s.Add(x.At(i, k).Mul(y.At(k, j)))
+			}
+			z.Set(i, j, s)
+		}
+	}
+	return z
+}
+
+func equal(x, y *Matrix[modint.P998244353]) bool {
+	return x.Rows() == y.Rows() && x.Cols() == y.Cols() && slices.Equal(x.a, y.a)
+}
+
+func TestMul(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for range 200 {
+		n, k, m := 1+r.Intn(40), 1+r.Intn(40), 1+r.Intn(40)
+		x, y := random(r, n, k), random(r, k, m)
+		want := naiveMul(x, y)
+		if got := New[modint.P998244353](n, m).Mul(x, y); !equal(got, want) {
+			t.Fatalf("%d×%d · %d×%d: произведение не совпадает с наивным", n, k, k, m)
+		}
+	}
+
+	// Все элементы M−1: 100 максимальных произведений в одной сумме
+	x := New[modint.P998244353](100, 100)
+	for i := range x.a {
+		x.a[i] = modint.New998244353(-1)
+	}
+	if got, want := x.Clone().Mul(x, x), naiveMul(x, x); !equal(got, want) {
+		t.Error("переполнение при отложенном остатке")
+	}
+}
+
+// TestZeroAtReduce — ноль на позиции k ≡ 15 (mod 16) не отменяет остаток:
+// строка из 32 элементов M−1 с x[15] = 0 на такой же столбец даёт 31
+func TestZeroAtReduce(t *testing.T) {
+	x, y := New[modint.P998244353](1, 32), New[modint.P998244353](32, 1)
+	v := make([]mint, 32)
+	for k := range 32 {
+		y.Set(k, 0, modint.New998244353(-1))
+		if k != 15 {
+			x.Set(0, k, modint.New998244353(-1))
+			v[k] = modint.New998244353(-1)
+		}
+	}
+	if got := New[modint.P998244353](1, 1).Mul(x, y).At(0, 0).Val(); got != 31 {
+		t.Errorf("Mul = %d, ожидалось 31", got)
+	}
+	if got := VecMul(v, y)[0].Val(); got != 31 {
+		t.Errorf("VecMul = %d, ожидалось 31", got)
+	}
+}
+
+func TestMulAliased(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	x, y := random(r, 30, 30), random(r, 30, 30)
+	want := naiveMul(x, y)
+	if z := x.Clone(); !equal(z.Mul(z, y), want) {
+		t.Error("z.Mul(z, y) не совпадает с x·y")
+	}
+	if z := y.Clone(); !equal(z.Mul(x, z), want) {
+		t.Error("z.Mul(x, z) не совпадает с x·y")
+	}
+	if z := x.Clone(); !equal(z.Mul(z, z), naiveMul(x, x)) {
+		t.Error("z.Mul(z, z) не совпадает с x·x")
+	}
+}
+
+func TestPow(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	for range 50 {
+		n := 1 + r.Intn(12)
+		x := random(r, n, n)
+		want := Identity[modint.P998244353](n)
+		for e := range uint64(40) {
+			if got := New[modint.P998244353](n, n).Pow(x, e); !equal(got, want) {
+				t.Fatalf("n=%d: Pow(x, %d) не совпадает с произведением", n, e)
+			}
+			v := random(r, 1, n).Row(0)
+			if got := VecPow(v, x, e); !slices.Equal(got, VecMul(v, want)) {
+				t.Fatalf("n=%d: VecPow(v, x, %d) не совпадает с v·x^%d", n, e, e)
+			}
+			want = naiveMul(want, x)
+		}
+		// Pow на месте и неизменность аргумента VecPow
+		saved := x.Clone()
+		VecPow(random(r, 1, n).Row(0), x, 1<<40+3)
+		if !equal(x, saved) {
+			t.Fatal("VecPow изменил матрицу")
+		}
+		if got := x.Pow(x, 5); !equal(got, New[modint.P998244353](n, n).Pow(saved, 5)) {
+			t.Fatal("x.Pow(x, 5) не совпадает с Pow в новую матрицу")
+		}
+	}
+}
+
+func TestPowers(t *testing.T) {
+	// Числа Фибоначчи: (F(k), F(k+1)) = (0, 1)·[[0 1] [1 1]]^k
+	fib := New[modint.P998244353](2, 2)
+	fib.Set(0, 1, modint.New998244353(1))
+	fib.Set(1, 0, modint.New998244353(1))
+	fib.Set(1, 1, modint.New998244353(1))
+	p := NewPowers(fib)
+	start := []mint{modint.New998244353(0), modint.New998244353(1)}
+	a, b := modint.New998244353(0), modint.New998244353(1)
+	for k := range uint64(300) {
+		if got := p.Apply(start, k); got[0] != a || got[1] != b {
+			t.Fatalf("F(%d) = %v, ожидалось %v", k, got[0], a)
+		}
+		a, b = b, a.Add(b)
+	}
+
+	r := rand.New(rand.NewSource(4))
+	x := random(r, 20, 20)
+	orig := x.Clone()
+	p = NewPowers(x)
+	p.Grow(1 << 50)
+	x.Set(0, 0, x.At(0, 0).Add(modint.New998244353(1))) // кэш хранит копию
+	for range 50 {
+		k := uint64(r.Int63n(1 << 50))
+		v := random(r, 1, 20).Row(0)
+		if got := p.Apply(v, k); !slices.Equal(got, VecPow(v, orig, k)) {
+			t.Fatalf("Apply(v, %d) не совпадает с VecPow", k)
+		}
+	}
+}
+
+func TestPanics(t *testing.T) {
+	for name, f := range map[string]func(){
+		"Mul с несогласованными размерами": func() {
+			New[modint.P998244353](2, 2).Mul(New[modint.P998244353](2, 3), New[modint.P998244353](2, 2))
+		},
+		"Pow неквадратной": func() {
+			New[modint.P998244353](2, 3).Pow(New[modint.P998244353](2, 3), 2)
+		},
+		"VecMul с неверной длиной": func() {
+			VecMul(make([]mint, 3), New[modint.P998244353](2, 2))
+		},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: ожидалась паника", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+func BenchmarkMul(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	x, y := random(r, 100, 100), random(r, 100, 100)
+	z := New[modint.P998244353](100, 100)
+	for b.Loop() {
+		z.Mul(x, y)
+	}
+}
+
+func BenchmarkVecPow(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	x, v := random(r, 100, 100), random(r, 1, 100).Row(0)
+	for b.Loop() {
+		VecPow(v, x, 1e12)
+	}
+}
+
+func BenchmarkPow(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	x := random(r, 100, 100)
+	z := New[modint.P998244353](100, 100)
+	for b.Loop() {
+		z.Pow(x, 1e12)
+	}
+}
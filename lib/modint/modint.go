@@ -0,0 +1,181 @@
+// Package modint — арифметика по простому модулю, параметризованная модулем.
+//
+// Int[M] хранит вычет по модулю M в [0, M): сложение, вычитание,
+// умножение, степень и обратный элемент не требуют ручных `% mod` и `+ mod`,
+// а вычеты по разным модулям — разные типы, и их нельзя смешать. Для двух
+// модулей задач есть готовые типы:
+//
+//	a := modint.New1e9_7(x) // или modint.New998244353, modint.New[M]
+//	b := a.Mul(a).Add(modint.New1e9_7(1)).Inv()
+//
+// Обратный элемент ищется по малой теореме Ферма, поэтому модуль должен быть
+// простым. Для многих обратных сразу есть BatchInv (одно возведение в степень
+// на весь срез) и InvTable (все 1/i до n за линейное время).
+package modint
+
+import "strconv"
+
+// Modulus — модуль типа Int, записанный длиной массива нулевого размера.
+// Так модуль — константа для компилятора: каждый модуль получает свою копию
+// кода, и `% M` компилируется в умножения, а не в деление на переменную.
+// Модуль с методом вместо длины массива обходится в 2–4 раза дороже.
+// Перечислены модули задач и простые для NTT; все простые и меньше 2^30
+type Modulus interface {
+	~[1_000_000_007]struct{} | ~[998_244_353]struct{} | ~[1_000_000_009]struct{} |
+		~[167_772_161]struct{} | ~[469_762_049]struct{} | ~[754_974_721]struct{}
+}
+
+// Модули задач числами — для сравнений и сообщений в тестах
+const (
+	M1e9_7     = 1_000_000_007
+	M998244353 = 998_244_353
+)
+
+// P1e9_7 — модуль 10^9+7
+type P1e9_7 [M1e9_7]struct{}
+
+// P998244353 — модуль 998244353 = 119·2^23+1, подходящий для NTT
+type P998244353 [M998244353]struct{}
+
+// Mod1e9_7 — вычет по модулю 10^9+7
+type Mod1e9_7 = Int[P1e9_7]
+
+// Mod998244353 — вычет по модулю 998244353
+type Mod998244353 = Int[P998244353]
+
+// New1e9_7 возвращает вычет v по модулю 10^9+7
+func New1e9_7(v int64) Mod1e9_7 {
+	return New[P1e9_7](v)
+}
+
+// New998244353 возвращает вычет v по модулю 998244353
+func New998244353(v int64) Mod998244353 {
+	return New[P998244353](v)
+}
+
+// Int — вычет по модулю M; нулевое значение — 0
+type Int[M Modulus] struct {
+	v uint32
+}
+
+// Mod возвращает модуль M
+func Mod[M Modulus]() int64 {
+	return int64(modulus[M]())
+}
+
+// modulus возвращает модуль M; после инстанцирования — константа
+func modulus[M Modulus]() uint32 {
+	var m M
+	return uint32(len(m))
+}
+
+// New возвращает вычет v по модулю M; v может быть отрицательным
+func New[M Modulus](v int64) Int[M] {
+	r := v % int64(modulus[M]())
+	if r < 0 {
+		r += int64(modulus[M]())
+	}
+	return Int[M]{uint32(r)}
+}
+
+// Val возвращает представителя вычета из [0, M)
+func (a Int[M]) Val() int64 {
+	return int64(a.v)
+}
+
+// String возвращает представителя вычета десятичной записью
+func (a Int[M]) String() string {
+	return strconv.FormatUint(uint64(a.v), 10)
+}
+
+// IsZero сообщает, равен ли вычет нулю
+func (a Int[M]) IsZero() bool {
+	return a.v == 0
+}
+
+// Add возвращает a + b
+func (a Int[M]) Add(b Int[M]) Int[M] {
+	r := a.v + b.v // a, b < M < 2^30: сумма помещается в uint32
+	if r >= modulus[M]() {
+		r -= modulus[M]()
+	}
+	return Int[M]{r}
+}
+
+// Sub возвращает a - b
+func (a Int[M]) Sub(b Int[M]) Int[M] {
+	r := a.v - b.v
+	if a.v < b.v {
+		r += modulus[M]()
+	}
+	return Int[M]{r}
+}
+
+// Neg возвращает -a
+func (a Int[M]) Neg() Int[M] {
+	if a.v == 0 {
+		return a
+	}
+	return Int[M]{modulus[M]() - a.v}
+}
+
+// Mul возвращает a·b
+func (a Int[M]) Mul(b Int[M]) Int[M] {
+	return Int[M]{uint32(uint64(a.v) * uint64(b.v) % uint64(modulus[M]()))}
+}
+
+// Pow возвращает a^e; 0^0 = 1
+func (a Int[M]) Pow(e uint64) Int[M] {
+	r := Int[M]{1}
+	for ; e > 0; e >>= 1 {
+		if e&1 == 1 {
+			r = r.Mul(a)
+		}
+		a = a.Mul(a)
+	}
+	return r
+}
+
+// Inv возвращает a^(-1); паникует, если a = 0
+func (a Int[M]) Inv() Int[M] {
+	if a.v == 0 {
+		panic("modint: обратный к нулю")
+	}
+	return a.Pow(uint64(modulus[M]()) - 2)
+}
+
+// Div возвращает a / b; паникует, если b = 0
+func (a Int[M]) Div(b Int[M]) Int[M] {
+	return a.Mul(b.Inv())
+}
+
+// BatchInv возвращает обратные ко всем элементам a за O(n) умножений и
+// одно обращение: через префиксные произведения. Паникует, если среди a есть 0
+func BatchInv[M Modulus](a []Int[M]) []Int[M] {
+	inv := make([]Int[M], len(a))
+	acc := Int[M]{1}
+	for i, x := range a {
+		inv[i] = acc // произведение a[0..i)
+		acc = acc.Mul(x)
+	}
+	acc = acc.Inv()
+	for i := len(a) - 1; i >= 0; i-- {
+		inv[i] = inv[i].Mul(acc)
+		acc = acc.Mul(a[i])
+	}
+	return inv
+}
+
+// InvTable возвращает inv[0..n], где inv[i] = 1/i (inv[0] = 0), за O(n):
+// inv[i] = -(M/i)·inv[M mod i]. Требует n < M
+func InvTable[M Modulus](n int) []Int[M] {
+	p := modulus[M]()
+	inv := make([]Int[M], n+1)
+	if n >= 1 {
+		inv[1] = Int[M]{1}
+	}
+	for i := 2; i <= n; i++ {
+		inv[i] = inv[p%uint32(i)].Mul(Int[M]{p / uint32(i)}).Neg()
+	}
+	return inv
+}
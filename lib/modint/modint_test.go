@@ -0,0 +1,132 @@
+package modint
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+// checkArith сравнивает операции Int[M] с math/big на случайных и граничных значениях
+func checkArith[M Modulus](t *testing.T) {
+	p := Mod[M]()
+	bp := big.NewInt(p)
+	r := rand.New(rand.NewSource(1))
+	values := []int64{0, 1, 2, p - 1, p - 2, p, p + 1, -1, -p, 1 << 62, -1 << 62}
+	for range 200 {
+		values = append(values, r.Int63()-r.Int63(), r.Int63n(p))
+	}
+	norm := func(v *big.Int) int64 {
+		return v.Mod(v, bp).Int64()
+	}
+	for i, x := range values {
+		y := values[(i*7+3)%len(values)]
+		a, b := New[M](x), New[M](y)
+		bx, by := big.NewInt(x), big.NewInt(y)
+		if got, want := a.Val(), norm(new(big.Int).Set(bx)); got != want {
+			t.Fatalf("New(%d) = %d, ожидалось %d", x, got, want)
+		}
+		if got, want := a.Add(b).Val(), norm(new(big.Int).Add(bx, by)); got != want {
+			t.Errorf("%d + %d = %d, ожидалось %d", x, y, got, want)
+		}
+		if got, want := a.Sub(b).Val(), norm(new(big.Int).Sub(bx, by)); got != want {
+			t.Errorf("%d - %d = %d, ожидалось %d", x, y, got, want)
+		}
+		if got, want := a.Neg().Val(), norm(new(big.Int).Neg(bx)); got != want {
+			t.Errorf("-%d = %d, ожидалось %d", x, got, want)
+		}
+		if got, want := a.Mul(b).Val(), norm(new(big.Int).Mul(bx, by)); got != want {
+			t.Errorf("%d · %d = %d, ожидалось %d", x, y, got, want)
+		}
+		e := uint64(y) >> 1
+		if got, want := a.Pow(e).Val(), new(big.Int).Exp(bx, new(big.Int).SetUint64(e), bp).Int64(); got != want {
+			t.Errorf("%d^%d = %d, ожидалось %d", x, e, got, want)
+		}
+		if a.IsZero() {
+			continue
+		}
+		if got := a.Mul(a.Inv()).Val(); got != 1 {
+			t.Errorf("%d · %d^(-1) = %d, ожидалось 1", x, x, got)
+		}
+		if got := b.Div(a).Mul(a); got != b {
+			t.Errorf("(%d / %d) · %d = %v, ожидалось %v", y, x, x, got, b)
+		}
+	}
+}
+
+func TestArith(t *testing.T) {
+	t.Run("1e9+7", checkArith[P1e9_7])
+	t.Run("998244353", checkArith[P998244353])
+}
+
+func TestInvZeroPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Inv(0) не паникует")
+		}
+	}()
+	Mod1e9_7{}.Inv()
+}
+
+func TestBatchInv(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	a := make([]Mod998244353, 1000)
+	for i := range a {
+		a[i] = New[P998244353](1 + r.Int63n(Mod[P998244353]()-1))
+	}
+	for i, inv := range BatchInv(a) {
+		if inv != a[i].Inv() {
+			t.Fatalf("BatchInv[%d] = %v, ожидалось %v", i, inv, a[i].Inv())
+		}
+	}
+	if got := BatchInv[P998244353](nil); len(got) != 0 {
+		t.Errorf("BatchInv(nil) = %v", got)
+	}
+}
+
+func TestInvTable(t *testing.T) {
+	inv := InvTable[P1e9_7](100000)
+	if !inv[0].IsZero() {
+		t.Errorf("inv[0] = %v, ожидалось 0", inv[0])
+	}
+	for i := 1; i < len(inv); i++ {
+		if inv[i] != New[P1e9_7](int64(i)).Inv() {
+			t.Fatalf("inv[%d] = %v, ожидалось %v", i, inv[i], New[P1e9_7](int64(i)).Inv())
+		}
+	}
+	if got := InvTable[P1e9_7](0); len(got) != 1 {
+		t.Errorf("InvTable(0) = %v, ожидался срез из одного нуля", got)
+	}
+}
+
+// TestHandCodedConstants сверяет константы, которые задачи считали вручную
+func TestHandCodedConstants(t *testing.T) {
+	// 14/: INV2, INV3 по модулю 10^9+7
+	if got := New[P1e9_7](2).Inv().Val(); got != 500000004 {
+		t.Errorf("1/2 = %d, ожидалось 500000004", got)
+	}
+	if got := New[P1e9_7](3).Inv().Val(); got != 333333336 {
+		t.Errorf("1/3 = %d, ожидалось 333333336", got)
+	}
+	// 19/: inv[i] = (MOD - (MOD/i)*inv[MOD%i]%MOD) % MOD
+	const mod = 1000000007
+	old := make([]int64, 1001)
+	old[1] = 1
+	for i := int64(2); i <= 1000; i++ {
+		old[i] = (mod - (mod/i)*old[mod%i]%mod) % mod
+	}
+	for i, v := range InvTable[P1e9_7](1000)[1:] {
+		if v.Val() != old[i+1] {
+			t.Fatalf("inv[%d] = %v, в 19/ было %d", i+1, v, old[i+1])
+		}
+	}
+}
+
+func BenchmarkMul(b *testing.B) {
+	a, x := New[P998244353](3), New[P998244353](1)
+	for b.Loop() {
+		for range 1000 {
+			x = x.Mul(a).Add(a)
+		}
+	}
+	_ = x
+}
@@ -0,0 +1,319 @@
+// Package ntt — быстрое преобразование Фурье над полем по модулю 998244353
+// (Number-Theoretic Transform) и операции с многочленами на его основе.
+//
+// Многочлен — срез коэффициентов []modint.Mod998244353, начиная со
+// свободного члена. Функции не изменяют аргументы и возвращают новые срезы.
+// Операции над формальными рядами (Inv, Log, Exp) считают первые n
+// коэффициентов методом Ньютона за O(n log n).
+package ntt
+
+import (
+	"math/bits"
+
+	"yandex-2025-winter/lib/comb"
+	"yandex-2025-winter/lib/modint"
+)
+
+// mint — элемент поля
+type mint = modint.Mod998244353
+
+// root — первообразный корень по модулю 998244353 = 119·2^23+1
+const root = 3
+
+// naiveLimit — при меньшей длине одного из сомножителей Multiply умножает
+// в столбик: это быстрее трёх преобразований
+const naiveLimit = 32
+
+// Transform заменяет a на значения многочлена в корнях из единицы степени
+// len(a) (invert = false) или выполняет обратное преобразование (invert = true).
+// len(a) — степень двойки, не больше 2^23
+func Transform(a []mint, invert bool) {
+	n := len(a)
+	if n&(n-1) != 0 {
+		panic("ntt: длина не степень двойки")
+	}
+	if n <= 1 {
+		return
+	}
+	shift := 64 - bits.TrailingZeros(uint(n))
+	for i := range a {
+		if j := int(bits.Reverse64(uint64(i)) >> shift); i < j {
+			a[i], a[j] = a[j], a[i]
+		}
+	}
+
+	ws := make([]mint, n/2)
+	for length := 2; length <= n; length <<= 1 {
+		half := length / 2
+		w := modint.New998244353(root).Pow(uint64((modint.Mod[modint.P998244353]() - 1) / int64(length)))
+		if invert {
+			w = w.Inv()
+		}
+		ws[0] = modint.New998244353(1)
+		for j := 1; j < half; j++ {
+			ws[j] = ws[j-1].Mul(w)
+		}
+		for i := 0; i < n; i += length {
+			lo, hi := a[i:i+half], a[i+half:i+length]
+			hi, w := hi[:len(lo)], ws[:len(lo)] // без проверок границ в цикле
+			for j := range lo {
+				u, v := lo[j], hi[j].Mul(w[j])
+				lo[j], hi[j] = u.Add(v), u.Sub(v)
+			}
+		}
+	}
+
+	if invert {
+		nInv := modint.New998244353(int64(n)).Inv()
+		for i := range a {
+			a[i] = a[i].Mul(nInv)
+		}
+	}
+}
+
+// Multiply возвращает произведение многочленов a и b
+func Multiply(a, b []mint) []mint {
+	if len(a) == 0 || len(b) == 0 {
+		return nil
+	}
+	size := len(a) + len(b) - 1
+	if min(len(a), len(b)) <= naiveLimit {
+		res := make([]mint, size)
+		for i, x := range a {
+			for j, y := range b {
+				res[i+j] = res[i+j].Add(x.Mul(y))
+			}
+		}
+		return res
+	}
+
+	n := 1 << bits.Len(uint(size-1))
+	fa := make([]mint, n)
+	fb := make([]mint, n)
+	copy(fa, a)
+	copy(fb, b)
+	Transform(fa, false)
+	Transform(fb, false)
+	for i := range fa {
+		fa[i] = fa[i].Mul(fb[i])
+	}
+	Transform(fa, true)
+	return fa[:size]
+}
+
+// resize возвращает копию первых n коэффициентов a, дополненную нулями
+func resize(a []mint, n int) []mint {
+	res := make([]mint, n)
+	copy(res, a)
+	return res
+}
+
+// Inv возвращает первые n коэффициентов ряда 1/a; паникует, если a[0] = 0.
+// Ньютон: b ← b·(2 − a·b) удваивает число верных коэффициентов
+func Inv(a []mint, n int) []mint {
+	if len(a) == 0 || a[0].IsZero() {
+		panic("ntt: обратный ряд к ряду с нулевым свободным членом")
+	}
+	b := []mint{a[0].Inv()}
+	two := modint.New998244353(2)
+	for m := 1; m < n; m *= 2 {
+		t := resize(Multiply(resize(a[:min(len(a), 2*m)], 2*m), b), 2*m)
+		for i := range t {
+			t[i] = t[i].Neg()
+		}
+		t[0] = t[0].Add(two)
+		b = resize(Multiply(b, t), 2*m)
+	}
+	return resize(b, n)
+}
+
+// Derivative возвращает производную a
+func Derivative(a []mint) []mint {
+	if len(a) <= 1 {
+		return nil
+	}
+	res := make([]mint, len(a)-1)
+	for i := range res {
+		res[i] = a[i+1].Mul(modint.New998244353(int64(i + 1)))
+	}
+	return res
+}
+
+// Integral возвращает первообразную a с нулевым свободным членом
+func Integral(a []mint) []mint {
+	inv := modint.InvTable[modint.P998244353](len(a))
+	res := make([]mint, len(a)+1)
+	for i, x := range a {
+		res[i+1] = x.Mul(inv[i+1])
+	}
+	return res
+}
+
+// Log возвращает первые n коэффициентов ln a; требует a[0] = 1
+func Log(a []mint, n int) []mint {
+	if len(a) == 0 || a[0] != modint.New998244353(1) {
+		panic("ntt: логарифм ряда со свободным членом не 1")
+	}
+	if n == 0 {
+		return nil
+	}
+	a = a[:min(len(a), n)]
+	d := resize(Multiply(Derivative(a), Inv(a, n)), n-1)
+	return resize(Integral(d), n)
+}
+
+// Exp возвращает первые n коэффициентов exp a; требует a[0] = 0.
+// Ньютон: g ← g·(1 − ln g + a)
+func Exp(a []mint, n int) []mint {
+	if len(a) > 0 && !a[0].IsZero() {
+		panic("ntt: экспонента ряда с ненулевым свободным членом")
+	}
+	if n == 0 {
+		return nil
+	}
+	g := []mint{modint.New998244353(1)}
+	for m := 1; m < n; m *= 2 {
+		t := resize(a[:min(len(a), 2*m)], 2*m)
+		l := Log(g, 2*m)
+		for i := range t {
+			t[i] = t[i].Sub(l[i])
+		}
+		t[0] = t[0].Add(modint.New998244353(1))
+		g = resize(Multiply(g, t), 2*m)
+	}
+	return resize(g, n)
+}
+
+// trim отбрасывает нулевые старшие коэффициенты
+func trim(a []mint) []mint {
+	for len(a) > 0 && a[len(a)-1].IsZero() {
+		a = a[:len(a)-1]
+	}
+	return a
+}
+
+// reversed возвращает коэффициенты a в обратном порядке
+func reversed(a []mint) []mint {
+	res := make([]mint, len(a))
+	for i, x := range a {
+		res[len(a)-1-i] = x
+	}
+	return res
+}
+
+// DivMod возвращает частное и остаток от деления a на b: a = q·b + r,
+// deg r < deg b. Паникует, если b = 0
+func DivMod(a, b []mint) (q, r []mint) {
+	a, b = trim(a), trim(b)
+	if len(b) == 0 {
+		panic("ntt: деление на нулевой многочлен")
+	}
+	if len(a) < len(b) {
+		return nil, resize(a, len(a))
+	}
+	k := len(a) - len(b) + 1
+	q = reversed(resize(Multiply(reversed(a)[:k], Inv(reversed(b), k)), k))
+	qb := Multiply(q, b)
+	r = make([]mint, len(b)-1)
+	for i := range r {
+		r[i] = a[i].Sub(qb[i])
+	}
+	return q, trim(r)
+}
+
+// Eval возвращает значение многочлена a в точке x по схеме Горнера
+func Eval(a []mint, x mint) mint {
+	var res mint
+	for i := len(a) - 1; i >= 0; i-- {
+		res = res.Mul(x).Add(a[i])
+	}
+	return res
+}
+
+// evalLeaf — при стольких точках и меньше Evaluate считает по Горнеру
+const evalLeaf = 32
+
+// Evaluate возвращает значения многочлена a во всех точках xs за
+// O(n log² n): остатки от деления на произведения (x − xs[i]) по дереву
+func Evaluate(a []mint, xs []mint) []mint {
+	res := make([]mint, len(xs))
+	if len(xs) == 0 {
+		return res
+	}
+	// tree[v] — произведение (x − xs[i]) по отрезку вершины v
+	tree := make([][]mint, 4*len(xs))
+	var build func(v, l, r int)
+	build = func(v, l, r int) {
+		if r-l == 1 {
+			tree[v] = []mint{xs[l].Neg(), modint.New998244353(1)}
+			return
+		}
+		m := (l + r) / 2
+		build(2*v, l, m)
+		build(2*v+1, m, r)
+		tree[v] = Multiply(tree[2*v], tree[2*v+1])
+	}
+	var down func(v, l, r int, p []mint)
+	down = func(v, l, r int, p []mint) {
+		if r-l <= evalLeaf {
+			for i := l; i < r; i++ {
+				res[i] = Eval(p, xs[i])
+			}
+			return
+		}
+		_, p = DivMod(p, tree[v])
+		m := (l + r) / 2
+		down(2*v, l, m, p)
+		down(2*v+1, m, r, p)
+	}
+	if len(xs) > evalLeaf {
+		build(1, 0, len(xs))
+	}
+	down(1, 0, len(xs), a)
+	return res
+}
+
+// TaylorShift возвращает коэффициенты a(x + c) за одно умножение:
+// [x^j] a(x+c) = 1/j! · Σ a_i·i! · c^(i−j)/(i−j)!
+func TaylorShift(a []mint, c mint) []mint {
+	n := len(a)
+	if n == 0 {
+		return nil
+	}
+	tab := comb.New998244353()
+	f := make([]mint, n) // f[t] = a_{n−1−t}·(n−1−t)!
+	g := make([]mint, n) // g[k] = c^k/k!
+	pw := modint.New998244353(1)
+	for i := range n {
+		f[n-1-i] = a[i].Mul(tab.Fact(i))
+		g[i] = pw.Mul(tab.InvFact(i))
+		pw = pw.Mul(c)
+	}
+	h := Multiply(f, g)
+	res := make([]mint, n)
+	for j := range n {
+		res[j] = h[n-1-j].Mul(tab.InvFact(j))
+	}
+	return res
+}
+
+// StirlingFirst возвращает строку чисел Стирлинга первого рода без знака
+// c(n, k), k = 0..n — число перестановок n элементов из k циклов. Это
+// коэффициенты x(x+1)…(x+n−1); произведение строится удвоением
+// R_2m(x) = R_m(x)·R_m(x+m) за O(n log n)
+func StirlingFirst(n int) []mint {
+	r := []mint{modint.New998244353(1)} // R_0 = 1
+	m := 0
+	for bit := bits.Len(uint(n)) - 1; bit >= 0; bit-- {
+		if m > 0 {
+			r = Multiply(r, TaylorShift(r, modint.New998244353(int64(m))))
+			m *= 2
+		}
+		if n>>bit&1 == 1 {
+			// R_{m+1}(x) = R_m(x)·(x + m)
+			r = Multiply(r, []mint{modint.New998244353(int64(m)), modint.New998244353(1)})
+			m++
+		}
+	}
+	return r
+}
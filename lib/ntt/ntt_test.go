@@ -0,0 +1,184 @@
+package ntt
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"yandex-2025-winter/lib/comb"
+	"yandex-2025-winter/lib/modint"
+)
+
+func randomPoly(r *rand.Rand, n int) []mint {
+	a := make([]mint, n)
+	for i := range a {
+		a[i] = modint.New998244353(r.Int63())
+	}
+	return a
+}
+
+func naiveMultiply(a, b []mint) []mint {
+	if len(a) == 0 || len(b) == 0 {
+		return nil
+	}
+	res := make([]mint, len(a)+len(b)-1)
+	for i, x := range a {
+		for j, y := range b {
+			res[i+j] = res[i+j].Add(x.Mul(y))
+		}
+	}
+	return res
+}
+
+func TestTransformRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 8, 1024} {
+		a := randomPoly(r, n)
+		b := slices.Clone(a)
+		Transform(b, false)
+		Transform(b, true)
+		if !slices.Equal(a, b) {
+			t.Errorf("n=%d: обратное преобразование не вернуло исходный массив", n)
+		}
+	}
+}
+
+func TestMultiply(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for _, sz := range [][2]int{{0, 5}, {1, 1}, {3, 40}, {33, 33}, {100, 257}, {1000, 999}} {
+		a, b := randomPoly(r, sz[0]), randomPoly(r, sz[1])
+		if got, want := Multiply(a, b), naiveMultiply(a, b); !slices.Equal(got, want) {
+			t.Errorf("Multiply(%d, %d) не совпадает с умножением в столбик", sz[0], sz[1])
+		}
+	}
+}
+
+func TestInv(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	for _, n := range []int{1, 2, 7, 64, 1000} {
+		a := randomPoly(r, n+3)
+		a[0] = modint.New998244353(5)
+		prod := resize(Multiply(a, Inv(a, n)), n)
+		for i, x := range prod {
+			if want := resize([]mint{modint.New998244353(1)}, n)[i]; x != want {
+				t.Fatalf("n=%d: [x^%d] a·Inv(a) = %v", n, i, x)
+			}
+		}
+	}
+}
+
+func TestLogExp(t *testing.T) {
+	// exp(x) = Σ x^k/k!
+	c := comb.New998244353()
+	invFact := make([]mint, 21)
+	for i := range invFact {
+		invFact[i] = c.InvFact(i)
+	}
+	if got := Exp([]mint{{}, modint.New998244353(1)}, 21); !slices.Equal(got, invFact) {
+		t.Errorf("Exp(x) = %v, ожидалось %v", got, invFact)
+	}
+
+	r := rand.New(rand.NewSource(4))
+	for _, n := range []int{1, 5, 100, 777} {
+		a := randomPoly(r, n)
+		a[0] = mint{}
+		if got := Log(Exp(a, n), n); !slices.Equal(got, a) {
+			t.Errorf("n=%d: Log(Exp(a)) != a", n)
+		}
+		// ln(a·b) = ln a + ln b
+		b, c := randomPoly(r, n), randomPoly(r, n)
+		b[0], c[0] = modint.New998244353(1), modint.New998244353(1)
+		lb, lc := Log(b, n), Log(c, n)
+		got := Log(resize(Multiply(b, c), n), n)
+		for i := range got {
+			if got[i] != lb[i].Add(lc[i]) {
+				t.Fatalf("n=%d: ln(bc) != ln b + ln c в коэффициенте %d", n, i)
+			}
+		}
+	}
+}
+
+func TestDivMod(t *testing.T) {
+	r := rand.New(rand.NewSource(5))
+	for _, sz := range [][2]int{{5, 10}, {10, 1}, {100, 37}, {2000, 1000}} {
+		a, b := randomPoly(r, sz[0]), randomPoly(r, sz[1])
+		q, rem := DivMod(a, b)
+		if len(rem) >= len(b) {
+			t.Errorf("deg остатка %d ≥ deg делителя %d", len(rem)-1, len(b)-1)
+		}
+		back := resize(Multiply(q, b), len(a))
+		for i := range rem {
+			back[i] = back[i].Add(rem[i])
+		}
+		if !slices.Equal(trim(back), trim(a)) {
+			t.Errorf("%v: q·b + r != a", sz)
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	r := rand.New(rand.NewSource(6))
+	for _, sz := range [][2]int{{0, 3}, {10, 0}, {50, 20}, {300, 1000}} {
+		a, xs := randomPoly(r, sz[0]), randomPoly(r, sz[1])
+		got := Evaluate(a, xs)
+		for i, x := range xs {
+			if got[i] != Eval(a, x) {
+				t.Fatalf("%v: Evaluate[%d] = %v, ожидалось %v", sz, i, got[i], Eval(a, x))
+			}
+		}
+	}
+}
+
+func TestTaylorShift(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	a := randomPoly(r, 50)
+	c := modint.New998244353(12345)
+	shifted := TaylorShift(a, c)
+	for _, x := range randomPoly(r, 10) {
+		if got, want := Eval(shifted, x), Eval(a, x.Add(c)); got != want {
+			t.Errorf("a(x+c) в точке %v = %v, ожидалось %v", x, got, want)
+		}
+	}
+}
+
+func TestStirlingFirst(t *testing.T) {
+	// c(i, j) = c(i−1, j−1) + (i−1)·c(i−1, j)
+	const n = 70
+	row := []mint{modint.New998244353(1)}
+	for i := 0; i <= n; i++ {
+		if got := StirlingFirst(i); !slices.Equal(got, row) {
+			t.Fatalf("StirlingFirst(%d) = %v, ожидалось %v", i, got, row)
+		}
+		next := make([]mint, i+2)
+		for j := 1; j <= i+1; j++ {
+			next[j] = row[j-1]
+			if j <= i {
+				next[j] = next[j].Add(modint.New998244353(int64(i)).Mul(row[j]))
+			}
+		}
+		row = next
+	}
+	// Σ_k c(n, k) = n!
+	row = StirlingFirst(100000)
+	var sum mint
+	for _, x := range row {
+		sum = sum.Add(x)
+	}
+	if fact := comb.New998244353().Fact(100000); sum != fact {
+		t.Errorf("Σ c(100000, k) = %v, ожидалось 100000! = %v", sum, fact)
+	}
+}
+
+func BenchmarkMultiply(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	x, y := randomPoly(r, 1<<18), randomPoly(r, 1<<18)
+	for b.Loop() {
+		Multiply(x, y)
+	}
+}
+
+func BenchmarkStirlingFirst(b *testing.B) {
+	for b.Loop() {
+		StirlingFirst(100000)
+	}
+}
@@ -0,0 +1,370 @@
+// Package numtheory — теория чисел для задач: решёта, разложение на
+// множители, делители, функция Эйлера и показатель Лежандра.
+//
+// Числа — int (на 64-битных платформах до 2^63−1). Для многих маленьких
+// чисел есть линейное решето Sieve с наименьшими простыми делителями:
+// разложение по нему — O(log x). Отдельное большое число раскладывает
+// Factorize: пробное деление на малые простые, тест Миллера — Рабина и
+// ρ-алгоритм Полларда, примерно O(n^(1/4)). Простые из отрезка [low, high]
+// без решета до high перечисляет PrimesBetween — по сегментам, не храня
+// весь отрезок в памяти.
+package numtheory
+
+import (
+	"iter"
+	"math"
+	"math/bits"
+	"slices"
+)
+
+// Factor — простой множитель P в степени E
+type Factor struct {
+	P, E int
+}
+
+// IntSqrt возвращает ⌊√n⌋ для n ≥ 0 без переполнения
+func IntSqrt(n int) int {
+	if n < 2 {
+		return n
+	}
+	r := int(math.Sqrt(float64(n)))
+	for r > n/r {
+		r--
+	}
+	for r+1 <= n/(r+1) {
+		r++
+	}
+	return r
+}
+
+// Legendre возвращает показатель простого p в разложении n!:
+// v_p(n!) = Σ ⌊n / p^i⌋
+func Legendre(n, p int) int {
+	res := 0
+	for n >= p {
+		n /= p
+		res += n
+	}
+	return res
+}
+
+// Primes возвращает простые числа до n включительно (решето Эратосфена)
+func Primes(n int) []int {
+	if n < 2 {
+		return []int{}
+	}
+	composite := make([]bool, n+1)
+	primes := []int{}
+	for i := 2; i <= n; i++ {
+		if composite[i] {
+			continue
+		}
+		primes = append(primes, i)
+		for j := i * i; j <= n; j += i {
+			composite[j] = true
+		}
+	}
+	return primes
+}
+
+// Sieve — линейное решето: наименьший простой делитель каждого числа до n
+type Sieve struct {
+	spf    []int32 // spf[x] — наименьший простой делитель x; spf[0] = spf[1] = 0
+	primes []int
+}
+
+// NewSieve строит решето до n включительно за O(n); n < 2^31
+func NewSieve(n int) *Sieve {
+	s := &Sieve{spf: make([]int32, max(n+1, 2))}
+	for i := 2; i <= n; i++ {
+		if s.spf[i] == 0 {
+			s.spf[i] = int32(i)
+			s.primes = append(s.primes, i)
+		}
+		// Каждое составное i·p помечается ровно один раз — своим наименьшим p
+		for _, p := range s.primes {
+			if p > int(s.spf[i]) || i*p > n {
+				break
+			}
+			s.spf[i*p] = int32(p)
+		}
+	}
+	return s
+}
+
+// Primes возвращает простые числа до n решета
+func (s *Sieve) Primes() []int {
+	return s.primes
+}
+
+// SmallestFactor возвращает наименьший простой делитель x ≥ 2
+func (s *Sieve) SmallestFactor(x int) int {
+	return int(s.spf[x])
+}
+
+// IsPrime сообщает, простое ли x; x не больше n решета
+func (s *Sieve) IsPrime(x int) bool {
+	return x >= 2 && int(s.spf[x]) == x
+}
+
+// Factorize раскладывает 1 ≤ x ≤ n на простые множители по возрастанию за O(log x)
+func (s *Sieve) Factorize(x int) []Factor {
+	var res []Factor
+	for x > 1 {
+		p := int(s.spf[x])
+		e := 0
+		for x%p == 0 {
+			x /= p
+			e++
+		}
+		res = append(res, Factor{p, e})
+	}
+	return res
+}
+
+// segmentSize — длина сегмента PrimesBetween
+const segmentSize = 1 << 16
+
+// PrimesBetween перечисляет простые из [low, high] по возрастанию. Решето
+// строится до √high, отрезок просеивается сегментами по segmentSize, так
+// что память — O(√high + segmentSize), а не O(high − low)
+func PrimesBetween(low, high int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		low = max(low, 2)
+		if low > high {
+			return
+		}
+		base := Primes(IntSqrt(high))
+		segment := make([]bool, segmentSize)
+		for segLow := low; segLow <= high; {
+			segHigh := high
+			if high-segLow >= segmentSize { // без переполнения около MaxInt
+				segHigh = segLow + segmentSize - 1
+			}
+			composite := segment[:segHigh-segLow+1]
+			clear(composite)
+			for _, p := range base {
+				if p*p > segHigh {
+					break
+				}
+				// Первое кратное p в сегменте, но не меньше p²: само p простое
+				start := max(segLow+(p-segLow%p)%p, p*p)
+				for j := start; j <= segHigh; j += p {
+					composite[j-segLow] = true
+				}
+			}
+			for i, c := range composite {
+				if !c && !yield(segLow+i) {
+					return
+				}
+			}
+			if segHigh == high {
+				return
+			}
+			segLow = segHigh + 1
+		}
+	}
+}
+
+// mulMod возвращает a·b mod m без переполнения
+func mulMod(a, b, m uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	return bits.Rem64(hi, lo, m)
+}
+
+// powMod возвращает a^e mod m
+func powMod(a, e, m uint64) uint64 {
+	res := uint64(1) % m
+	a %= m
+	for ; e > 0; e >>= 1 {
+		if e&1 == 1 {
+			res = mulMod(res, a, m)
+		}
+		a = mulMod(a, a, m)
+	}
+	return res
+}
+
+// smallPrimes — делители пробного деления в IsPrime и Factorize
+var smallPrimes = Primes(100)
+
+// millerRabinBases — основания, при которых тест Миллера — Рабина точен
+// для всех чисел меньше 2^64
+var millerRabinBases = []uint64{2, 325, 9375, 28178, 450775, 9780504, 1795265022}
+
+// IsPrime сообщает, простое ли n, детерминированным тестом Миллера — Рабина
+func IsPrime(n int) bool {
+	if n < 2 {
+		return false
+	}
+	for _, p := range smallPrimes {
+		if n%p == 0 {
+			return n == p
+		}
+	}
+	return isPrime64(uint64(n))
+}
+
+// isPrime64 — тест Миллера — Рабина для нечётного n без делителей меньше 100
+func isPrime64(n uint64) bool {
+	d := n - 1
+	s := bits.TrailingZeros64(d)
+	d >>= s
+	for _, a := range millerRabinBases {
+		a %= n
+		if a == 0 {
+			continue
+		}
+		x := powMod(a, d, n)
+		if x == 1 || x == n-1 {
+			continue
+		}
+		composite := true
+		for range s - 1 {
+			x = mulMod(x, x, n)
+			if x == n-1 {
+				composite = false
+				break
+			}
+		}
+		if composite {
+			return false
+		}
+	}
+	return true
+}
+
+// pollard возвращает нетривиальный делитель составного нечётного n
+// ρ-алгоритмом Полларда с циклом Брента и накоплением произведений
+func pollard(n uint64) uint64 {
+	const batch = 128
+	for c := uint64(1); ; c++ {
+		f := func(x uint64) uint64 { return (mulMod(x, x, n) + c) % n }
+		y, g, q := uint64(2), uint64(1), uint64(1)
+		var x, ys uint64
+		for r := 1; g == 1; r *= 2 {
+			x = y
+			for range r {
+				y = f(y)
+			}
+			for k := 0; k < r && g == 1; k += batch {
+				ys = y
+				for range min(batch, r-k) {
+					y = f(y)
+					q = mulMod(q, absDiff(x, y), n)
+				}
+				g = gcd(q, n)
+			}
+		}
+		if g == n {
+			// Произведение обнулилось: повторяем последний блок по одному шагу
+			for g = 1; g == 1; {
+				ys = f(ys)
+				g = gcd(absDiff(x, ys), n)
+			}
+		}
+		if g != n {
+			return g
+		}
+	}
+}
+
+func absDiff(a, b uint64) uint64 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+func gcd(a, b uint64) uint64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// Factorize раскладывает n ≥ 1 на простые множители по возрастанию
+func Factorize(n int) []Factor {
+	if n < 1 {
+		panic("numtheory: разложение числа меньше 1")
+	}
+	var res []Factor
+	for _, p := range smallPrimes {
+		if p*p > n {
+			break
+		}
+		if n%p == 0 {
+			e := 0
+			for n%p == 0 {
+				n /= p
+				e++
+			}
+			res = append(res, Factor{p, e})
+		}
+	}
+	if n == 1 {
+		return res
+	}
+	var large []int
+	var split func(m uint64)
+	split = func(m uint64) {
+		if m < 100*100 || isPrime64(m) { // без делителей меньше 100: простое
+			large = append(large, int(m))
+			return
+		}
+		d := pollard(m)
+		split(d)
+		split(m / d)
+	}
+	split(uint64(n))
+	slices.Sort(large)
+	for _, p := range large {
+		if len(res) > 0 && res[len(res)-1].P == p {
+			res[len(res)-1].E++
+		} else {
+			res = append(res, Factor{p, 1})
+		}
+	}
+	return res
+}
+
+// DivisorsOf возвращает все делители числа с разложением fs по возрастанию
+func DivisorsOf(fs []Factor) []int {
+	divs := []int{1}
+	for _, f := range fs {
+		cur := len(divs)
+		pw := 1
+		for range f.E {
+			pw *= f.P
+			for _, d := range divs[:cur] {
+				divs = append(divs, d*pw)
+			}
+		}
+	}
+	slices.Sort(divs)
+	return divs
+}
+
+// Divisors возвращает все делители n ≥ 1 по возрастанию
+func Divisors(n int) []int {
+	return DivisorsOf(Factorize(n))
+}
+
+// DivisorCount возвращает число делителей n ≥ 1
+func DivisorCount(n int) int {
+	res := 1
+	for _, f := range Factorize(n) {
+		res *= f.E + 1
+	}
+	return res
+}
+
+// Phi возвращает функцию Эйлера φ(n) — число чисел от 1 до n, взаимно
+// простых с n; n ≥ 1
+func Phi(n int) int {
+	res := n
+	for _, f := range Factorize(n) {
+		res = res / f.P * (f.P - 1)
+	}
+	return res
+}
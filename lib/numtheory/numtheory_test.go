@@ -0,0 +1,268 @@
+package numtheory
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// naiveFactorize — пробное деление до √n
+func naiveFactorize(n int) []Factor {
+	var res []Factor
+	for p := 2; p*p <= n; p++ {
+		if n%p == 0 {
+			e := 0
+			for n%p == 0 {
+				n /= p
+				e++
+			}
+			res = append(res, Factor{p, e})
+		}
+	}
+	if n > 1 {
+		res = append(res, Factor{n, 1})
+	}
+	return res
+}
+
+func TestPrimes(t *testing.T) {
+	want := []int{2, 3, 5, 7, 11, 13, 17, 19, 23, 29}
+	if got := Primes(30); !slices.Equal(got, want) {
+		t.Errorf("Primes(30) = %v, ожидалось %v", got, want)
+	}
+	if got := Primes(1); len(got) != 0 {
+		t.Errorf("Primes(1) = %v, ожидалось []", got)
+	}
+	s := NewSieve(100000)
+	if got := Primes(100000); !slices.Equal(s.Primes(), got) {
+		t.Error("линейное решето и решето Эратосфена дают разные простые")
+	}
+}
+
+func TestSieveFactorize(t *testing.T) {
+	const n = 100000
+	s := NewSieve(n)
+	for x := 1; x <= n; x++ {
+		if got, want := s.Factorize(x), naiveFactorize(x); !slices.Equal(got, want) {
+			t.Fatalf("Sieve.Factorize(%d) = %v, ожидалось %v", x, got, want)
+		}
+		if s.IsPrime(x) != (x > 1 && len(naiveFactorize(x)) == 1 && naiveFactorize(x)[0].E == 1) {
+			t.Fatalf("Sieve.IsPrime(%d) = %v", x, s.IsPrime(x))
+		}
+	}
+}
+
+func TestIsPrime(t *testing.T) {
+	s := NewSieve(200000)
+	for x := -5; x <= 200000; x++ {
+		if got := IsPrime(x); got != (x >= 0 && s.IsPrime(x)) {
+			t.Fatalf("IsPrime(%d) = %v", x, got)
+		}
+	}
+	r := rand.New(rand.NewSource(1))
+	for range 2000 {
+		x := r.Int63()
+		if got, want := IsPrime(int(x)), big.NewInt(x).ProbablyPrime(20); got != want {
+			t.Fatalf("IsPrime(%d) = %v, ожидалось %v", x, got, want)
+		}
+	}
+	// Сильные псевдопростые по нескольким основаниям и простые около 2^63
+	for _, tt := range []struct {
+		n     int
+		prime bool
+	}{
+		{3215031751, false},
+		{3825123056546413051, false},
+		{math.MaxInt64, false}, // 7²·73·127·337·92737·649657
+		{9223372036854775783, true},
+		{1_000_000_007 * 998_244_353, false},
+	} {
+		if got := IsPrime(tt.n); got != tt.prime {
+			t.Errorf("IsPrime(%d) = %v, ожидалось %v", tt.n, got, tt.prime)
+		}
+	}
+}
+
+// product восстанавливает число по разложению
+func product(fs []Factor) int {
+	res := 1
+	for _, f := range fs {
+		for range f.E {
+			res *= f.P
+		}
+	}
+	return res
+}
+
+func TestFactorize(t *testing.T) {
+	for x := 1; x <= 20000; x++ {
+		if got, want := Factorize(x), naiveFactorize(x); !slices.Equal(got, want) {
+			t.Fatalf("Factorize(%d) = %v, ожидалось %v", x, got, want)
+		}
+	}
+	big := []int{
+		math.MaxInt64,
+		1_000_000_007 * 998_244_353,
+		999_999_937 * 999_999_937, // квадрат большого простого
+		101 * 101 * 103,           // малые делители сразу за границей пробного деления
+		1 << 62,                   // степень двойки
+		9223372036854775783,       // простое
+		4611686014132420609,       // (2^31−1)²
+		2 * 3 * 5 * 7 * 11 * 13 * 17 * 19 * 23 * 29 * 31 * 37 * 41 * 43 * 47,
+	}
+	r := rand.New(rand.NewSource(2))
+	for range 200 {
+		big = append(big, int(r.Int63()))
+	}
+	for _, x := range big {
+		fs := Factorize(x)
+		if product(fs) != x {
+			t.Fatalf("Factorize(%d) = %v: произведение %d", x, fs, product(fs))
+		}
+		for i, f := range fs {
+			if !IsPrime(f.P) || f.E < 1 || i > 0 && fs[i-1].P >= f.P {
+				t.Fatalf("Factorize(%d) = %v: множители не простые или не по возрастанию", x, fs)
+			}
+		}
+	}
+}
+
+func TestDivisors(t *testing.T) {
+	if got, want := Divisors(36), []int{1, 2, 3, 4, 6, 9, 12, 18, 36}; !slices.Equal(got, want) {
+		t.Errorf("Divisors(36) = %v, ожидалось %v", got, want)
+	}
+	if got := Divisors(1); !slices.Equal(got, []int{1}) {
+		t.Errorf("Divisors(1) = %v, ожидалось [1]", got)
+	}
+	for x := 1; x <= 3000; x++ {
+		var want []int
+		for d := 1; d <= x; d++ {
+			if x%d == 0 {
+				want = append(want, d)
+			}
+		}
+		if got := Divisors(x); !slices.Equal(got, want) {
+			t.Fatalf("Divisors(%d) = %v, ожидалось %v", x, got, want)
+		}
+		if DivisorCount(x) != len(want) {
+			t.Fatalf("DivisorCount(%d) = %d, ожидалось %d", x, DivisorCount(x), len(want))
+		}
+	}
+	// 963761198400 — наибольшее число делителей до 10^12
+	if got := DivisorCount(963761198400); got != 6720 {
+		t.Errorf("DivisorCount(963761198400) = %d, ожидалось 6720", got)
+	}
+}
+
+func TestPhi(t *testing.T) {
+	gcd := func(a, b int) int {
+		for b != 0 {
+			a, b = b, a%b
+		}
+		return a
+	}
+	for n := 1; n <= 500; n++ {
+		want := 0
+		for k := 1; k <= n; k++ {
+			if gcd(n, k) == 1 {
+				want++
+			}
+		}
+		if got := Phi(n); got != want {
+			t.Fatalf("Phi(%d) = %d, ожидалось %d", n, got, want)
+		}
+	}
+	if got := Phi(1_000_000_007 * 998_244_353); got != 1_000_000_006*998_244_352 {
+		t.Errorf("Phi(p·q) = %d", got)
+	}
+}
+
+func TestLegendre(t *testing.T) {
+	// v_p(n!) против разложения n! = 1·2·…·n по решету
+	s := NewSieve(1000)
+	exp := map[int]int{}
+	for n := 1; n <= 1000; n++ {
+		for _, f := range s.Factorize(n) {
+			exp[f.P] += f.E
+		}
+		for _, p := range []int{2, 3, 5, 7, 31, 997} {
+			if got := Legendre(n, p); got != exp[p] {
+				t.Fatalf("Legendre(%d, %d) = %d, ожидалось %d", n, p, got, exp[p])
+			}
+		}
+	}
+	// Без переполнения p^i около 2^63
+	if got := Legendre(math.MaxInt64, 2); got != math.MaxInt64-63 {
+		t.Errorf("Legendre(MaxInt64, 2) = %d, ожидалось %d", got, math.MaxInt64-63)
+	}
+}
+
+func TestIntSqrt(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 3, 4, 8, 9, 10, 99, 100, 1 << 52, 1<<52 + 1, 4611686014132420609, math.MaxInt64} {
+		r := IntSqrt(n)
+		if r < 0 || r > n/max(r, 1) || (r+1) <= n/(r+1) {
+			t.Errorf("IntSqrt(%d) = %d", n, r)
+		}
+	}
+	if got := IntSqrt(math.MaxInt64); got != 3037000499 {
+		t.Errorf("IntSqrt(MaxInt64) = %d, ожидалось 3037000499", got)
+	}
+}
+
+func TestPrimesBetween(t *testing.T) {
+	s := NewSieve(300000)
+	for _, tt := range [][2]int{{10, 5}, {0, 1}, {0, 2}, {7, 7}, {8, 8}, {10, 20}, {1, 300000}, {65530, 65540}, {131072, 200000}} {
+		var got, want []int
+		for p := range PrimesBetween(tt[0], tt[1]) {
+			got = append(got, p)
+		}
+		for x := max(tt[0], 0); x <= tt[1]; x++ {
+			if s.IsPrime(x) {
+				want = append(want, x)
+			}
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("PrimesBetween(%d, %d): %d простых, ожидалось %d", tt[0], tt[1], len(got), len(want))
+		}
+	}
+
+	// Отрезок у 10^12 без решета до 10^12; досрочная остановка
+	const low = 1_000_000_000_000
+	count := 0
+	for p := range PrimesBetween(low, low+1000) {
+		if !IsPrime(p) {
+			t.Fatalf("PrimesBetween вернул составное %d", p)
+		}
+		count++
+	}
+	if count == 0 {
+		t.Error("PrimesBetween у 10^12 не нашёл простых")
+	}
+	for p := range PrimesBetween(low, 2*low) {
+		if p != 1_000_000_000_039 {
+			t.Errorf("первое простое после 10^12 = %d, ожидалось 1000000000039", p)
+		}
+		break
+	}
+}
+
+func BenchmarkFactorize(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	xs := make([]int, 100)
+	for i := range xs {
+		xs[i] = int(r.Int63())
+	}
+	for b.Loop() {
+		for _, x := range xs {
+			Factorize(x)
+		}
+	}
+}
+
+func BenchmarkNewSieve(b *testing.B) {
+	for b.Loop() {
+		NewSieve(1_000_000)
+	}
+}
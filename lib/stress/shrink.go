@@ -0,0 +1,103 @@
+package stress
+
+import (
+	"slices"
+	"sort"
+)
+
+// maxShrinkSteps ограничивает уменьшение, если Shrink всё же зациклится
+const maxShrinkSteps = 10000
+
+// Minimize жадно уменьшает вход ошибки: берёт первый кандидат из Shrink, на
+// котором решение всё ещё ошибается, пока такие есть. Возвращает уменьшенную
+// ошибку и число сделанных шагов; Iteration и Seed остаются от исходной.
+func (p Problem[In, Out]) Minimize(f *Failure[In, Out]) (*Failure[In, Out], int) {
+	steps := 0
+	for steps < maxShrinkSteps {
+		next := p.firstFailing(p.Shrink(f.In))
+		if next == nil {
+			break
+		}
+		next.Iteration, next.Seed = f.Iteration, f.Seed
+		f = next
+		steps++
+	}
+	return f, steps
+}
+
+func (p Problem[In, Out]) firstFailing(candidates []In) *Failure[In, Out] {
+	for _, in := range candidates {
+		if f := p.Try(in); f != nil {
+			return f
+		}
+	}
+	return nil
+}
+
+// ShrinkSlice удаляет из s куски длиной n/2, n/4, ..., 1 во всех позициях
+// (как в delta debugging), не оставляя меньше minLen элементов
+func ShrinkSlice[T any](s []T, minLen int) [][]T {
+	var out [][]T
+	for size := len(s) / 2; size >= 1; size /= 2 {
+		if len(s)-size < minLen {
+			continue
+		}
+		for start := 0; start+size <= len(s); start += size {
+			out = append(out, slices.Concat(s[:start], s[start+size:]))
+		}
+	}
+	return out
+}
+
+// ShrinkInt возвращает значения между x и target, от target к x:
+// target, x - (x-target)/2, ..., x∓1
+func ShrinkInt(x, target int64) []int64 {
+	var out []int64
+	for d := x - target; d != 0; d /= 2 {
+		out = append(out, x-d)
+	}
+	return out
+}
+
+// ShrinkEach уменьшает элементы s по одному: для каждой позиции i и каждого
+// значения из shrink(s[i]) возвращает копию s с заменённым s[i]
+func ShrinkEach[T any](s []T, shrink func(T) []T) [][]T {
+	var out [][]T
+	for i, v := range s {
+		for _, w := range shrink(v) {
+			c := slices.Clone(s)
+			c[i] = w
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// ShrinkPerm уменьшает перестановку 1..n, оставаясь перестановкой: удаляет
+// элементы и перенумеровывает оставшиеся в 1..k с сохранением порядка,
+// затем пробует поменять соседние инверсии местами, приближая к тождественной
+func ShrinkPerm(p []int, minLen int) [][]int {
+	out := ShrinkSlice(p, minLen)
+	for i := range out {
+		out[i] = Compress(out[i])
+	}
+	for i := 0; i+1 < len(p); i++ {
+		if p[i] > p[i+1] {
+			c := slices.Clone(p)
+			c[i], c[i+1] = c[i+1], c[i]
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// Compress заменяет различные числа их рангами 1..k; s не меняется
+func Compress(s []int) []int {
+	sorted := slices.Clone(s)
+	sort.Ints(sorted)
+	out := make([]int, len(s))
+	for i, v := range s {
+		out[i] = sort.SearchInts(sorted, v) + 1
+	}
+	return out
+}
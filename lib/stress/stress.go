@@ -0,0 +1,223 @@
+// Package stress — стресс-тестирование решений: случайные входы из генератора
+// сравниваются с ответом медленного, но заведомо верного решения (оракула).
+//
+// Каждая итерация получает свой сид, поэтому любую найденную ошибку можно
+// повторить одной итерацией:
+//
+//	STRESS_SEED=<сид> STRESS_ITERS=1 go test -run '^TestStress$' ./NN
+//
+// Без STRESS_SEED начальный сид берётся из текущего времени, без STRESS_ITERS
+// выполняется Problem.Iterations итераций (в -short — в десять раз меньше).
+//
+// Найденный вход уменьшается функцией Problem.Shrink и сохраняется рядом с
+// задачей в testdata/stress/<имя теста>/; при следующих запусках сохранённые
+// входы проверяются первыми. Туда же можно положить вход из сообщения об
+// ошибке — Run проверит и уменьшит его.
+package stress
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// Переменные окружения, задающие начальный сид и число итераций
+const (
+	SeedEnv  = "STRESS_SEED"
+	ItersEnv = "STRESS_ITERS"
+)
+
+// DefaultIterations — число итераций, если Problem.Iterations не задано
+const DefaultIterations = 1000
+
+// ErrSkip возвращается из Problem.Check, если вход вне гарантий условия
+// (например, ответа не существует) и итерацию нужно пропустить
+var ErrSkip = errors.New("stress: вход вне гарантий условия")
+
+// Problem описывает задачу для стресс-тестирования
+type Problem[In, Out any] struct {
+	// Gen строит случайный вход; r детерминирован сидом итерации
+	Gen func(r *rand.Rand) In
+	// Solve — проверяемое решение
+	Solve func(In) Out
+	// Oracle — медленное заведомо верное решение
+	Oracle func(In) Out
+	// Check сравнивает ответ решения с ответом оракула; nil — ответ верный.
+	// По умолчанию ответы должны совпадать (reflect.DeepEqual). Нужен задачам
+	// с несколькими верными ответами.
+	Check func(in In, got, want Out) error
+	// Format записывает вход в формате условия; по умолчанию %+v
+	Format func(In) string
+	// Parse читает вход, записанный Format; без него сохранённые входы
+	// не проверяются и новые не сохраняются
+	Parse func(string) (In, error)
+	// Shrink возвращает входы проще данного, от самых сильных упрощений к
+	// самым слабым; Minimize жадно берёт первый, на котором решение всё ещё
+	// ошибается. Каждый кандидат должен быть строго проще, иначе
+	// уменьшение не закончится.
+	Shrink func(In) []In
+	// Iterations — число итераций; 0 — DefaultIterations
+	Iterations int
+}
+
+// Failure — вход, на котором решение ошиблось
+type Failure[In, Out any] struct {
+	Iteration int
+	Seed      int64 // сид итерации: Gen(rand.New(rand.NewSource(Seed))) вернёт In
+	In        In
+	Got, Want Out
+	Err       error // несовпадение с оракулом или паника решения
+}
+
+// Find прогоняет iterations итераций с сидами seed, seed+1, ... и возвращает
+// первую ошибку решения или nil, если все ответы верны
+func (p Problem[In, Out]) Find(seed int64, iterations int) *Failure[In, Out] {
+	for i := 0; i < iterations; i++ {
+		s := seed + int64(i)
+		in := p.Gen(rand.New(rand.NewSource(s)))
+		if f := p.Try(in); f != nil {
+			f.Iteration, f.Seed = i, s
+			return f
+		}
+	}
+	return nil
+}
+
+// Try проверяет решение на одном входе; nil — ответ верный или вход пропущен.
+// Паника решения — ошибка без вызова оракула, которому большой вход не по силам.
+func (p Problem[In, Out]) Try(in In) *Failure[In, Out] {
+	got, err := p.solve(in)
+	if err != nil {
+		return &Failure[In, Out]{In: in, Got: got, Err: err}
+	}
+	want := p.Oracle(in)
+	if err := p.check(in, got, want); err != nil && !errors.Is(err, ErrSkip) {
+		return &Failure[In, Out]{In: in, Got: got, Want: want, Err: err}
+	}
+	return nil
+}
+
+// solve вызывает решение, превращая панику в ошибку
+func (p Problem[In, Out]) solve(in In) (out Out, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("паника: %v", r)
+		}
+	}()
+	return p.Solve(in), nil
+}
+
+func (p Problem[In, Out]) check(in In, got, want Out) error {
+	if p.Check != nil {
+		return p.Check(in, got, want)
+	}
+	if !reflect.DeepEqual(got, want) {
+		return fmt.Errorf("ответ %+v, ожидалось %+v", got, want)
+	}
+	return nil
+}
+
+// FormatInput записывает вход с помощью Format или %+v
+func (p Problem[In, Out]) FormatInput(in In) string {
+	if p.Format != nil {
+		return p.Format(in)
+	}
+	return fmt.Sprintf("%+v\n", in)
+}
+
+// Run проверяет сохранённые входы и стресс-тестирует решение. На первой
+// ошибке вход уменьшается и сохраняется, а тест останавливается с сидом и
+// входом для повторения.
+func Run[In, Out any](t testing.TB, p Problem[In, Out]) {
+	t.Helper()
+	dir := filepath.Join(testdataDir, filepath.FromSlash(t.Name()))
+	if p.Parse != nil {
+		f, name, err := p.replay(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if f != nil {
+			p.fail(t, dir, f, "сохранённый вход "+name)
+			return
+		}
+	}
+
+	seed, iterations, err := config(p.Iterations, testing.Short())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("%s=%d, итераций: %d", SeedEnv, seed, iterations)
+
+	f := p.Find(seed, iterations)
+	if f == nil {
+		return
+	}
+	p.fail(t, dir, f, fmt.Sprintf("итерация %d, повторить: %s=%d %s=1 go test -run '^%s$'",
+		f.Iteration, SeedEnv, f.Seed, ItersEnv, t.Name()))
+}
+
+// fail уменьшает вход, сохраняет его и останавливает тест
+func (p Problem[In, Out]) fail(t testing.TB, dir string, f *Failure[In, Out], origin string) {
+	t.Helper()
+	steps := 0
+	if p.Shrink != nil {
+		f, steps = p.Minimize(f)
+	}
+	input := p.FormatInput(f.In)
+	msg := fmt.Sprintf("%s\n%v\nввод", origin, f.Err)
+	if steps > 0 {
+		msg += fmt.Sprintf(" (шагов уменьшения: %d)", steps)
+	}
+	msg += ":\n" + input
+	if p.Parse != nil {
+		path, err := save(dir, input)
+		if err != nil {
+			msg += fmt.Sprintf("не удалось сохранить: %v", err)
+		} else {
+			msg += "сохранён в " + path
+		}
+	}
+	t.Fatal(msg)
+}
+
+// config читает начальный сид и число итераций из окружения
+func config(iterations int, short bool) (int64, int, error) {
+	if iterations <= 0 {
+		iterations = DefaultIterations
+	}
+	if short {
+		iterations = max(1, iterations/10)
+	}
+	if v := os.Getenv(ItersEnv); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return 0, 0, fmt.Errorf("%s=%q: нужно положительное число", ItersEnv, v)
+		}
+		iterations = n
+	}
+
+	seed := time.Now().UnixNano()
+	if v := os.Getenv(SeedEnv); v != "" {
+		s, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("%s=%q: нужно целое число", SeedEnv, v)
+		}
+		seed = s
+	}
+	return seed, iterations, nil
+}
+
+// Perm возвращает случайную перестановку чисел 1..n
+func Perm(r *rand.Rand, n int) []int {
+	p := r.Perm(n)
+	for i := range p {
+		p[i]++
+	}
+	return p
+}
@@ -0,0 +1,228 @@
+package stress
+
+import (
+	"errors"
+	"math/rand"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// square — задача «возвести в квадрат» с ошибкой в solve при x = 7
+var square = Problem[int, int]{
+	Gen:    func(r *rand.Rand) int { return r.Intn(10) },
+	Oracle: func(x int) int { return x * x },
+	Solve: func(x int) int {
+		if x == 7 {
+			return 0
+		}
+		return x * x
+	},
+}
+
+func TestFind(t *testing.T) {
+	f := square.Find(1, 1000)
+	if f == nil {
+		t.Fatal("ошибка при x = 7 не найдена")
+	}
+	if f.In != 7 || f.Got != 0 || f.Want != 49 {
+		t.Errorf("найдено %+v, ожидался вход 7", f)
+	}
+	// Сид итерации воспроизводит вход
+	if in := square.Gen(rand.New(rand.NewSource(f.Seed))); in != f.In {
+		t.Errorf("сид %d дал вход %d, ожидался %d", f.Seed, in, f.In)
+	}
+	if g := square.Find(f.Seed, 1); g == nil || g.In != f.In {
+		t.Errorf("повтор с сидом %d не нашёл ошибку", f.Seed)
+	}
+}
+
+func TestFindNoFailure(t *testing.T) {
+	p := square
+	p.Solve = p.Oracle
+	if f := p.Find(1, 100); f != nil {
+		t.Errorf("найдена ошибка у верного решения: %+v", f)
+	}
+}
+
+func TestTryPanic(t *testing.T) {
+	p := square
+	p.Solve = func(x int) int { return 100 / (x - 3) }
+	f := p.Try(3)
+	if f == nil || !strings.Contains(f.Err.Error(), "паника") {
+		t.Errorf("паника решения не превратилась в ошибку: %+v", f)
+	}
+}
+
+func TestTrySkip(t *testing.T) {
+	p := square
+	p.Check = func(x, got, want int) error {
+		if x == 7 {
+			return ErrSkip
+		}
+		if got != want {
+			return errors.New("WA")
+		}
+		return nil
+	}
+	if f := p.Try(7); f != nil {
+		t.Errorf("вход вне гарантий не пропущен: %+v", f)
+	}
+	if f := p.Find(1, 1000); f != nil {
+		t.Errorf("найдена ошибка, хотя единственная пропущена: %+v", f)
+	}
+}
+
+func TestConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		seed       string
+		iters      string
+		iterations int
+		short      bool
+		wantSeed   int64
+		wantIters  int
+		wantErr    bool
+	}{
+		{"по умолчанию", "5", "", 0, false, 5, DefaultIterations, false},
+		{"из задачи", "5", "", 300, false, 5, 300, false},
+		{"short", "5", "", 300, true, 5, 30, false},
+		{"из окружения", "-7", "3", 300, true, -7, 3, false},
+		{"плохой сид", "x", "", 0, false, 0, 0, true},
+		{"плохое число итераций", "1", "0", 0, false, 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(SeedEnv, tt.seed)
+			t.Setenv(ItersEnv, tt.iters)
+			seed, iters, err := config(tt.iterations, tt.short)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ошибка %v, ожидалась: %v", err, tt.wantErr)
+			}
+			if err == nil && (seed != tt.wantSeed || iters != tt.wantIters) {
+				t.Errorf("сид %d, итераций %d; ожидалось %d и %d", seed, iters, tt.wantSeed, tt.wantIters)
+			}
+		})
+	}
+}
+
+func TestPerm(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	p := Perm(r, 50)
+	seen := make([]bool, 51)
+	for _, v := range p {
+		if v < 1 || v > 50 || seen[v] {
+			t.Fatalf("%v — не перестановка 1..50", p)
+		}
+		seen[v] = true
+	}
+}
+
+// hasSeven — сумма чисел; solve ошибается, если среди них есть 7
+var hasSeven = Problem[[]int64, int64]{
+	Gen: func(r *rand.Rand) []int64 {
+		s := make([]int64, 1+r.Intn(30))
+		for i := range s {
+			s[i] = int64(r.Intn(100))
+		}
+		return s
+	},
+	Oracle: sum,
+	Solve: func(s []int64) int64 {
+		if slices.Contains(s, 7) {
+			return 0
+		}
+		return sum(s)
+	},
+	Shrink: func(s []int64) [][]int64 {
+		return append(ShrinkSlice(s, 1), ShrinkEach(s, func(x int64) []int64 { return ShrinkInt(x, 0) })...)
+	},
+}
+
+func sum(s []int64) int64 {
+	total := int64(0)
+	for _, x := range s {
+		total += x
+	}
+	return total
+}
+
+func TestMinimize(t *testing.T) {
+	f := hasSeven.Try([]int64{50, 3, 7, 99, 7, 12, 40})
+	if f == nil {
+		t.Fatal("ошибка не найдена")
+	}
+	f, steps := hasSeven.Minimize(f)
+	if !slices.Equal(f.In, []int64{7}) || steps == 0 {
+		t.Errorf("уменьшено до %v за %d шагов, ожидалось [7]", f.In, steps)
+	}
+	if f.Got != 0 || f.Want != 7 {
+		t.Errorf("ответы %d и %d не пересчитаны для уменьшенного входа", f.Got, f.Want)
+	}
+}
+
+func TestShrinkSlice(t *testing.T) {
+	got := ShrinkSlice([]int{1, 2, 3}, 1)
+	want := [][]int{{2, 3}, {1, 3}, {1, 2}}
+	if !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("ShrinkSlice = %v, ожидалось %v", got, want)
+	}
+	if got := ShrinkSlice([]int{1, 2}, 2); len(got) != 0 {
+		t.Errorf("ShrinkSlice с minLen = len: %v, ожидалось пусто", got)
+	}
+}
+
+func TestShrinkInt(t *testing.T) {
+	tests := []struct {
+		x, target int64
+		want      []int64
+	}{
+		{10, 0, []int64{0, 5, 8, 9}},
+		{-6, 1, []int64{1, -3, -5}},
+		{4, 4, nil},
+	}
+	for _, tt := range tests {
+		if got := ShrinkInt(tt.x, tt.target); !slices.Equal(got, tt.want) {
+			t.Errorf("ShrinkInt(%d, %d) = %v, ожидалось %v", tt.x, tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestShrinkPerm(t *testing.T) {
+	for _, q := range ShrinkPerm([]int{3, 1, 4, 2}, 2) {
+		seen := make([]bool, len(q)+1)
+		for _, v := range q {
+			if v < 1 || v > len(q) || seen[v] {
+				t.Fatalf("%v — не перестановка", q)
+			}
+			seen[v] = true
+		}
+	}
+	if got := Compress([]int{40, 10, 30}); !slices.Equal(got, []int{3, 1, 2}) {
+		t.Errorf("Compress = %v, ожидалось [3 1 2]", got)
+	}
+}
+
+func TestSaveReplay(t *testing.T) {
+	p := square
+	p.Parse = func(s string) (int, error) { return strconv.Atoi(strings.TrimSpace(s)) }
+	dir := filepath.Join(t.TempDir(), "TestStress")
+
+	if f, _, err := p.replay(dir); f != nil || err != nil {
+		t.Fatalf("пустой каталог: %v, %v", f, err)
+	}
+	for _, input := range []string{"3\n", "7\n", "7\n"} {
+		if _, err := save(dir, input); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if files, _ := filepath.Glob(filepath.Join(dir, "*.txt")); len(files) != 2 {
+		t.Errorf("сохранено файлов: %d, ожидалось 2", len(files))
+	}
+	f, name, err := p.replay(dir)
+	if err != nil || f == nil || f.In != 7 {
+		t.Errorf("replay = %+v, %q, %v; ожидалась ошибка на 7", f, name, err)
+	}
+}
@@ -0,0 +1,52 @@
+package stress
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// testdataDir — каталог сохранённых входов относительно каталога задачи
+const testdataDir = "testdata/stress"
+
+// replay проверяет сохранённые входы из dir в порядке имён и возвращает
+// первую ошибку решения с именем файла
+func (p Problem[In, Out]) replay(dir string) (*Failure[In, Out], string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.txt"))
+	if err != nil {
+		return nil, "", err
+	}
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, "", err
+		}
+		in, err := p.Parse(string(data))
+		if err != nil {
+			return nil, "", fmt.Errorf("%s: %w", path, err)
+		}
+		if f := p.Try(in); f != nil {
+			return f, path, nil
+		}
+	}
+	return nil, "", nil
+}
+
+// save записывает вход в dir под именем из хэша содержимого, как go test
+// сохраняет входы фаззинга; повторное сохранение того же входа ничего не меняет
+func save(dir, input string) (string, error) {
+	sum := sha256.Sum256([]byte(input))
+	path := filepath.Join(dir, hex.EncodeToString(sum[:8])+".txt")
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	return path, os.WriteFile(path, []byte(input), 0o644)
+}
@@ -0,0 +1,78 @@
+package strs
+
+import (
+	"math/bits"
+	"math/rand/v2"
+)
+
+// HashMod — модуль полиномиальных хэшей, простое 2^61 − 1: умножение
+// сводится к сдвигам, вероятность коллизии двух строк длины n — n/2^61
+const HashMod = 1<<61 - 1
+
+// mulMod возвращает a·b mod 2^61 − 1 для a, b < 2^61
+func mulMod(a, b uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	// a·b = hi·2^64 + lo = (hi·2^3 + lo>>61)·2^61 + lo mod 2^61, а 2^61 ≡ 1
+	res := (hi<<3 | lo>>61) + lo&HashMod
+	if res >= HashMod {
+		res -= HashMod
+	}
+	return res
+}
+
+// RandomBase возвращает случайное основание хэша: с фиксированным
+// основанием решение можно завалить подобранным тестом
+func RandomBase() uint64 {
+	return 1<<40 + rand.Uint64N(HashMod-1<<41)
+}
+
+// Hash — префиксные хэши строки: хэш любой подстроки за O(1)
+type Hash struct {
+	base     uint64
+	pre, pow []uint64
+}
+
+// NewHash считает префиксные хэши s с основанием base ∈ [2, 2^61 − 1).
+// Хэши сравнимы, только если основание общее
+func NewHash[S Seq](s S, base uint64) *Hash {
+	if base < 2 || base >= HashMod {
+		panic("strs: основание вне [2, 2^61 − 1)")
+	}
+	n := len(s)
+	h := &Hash{base: base, pre: make([]uint64, n+1), pow: make([]uint64, n+1)}
+	h.pow[0] = 1
+	for i := range n {
+		// +1, чтобы нулевой байт не совпадал с пустой строкой
+		h.pre[i+1] = (mulMod(h.pre[i], base) + uint64(s[i]) + 1) % HashMod
+		h.pow[i+1] = mulMod(h.pow[i], base)
+	}
+	return h
+}
+
+// Len возвращает длину строки
+func (h *Hash) Len() int {
+	return len(h.pre) - 1
+}
+
+// Sub возвращает хэш s[l:r]
+func (h *Hash) Sub(l, r int) uint64 {
+	return (h.pre[r] + HashMod - mulMod(h.pre[l], h.pow[r-l])) % HashMod
+}
+
+// CommonPrefix возвращает длину общего префикса подстрок a[i:] и b[j:]
+// двоичным поиском по хэшам, O(log n); хэши с одним основанием
+func CommonPrefix(a *Hash, i int, b *Hash, j int) int {
+	if a.base != b.base {
+		panic("strs: хэши с разными основаниями")
+	}
+	lo, hi := 0, min(a.Len()-i, b.Len()-j)
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if a.Sub(i, i+mid) == b.Sub(j, j+mid) {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	return lo
+}
@@ -0,0 +1,72 @@
+package strs
+
+import "math/bits"
+
+// minTable — разреженная таблица для минимума на отрезке за O(1):
+// level[j][i] — минимум a[i : i+2^j]
+type minTable struct {
+	level [][]int32
+}
+
+func newMinTable(a []int32) minTable {
+	t := minTable{level: [][]int32{a}}
+	for j := 1; 1<<j <= len(a); j++ {
+		prev, half := t.level[j-1], 1<<(j-1)
+		cur := make([]int32, len(a)-1<<j+1)
+		for i := range cur {
+			cur[i] = min(prev[i], prev[i+half])
+		}
+		t.level = append(t.level, cur)
+	}
+	return t
+}
+
+// query возвращает минимум a[l:r], l < r
+func (t minTable) query(l, r int) int32 {
+	j := bits.Len(uint(r-l)) - 1
+	return min(t.level[j][l], t.level[j][r-1<<j])
+}
+
+// LCP отвечает на запросы длины общего префикса суффиксов двух строк s и
+// t за O(1). Строится суффиксный массив s + разделитель + t, массив lcp и
+// разреженная таблица по нему: O((n+m) log(n+m)) времени и памяти
+type LCP struct {
+	n, m  int
+	rank  []int32
+	table minTable
+}
+
+// NewLCP строит LCP для строк s и t; для суффиксов одной строки —
+// NewLCP(s, s)
+func NewLCP[S Seq](s, t S) *LCP {
+	n, m := len(s), len(t)
+	// Разделитель 256 не совпадает ни с одним байтом, поэтому общий
+	// префикс не переходит из s в t
+	a := make([]int32, n+1+m)
+	for i := range n {
+		a[i] = int32(s[i])
+	}
+	a[n] = 256
+	for j := range m {
+		a[n+1+j] = int32(t[j])
+	}
+	sa := suffixArray(a, 257)
+	rank := inverse(sa)
+	return &LCP{n: n, m: m, rank: rank, table: newMinTable(kasai(a, sa, rank))}
+}
+
+// Query возвращает длину общего префикса s[i:] и t[j:]; 0 ≤ i ≤ len(s),
+// 0 ≤ j ≤ len(t)
+func (l *LCP) Query(i, j int) int {
+	if i < 0 || i > l.n || j < 0 || j > l.m {
+		panic("strs: индекс вне строки")
+	}
+	if i == l.n || j == l.m {
+		return 0
+	}
+	a, b := int(l.rank[i]), int(l.rank[l.n+1+j])
+	if a > b {
+		a, b = b, a
+	}
+	return int(l.table.query(a+1, b+1))
+}
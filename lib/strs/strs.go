@@ -0,0 +1,197 @@
+// Package strs — строковые алгоритмы: Z-функция, префикс-функция,
+// суффиксный массив с LCP и полиномиальные хэши.
+//
+// Функции принимают и string, и []byte. Для сравнения суффиксов двух строк
+// есть LCP: после построения за O((n+m) log(n+m)) длина общего префикса
+// s[i:] и t[j:] находится за O(1) без таблицы n×m:
+//
+//	lcp := strs.NewLCP(s, t)
+//	lcp.Query(i, j) // длина общего префикса s[i:] и t[j:]
+package strs
+
+// Seq — строка или срез байтов
+type Seq interface {
+	~string | ~[]byte
+}
+
+// ZFunction возвращает z, где z[i] — длина общего префикса s и s[i:];
+// z[0] = len(s). O(n)
+func ZFunction[S Seq](s S) []int {
+	n := len(s)
+	z := make([]int, n)
+	if n == 0 {
+		return z
+	}
+	z[0] = n
+	for i, l, r := 1, 0, 0; i < n; i++ {
+		if i < r {
+			z[i] = min(r-i, z[i-l])
+		}
+		for i+z[i] < n && s[z[i]] == s[i+z[i]] {
+			z[i]++
+		}
+		if i+z[i] > r {
+			l, r = i, i+z[i]
+		}
+	}
+	return z
+}
+
+// PrefixFunction возвращает π, где π[i] — длина наибольшего собственного
+// префикса s[:i+1], который одновременно его суффикс. O(n)
+func PrefixFunction[S Seq](s S) []int {
+	pi := make([]int, len(s))
+	for i := 1; i < len(s); i++ {
+		k := pi[i-1]
+		for k > 0 && s[i] != s[k] {
+			k = pi[k-1]
+		}
+		if s[i] == s[k] {
+			k++
+		}
+		pi[i] = k
+	}
+	return pi
+}
+
+// SuffixArray возвращает начала суффиксов s в лексикографическом порядке.
+// O(n log n)
+func SuffixArray[S Seq](s S) []int {
+	a := make([]int32, len(s))
+	for i := range a {
+		a[i] = int32(s[i])
+	}
+	return widen(suffixArray(a, 256))
+}
+
+// LCPArray возвращает lcp для суффиксного массива sa строки s: lcp[k] —
+// длина общего префикса суффиксов sa[k−1] и sa[k], lcp[0] = 0 (Kasai, O(n))
+func LCPArray[S Seq](s S, sa []int) []int {
+	a := make([]int32, len(s))
+	for i := range a {
+		a[i] = int32(s[i])
+	}
+	sa32 := make([]int32, len(sa))
+	for i, p := range sa {
+		sa32[i] = int32(p)
+	}
+	return widen(kasai(a, sa32, inverse(sa32)))
+}
+
+func widen(a []int32) []int {
+	res := make([]int, len(a))
+	for i, v := range a {
+		res[i] = int(v)
+	}
+	return res
+}
+
+// inverse возвращает rank: rank[sa[k]] = k
+func inverse(sa []int32) []int32 {
+	rank := make([]int32, len(sa))
+	for k, p := range sa {
+		rank[p] = int32(k)
+	}
+	return rank
+}
+
+// suffixArray — удвоение префиксов с сортировкой подсчётом; символы a —
+// из [0, alphabet)
+func suffixArray(a []int32, alphabet int) []int32 {
+	n := len(a)
+	sa := make([]int32, n)
+	if n == 0 {
+		return sa
+	}
+	class := make([]int32, n)
+	cnt := make([]int32, max(alphabet, n)+1)
+	for _, c := range a {
+		cnt[c+1]++
+	}
+	for c := 1; c <= alphabet; c++ {
+		cnt[c] += cnt[c-1]
+	}
+	for i, c := range a {
+		sa[cnt[c]] = int32(i)
+		cnt[c]++
+	}
+	classes := int32(1)
+	for k := 1; k < n; k++ {
+		if a[sa[k]] != a[sa[k-1]] {
+			classes++
+		}
+		class[sa[k]] = classes - 1
+	}
+
+	tmp := make([]int32, n)
+	next := make([]int32, n)
+	// Суффиксы уже отсортированы по первым h символам; сортируем по парам
+	// (класс [i, i+h), класс [i+h, i+2h)), отсутствующая вторая половина —
+	// меньше любой
+	for h := 1; h < n && int(classes) < n; h <<= 1 {
+		// По второй половине: сначала суффиксы без неё, затем sa[k] − h
+		p := 0
+		for i := n - h; i < n; i++ {
+			tmp[p] = int32(i)
+			p++
+		}
+		for _, s := range sa {
+			if int(s) >= h {
+				tmp[p] = s - int32(h)
+				p++
+			}
+		}
+		// Устойчиво по первой половине
+		clear(cnt[:classes+1])
+		for _, c := range class {
+			cnt[c+1]++
+		}
+		for c := int32(1); c <= classes; c++ {
+			cnt[c] += cnt[c-1]
+		}
+		for _, s := range tmp {
+			c := class[s]
+			sa[cnt[c]] = s
+			cnt[c]++
+		}
+		second := func(i int32) int32 {
+			if int(i)+h < n {
+				return class[int(i)+h]
+			}
+			return -1
+		}
+		classes = 1
+		next[sa[0]] = 0
+		for k := 1; k < n; k++ {
+			cur, prev := sa[k], sa[k-1]
+			if class[cur] != class[prev] || second(cur) != second(prev) {
+				classes++
+			}
+			next[cur] = classes - 1
+		}
+		class, next = next, class
+	}
+	return sa
+}
+
+// kasai считает lcp соседних суффиксов sa
+func kasai(a, sa, rank []int32) []int32 {
+	n := len(a)
+	lcp := make([]int32, n)
+	h := 0
+	for i := range n {
+		if rank[i] == 0 {
+			h = 0
+			continue
+		}
+		j := int(sa[rank[i]-1])
+		for i+h < n && j+h < n && a[i+h] == a[j+h] {
+			h++
+		}
+		lcp[rank[i]] = int32(h)
+		if h > 0 {
+			h--
+		}
+	}
+	return lcp
+}
@@ -0,0 +1,150 @@
+package strs
+
+import (
+	"math/rand"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// random возвращает строку длины n над первыми k буквами: малый алфавит
+// даёт длинные повторы
+func random(r *rand.Rand, n, k int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte('a' + r.Intn(k))
+	}
+	return string(b)
+}
+
+func naiveLCP(s, t string) int {
+	k := 0
+	for k < len(s) && k < len(t) && s[k] == t[k] {
+		k++
+	}
+	return k
+}
+
+func TestZAndPrefixFunction(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for range 500 {
+		s := random(r, r.Intn(40), 1+r.Intn(3))
+		z, pi := ZFunction(s), PrefixFunction([]byte(s))
+		for i := range s {
+			if want := naiveLCP(s, s[i:]); z[i] != want {
+				t.Fatalf("%q: z[%d] = %d, ожидалось %d", s, i, z[i], want)
+			}
+			want := 0
+			for k := 1; k <= i; k++ {
+				if s[:k] == s[i+1-k:i+1] {
+					want = k
+				}
+			}
+			if pi[i] != want {
+				t.Fatalf("%q: π[%d] = %d, ожидалось %d", s, i, pi[i], want)
+			}
+		}
+	}
+}
+
+func TestSuffixArray(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for range 500 {
+		s := random(r, r.Intn(60), 1+r.Intn(4))
+		want := make([]int, len(s))
+		for i := range want {
+			want[i] = i
+		}
+		sort.Slice(want, func(a, b int) bool { return s[want[a]:] < s[want[b]:] })
+		sa := SuffixArray(s)
+		if !slices.Equal(sa, want) {
+			t.Fatalf("%q: SuffixArray = %v, ожидалось %v", s, sa, want)
+		}
+		lcp := LCPArray(s, sa)
+		for k := 1; k < len(sa); k++ {
+			if want := naiveLCP(s[sa[k-1]:], s[sa[k]:]); lcp[k] != want {
+				t.Fatalf("%q: lcp[%d] = %d, ожидалось %d", s, k, lcp[k], want)
+			}
+		}
+	}
+	// Байты 0 и 255 — края алфавита
+	if sa := SuffixArray([]byte{255, 0, 255, 0}); !slices.Equal(sa, []int{3, 1, 2, 0}) {
+		t.Errorf("SuffixArray(ff 00 ff 00) = %v", sa)
+	}
+}
+
+func TestLCP(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	for range 300 {
+		k := 1 + r.Intn(3)
+		s, u := random(r, r.Intn(30), k), random(r, r.Intn(30), k)
+		if r.Intn(3) == 0 {
+			u = s + u // t начинается с s: префикс не должен выйти за разделитель
+		}
+		lcp := NewLCP(s, u)
+		for i := 0; i <= len(s); i++ {
+			for j := 0; j <= len(u); j++ {
+				if got, want := lcp.Query(i, j), naiveLCP(s[i:], u[j:]); got != want {
+					t.Fatalf("s=%q t=%q: Query(%d, %d) = %d, ожидалось %d", s, u, i, j, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestHash(t *testing.T) {
+	r := rand.New(rand.NewSource(4))
+	base := RandomBase()
+	for range 300 {
+		k := 1 + r.Intn(3)
+		s, u := random(r, r.Intn(40), k), random(r, r.Intn(40), k)
+		hs, hu := NewHash(s, base), NewHash([]byte(u), base)
+		for range 50 {
+			i, j := r.Intn(len(s)+1), r.Intn(len(u)+1)
+			if got, want := CommonPrefix(hs, i, hu, j), naiveLCP(s[i:], u[j:]); got != want {
+				t.Fatalf("s=%q t=%q: CommonPrefix(%d, %d) = %d, ожидалось %d", s, u, i, j, got, want)
+			}
+		}
+	}
+	// Нулевые байты разной длины различаются
+	h := NewHash("\x00\x00", base)
+	if h.Sub(0, 1) == h.Sub(0, 2) || h.Sub(0, 0) == h.Sub(0, 1) {
+		t.Error("хэши строк из нулевых байтов совпали")
+	}
+	if got, want := mulMod(HashMod-1, HashMod-1), uint64(1); got != want {
+		t.Errorf("mulMod(−1, −1) = %d, ожидалось %d", got, want)
+	}
+}
+
+func TestPanics(t *testing.T) {
+	for name, f := range map[string]func(){
+		"Query вне строки": func() { NewLCP("ab", "c").Query(3, 0) },
+		"основание 1":      func() { NewHash("ab", 1) },
+		"разные основания": func() { CommonPrefix(NewHash("a", 2), 0, NewHash("a", 3), 0) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: ожидалась паника", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+func BenchmarkNewLCP(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	s, u := random(r, 100000, 2), strings.Repeat("ab", 50000)
+	for b.Loop() {
+		NewLCP(s, u)
+	}
+}
+
+func BenchmarkSuffixArray(b *testing.B) {
+	s := strings.Repeat("a", 200000) // наихудший случай удвоения: log n шагов
+	for b.Loop() {
+		SuffixArray(s)
+	}
+}
@@ -0,0 +1,286 @@
+// Package trie — двоичный бор по битам чисел для задач на XOR.
+//
+// BinaryTrie хранит мультимножество чисел из [0, 2^bits) и отвечает на
+// запросы «относительно x»: наименьший и наибольший y ⊕ x, k-е по
+// возрастанию y ⊕ x, сколько y ⊕ x меньше заданного. Узлы лежат в срезах
+// (арена), а не в отдельных объектах: освобождённые при удалении узлы идут
+// в список свободных и переиспользуются, Reset очищает бор без освобождения
+// памяти. Поэтому бор растёт до наибольшего числа живых узлов и после этого
+// не выделяет память.
+//
+// В узлах можно считать свой агрегат поддерева — моноид, который узел
+// собирает из двух детей (Aggregate):
+//
+//	// distinct — число различных чисел в поддереве
+//	type distinct struct{}
+//
+//	func (distinct) Empty() int                 { return 0 }
+//	func (distinct) Leaf(mult int) int          { return 1 }
+//	func (distinct) Merge(zero, one, _ int) int { return zero + one }
+//
+//	t := trie.NewWithAggregate(30, distinct{})
+//	t.Insert(5)
+//	t.Insert(5)
+//	t.Insert(7)
+//	t.Aggregate() // 2
+package trie
+
+// Aggregate — значение поддерева, которое узел собирает из детей. Узел
+// уровня level делит числа по биту level: в zero — с нулевым битом, в one
+// — с единичным; каждый ребёнок — полное поддерево из 2^level чисел. Лист
+// (level −1) — одно число с кратностью mult ≥ 1. Отсутствующий или пустой
+// ребёнок — Empty
+type Aggregate[T any] interface {
+	Empty() T
+	Leaf(mult int) T
+	Merge(zero, one T, level int) T
+}
+
+// BinaryTrie — мультимножество чисел из [0, 2^bits) в виде двоичного бора
+// с агрегатом T в узлах
+type BinaryTrie[T any] struct {
+	bits  int
+	nodes []node[T]
+	agg   Aggregate[T]
+	free  int32   // голова списка свободных узлов (связь через child[0]); 0 — пуст
+	path  []int32 // узлы пути от корня в Add
+}
+
+// node — узел арены; поля вместе, чтобы спуск брал одну строку кэша на узел
+type node[T any] struct {
+	child [2]int32 // 0 — нет ребёнка (корень 0 ничьим ребёнком не бывает)
+	count int      // сколько чисел с кратностью в поддереве
+	val   T        // агрегат поддерева
+}
+
+// New возвращает пустой бор для чисел из [0, 2^bits) без агрегата;
+// 1 ≤ bits ≤ 62
+func New(bits int) *BinaryTrie[struct{}] {
+	return NewWithAggregate[struct{}](bits, nil)
+}
+
+// NewWithAggregate возвращает пустой бор для чисел из [0, 2^bits), в узлах
+// которого считается агрегат agg
+func NewWithAggregate[T any](bits int, agg Aggregate[T]) *BinaryTrie[T] {
+	if bits < 1 || bits > 62 {
+		panic("trie: нужно 1 ≤ bits ≤ 62")
+	}
+	t := &BinaryTrie[T]{bits: bits, agg: agg, path: make([]int32, bits+1)}
+	t.Reset()
+	return t
+}
+
+// Reset удаляет все числа; память арены остаётся за бором
+func (t *BinaryTrie[T]) Reset() {
+	t.nodes = t.nodes[:0]
+	t.free = 0
+	t.newNode() // корень
+}
+
+// newNode возвращает пустой узел из списка свободных или из конца арены
+func (t *BinaryTrie[T]) newNode() int32 {
+	var v int32
+	if t.free != 0 {
+		v = t.free
+		t.free = t.nodes[v].child[0]
+		t.nodes[v] = node[T]{}
+	} else {
+		v = int32(len(t.nodes))
+		t.nodes = append(t.nodes, node[T]{})
+	}
+	if t.agg != nil {
+		t.nodes[v].val = t.agg.Empty()
+	}
+	return v
+}
+
+// Bits возвращает число битов в числах бора
+func (t *BinaryTrie[T]) Bits() int {
+	return t.bits
+}
+
+// Len возвращает число чисел с учётом кратности
+func (t *BinaryTrie[T]) Len() int {
+	return t.nodes[0].count
+}
+
+// Nodes возвращает число узлов арены, включая свободные
+func (t *BinaryTrie[T]) Nodes() int {
+	return len(t.nodes)
+}
+
+func (t *BinaryTrie[T]) check(x int) {
+	if x < 0 || x>>t.bits != 0 {
+		panic("trie: число вне [0, 2^bits)")
+	}
+}
+
+// Add меняет кратность x на k (k < 0 — удаление) за O(bits). Паникует,
+// если кратность стала бы отрицательной
+func (t *BinaryTrie[T]) Add(x, k int) {
+	t.check(x)
+	if k == 0 {
+		return
+	}
+	if k < 0 && t.Count(x) < -k {
+		panic("trie: удаление отсутствующего числа")
+	}
+	// Спуск с созданием узлов; path[i] — узел на глубине i, path[bits] — лист
+	v := int32(0)
+	t.path[0] = 0
+	for i := 1; i <= t.bits; i++ {
+		b := x >> (t.bits - i) & 1
+		if t.nodes[v].child[b] == 0 {
+			c := t.newNode() // nodes может переехать при росте арены
+			t.nodes[v].child[b] = c
+		}
+		v = t.nodes[v].child[b]
+		t.path[i] = v
+	}
+	for _, v := range t.path {
+		t.nodes[v].count += k
+	}
+
+	// Опустевшее поддерево — цепочка по битам x: отцепляем её у самого
+	// верхнего пустого узла и отдаём в список свободных
+	top := t.bits + 1
+	for i := t.bits; i >= 1 && t.nodes[t.path[i]].count == 0; i-- {
+		top = i
+	}
+	if top <= t.bits {
+		t.nodes[t.path[top-1]].child[x>>(t.bits-top)&1] = 0
+		for i := top; i <= t.bits; i++ {
+			t.nodes[t.path[i]].child = [2]int32{t.free, 0}
+			t.free = t.path[i]
+		}
+	}
+
+	if t.agg == nil {
+		return
+	}
+	if top > t.bits {
+		leaf := &t.nodes[t.path[t.bits]]
+		leaf.val = t.agg.Leaf(leaf.count)
+	}
+	for i := min(top, t.bits) - 1; i >= 0; i-- {
+		t.pull(t.path[i], t.bits-1-i)
+	}
+}
+
+// pull пересчитывает агрегат узла v уровня level по детям
+func (t *BinaryTrie[T]) pull(v int32, level int) {
+	zero, one := t.agg.Empty(), t.agg.Empty()
+	if c := t.nodes[v].child[0]; c != 0 {
+		zero = t.nodes[c].val
+	}
+	if c := t.nodes[v].child[1]; c != 0 {
+		one = t.nodes[c].val
+	}
+	t.nodes[v].val = t.agg.Merge(zero, one, level)
+}
+
+// Insert добавляет x
+func (t *BinaryTrie[T]) Insert(x int) {
+	t.Add(x, 1)
+}
+
+// Delete удаляет одно вхождение x и сообщает, было ли оно
+func (t *BinaryTrie[T]) Delete(x int) bool {
+	if t.Count(x) == 0 {
+		return false
+	}
+	t.Add(x, -1)
+	return true
+}
+
+// Count возвращает кратность x
+func (t *BinaryTrie[T]) Count(x int) int {
+	t.check(x)
+	v := int32(0)
+	for i := t.bits - 1; i >= 0; i-- {
+		if v = t.nodes[v].child[x>>i&1]; v == 0 {
+			return 0
+		}
+	}
+	return t.nodes[v].count
+}
+
+// Aggregate возвращает агрегат всего бора; Empty, если бор пуст
+func (t *BinaryTrie[T]) Aggregate() T {
+	return t.nodes[0].val
+}
+
+// MinXor возвращает наименьшее y ⊕ x по числам y бора; бор не пуст
+func (t *BinaryTrie[T]) MinXor(x int) int {
+	return t.extremeXor(x, 0)
+}
+
+// MaxXor возвращает наибольшее y ⊕ x по числам y бора; бор не пуст
+func (t *BinaryTrie[T]) MaxXor(x int) int {
+	return t.extremeXor(x, 1)
+}
+
+// extremeXor спускается, стараясь получить в каждом бите y ⊕ x значение want
+func (t *BinaryTrie[T]) extremeXor(x, want int) int {
+	if t.Len() == 0 {
+		panic("trie: запрос к пустому бору")
+	}
+	v, res := int32(0), 0
+	for i := t.bits - 1; i >= 0; i-- {
+		b := x>>i&1 ^ want
+		if t.nodes[v].child[b] == 0 {
+			b ^= 1
+		}
+		v = t.nodes[v].child[b]
+		res |= (b ^ x>>i&1) << i
+	}
+	return res
+}
+
+// KthXor возвращает k-е по возрастанию (с нуля, с учётом кратности)
+// значение y ⊕ x по числам y бора; 0 ≤ k < Len
+func (t *BinaryTrie[T]) KthXor(x, k int) int {
+	if k < 0 || k >= t.Len() {
+		panic("trie: k вне [0, Len)")
+	}
+	v, res := int32(0), 0
+	for i := t.bits - 1; i >= 0; i-- {
+		b := x >> i & 1 // ребёнок, где бит y ⊕ x равен 0
+		if c := t.nodes[v].child[b]; c != 0 {
+			if k < t.nodes[c].count {
+				v = c
+				continue
+			}
+			k -= t.nodes[c].count
+		}
+		v = t.nodes[v].child[b^1]
+		res |= 1 << i
+	}
+	return res
+}
+
+// CountLessXor возвращает, сколько чисел y бора (с кратностью) дают
+// y ⊕ x < limit
+func (t *BinaryTrie[T]) CountLessXor(x, limit int) int {
+	if limit <= 0 {
+		return 0
+	}
+	if limit>>t.bits != 0 {
+		return t.Len()
+	}
+	v, res := int32(0), 0
+	for i := t.bits - 1; i >= 0; i-- {
+		b := x >> i & 1
+		if limit>>i&1 == 1 {
+			// Все y с битом y ⊕ x = 0 здесь меньше limit
+			if c := t.nodes[v].child[b]; c != 0 {
+				res += t.nodes[c].count
+			}
+			b ^= 1
+		}
+		if v = t.nodes[v].child[b]; v == 0 {
+			break
+		}
+	}
+	return res
+}
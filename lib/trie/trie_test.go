@@ -0,0 +1,240 @@
+package trie
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// countAgg — число чисел с учётом кратности; проверяет, что Leaf получает
+// кратность
+type countAgg struct{}
+
+func (countAgg) Empty() int                     { return 0 }
+func (countAgg) Leaf(mult int) int              { return mult }
+func (countAgg) Merge(zero, one int, _ int) int { return zero + one }
+
+// minAgg — наименьшее число поддерева относительно его начала, −1 у
+// пустого; проверяет, что Merge получает уровень детей
+type minAgg struct{}
+
+func (minAgg) Empty() int   { return -1 }
+func (minAgg) Leaf(int) int { return 0 }
+func (minAgg) Merge(zero, one int, level int) int {
+	switch {
+	case zero >= 0:
+		return zero
+	case one >= 0:
+		return 1<<level + one
+	}
+	return -1
+}
+
+// naive — мультимножество для сравнения
+type naive map[int]int
+
+func (m naive) xors(x int) []int {
+	var res []int
+	for y, c := range m {
+		for range c {
+			res = append(res, y^x)
+		}
+	}
+	slices.Sort(res)
+	return res
+}
+
+func TestAgainstNaive(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, bits := range []int{1, 3, 5, 20, 62} {
+		tr := NewWithAggregate(bits, countAgg{})
+		m := naive{}
+		size := 0
+		value := func() int {
+			if bits > 10 && r.Intn(2) == 0 {
+				return r.Int() & (1<<bits - 1)
+			}
+			return r.Intn(min(1<<bits, 40))
+		}
+		var pool []int // уже вставленные, чтобы чаще удалять существующие
+		for step := range 3000 {
+			x := value()
+			switch op := r.Intn(5); {
+			case op < 2:
+				k := 1 + r.Intn(3)
+				tr.Add(x, k)
+				m[x] += k
+				size += k
+				pool = append(pool, x)
+			case op < 4 && len(pool) > 0:
+				x = pool[r.Intn(len(pool))]
+				fallthrough
+			default:
+				if got, want := tr.Delete(x), m[x] > 0; got != want {
+					t.Fatalf("bits=%d шаг %d: Delete(%d) = %v, ожидалось %v", bits, step, x, got, want)
+				}
+				if m[x] > 0 {
+					m[x]--
+					size--
+				}
+			}
+
+			if tr.Len() != size || tr.Aggregate() != size {
+				t.Fatalf("bits=%d шаг %d: Len = %d, сумма = %d, ожидалось %d", bits, step, tr.Len(), tr.Aggregate(), size)
+			}
+			q := value()
+			if got := tr.Count(q); got != m[q] {
+				t.Fatalf("bits=%d шаг %d: Count(%d) = %d, ожидалось %d", bits, step, q, got, m[q])
+			}
+			if size == 0 {
+				continue
+			}
+			xs := m.xors(q)
+			if got := tr.MinXor(q); got != xs[0] {
+				t.Fatalf("bits=%d шаг %d: MinXor(%d) = %d, ожидалось %d", bits, step, q, got, xs[0])
+			}
+			if got := tr.MaxXor(q); got != xs[size-1] {
+				t.Fatalf("bits=%d шаг %d: MaxXor(%d) = %d, ожидалось %d", bits, step, q, got, xs[size-1])
+			}
+			k := r.Intn(size)
+			if got := tr.KthXor(q, k); got != xs[k] {
+				t.Fatalf("bits=%d шаг %d: KthXor(%d, %d) = %d, ожидалось %d", bits, step, q, k, got, xs[k])
+			}
+			limit := xs[k] + r.Intn(3) - 1
+			want, _ := slices.BinarySearch(xs, limit)
+			if got := tr.CountLessXor(q, limit); got != want {
+				t.Fatalf("bits=%d шаг %d: CountLessXor(%d, %d) = %d, ожидалось %d", bits, step, q, limit, got, want)
+			}
+		}
+	}
+}
+
+func TestMinAggregate(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for _, bits := range []int{1, 2, 4, 6, 62} {
+		tr := NewWithAggregate(bits, minAgg{})
+		m := naive{}
+		// Немного чисел на весь диапазон: удаления попадают в бор
+		pool := make([]int, 8)
+		for i := range pool {
+			pool[i] = int(r.Int63n(1 << bits))
+		}
+		for step := range 2000 {
+			x := pool[r.Intn(len(pool))]
+			if r.Intn(3) == 0 {
+				if tr.Delete(x) {
+					m[x]--
+				}
+			} else {
+				tr.Insert(x)
+				m[x]++
+			}
+			want := -1
+			for y, c := range m {
+				if c > 0 && (want < 0 || y < want) {
+					want = y
+				}
+			}
+			if got := tr.Aggregate(); got != want {
+				t.Fatalf("bits=%d шаг %d: минимум %d, ожидалось %d", bits, step, got, want)
+			}
+		}
+	}
+}
+
+func TestArenaReuse(t *testing.T) {
+	tr := New(30)
+	r := rand.New(rand.NewSource(3))
+	xs := make([]int, 1000)
+	for i := range xs {
+		xs[i] = r.Intn(1 << 30)
+		tr.Insert(xs[i])
+	}
+	nodes := tr.Nodes()
+	// Удалённые цепочки уходят в список свободных, и те же вставки берут
+	// узлы оттуда
+	for range 5 {
+		for _, x := range xs {
+			tr.Delete(x)
+		}
+		if tr.Len() != 0 || tr.Nodes() != nodes {
+			t.Fatalf("после удаления всех: Len = %d, узлов %d, ожидалось 0 и %d", tr.Len(), tr.Nodes(), nodes)
+		}
+		for _, x := range xs {
+			tr.Insert(x)
+		}
+		if tr.Nodes() != nodes {
+			t.Fatalf("арена выросла с %d до %d узлов при тех же числах", nodes, tr.Nodes())
+		}
+	}
+
+	tr.Reset()
+	if tr.Len() != 0 || tr.Nodes() != 1 || tr.Count(xs[0]) != 0 {
+		t.Error("Reset не очистил бор")
+	}
+	allocs := testing.AllocsPerRun(10, func() {
+		tr.Reset()
+		for _, x := range xs {
+			tr.Insert(x)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("после Reset вставки выделяют память: %v раз", allocs)
+	}
+}
+
+func TestPanics(t *testing.T) {
+	for name, f := range map[string]func(){
+		"bits = 0":                func() { New(0) },
+		"bits = 63":               func() { New(63) },
+		"число вне диапазона":     func() { New(3).Insert(8) },
+		"отрицательное число":     func() { New(3).Insert(-1) },
+		"удаление отсутствующего": func() { New(3).Add(5, -1) },
+		"MinXor пустого":          func() { New(3).MinXor(0) },
+		"KthXor с k = Len": func() {
+			tr := New(3)
+			tr.Insert(1)
+			tr.KthXor(0, 1)
+		},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: ожидалась паника", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+func BenchmarkInsertDelete(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	xs := make([]int, 200000)
+	for i := range xs {
+		xs[i] = r.Intn(1 << 30)
+	}
+	tr := NewWithAggregate(30, minAgg{})
+	for b.Loop() {
+		tr.Reset()
+		for _, x := range xs {
+			tr.Insert(x)
+		}
+		for _, x := range xs[:len(xs)/2] {
+			tr.Delete(x)
+		}
+	}
+}
+
+func BenchmarkMaxXor(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	tr := New(30)
+	for range 200000 {
+		tr.Insert(r.Intn(1 << 30))
+	}
+	for b.Loop() {
+		for x := range 1000 {
+			tr.MaxXor(x)
+		}
+	}
+}